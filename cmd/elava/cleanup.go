@@ -0,0 +1,157 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/yairfalse/elava/pkg/cost"
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// runCleanup implements `elava cleanup`: it scans the configured regions
+// once and prints the resources flagged as waste, grouped by category and
+// sorted by estimated monthly savings or by staleness. Returns the process
+// exit code.
+func runCleanup(args []string) int {
+	fs := flag.NewFlagSet("cleanup", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to TOML config file")
+	profile := fs.String("profile", "", "AWS named profile (overrides config)")
+	minSavings := fs.Float64("min-savings", 0, "Hide resources saving less than this many USD per month")
+	sortBy := fs.String("sort", "savings", "Order within each category: savings or staleness")
+	output := fs.String("output", "table", "Output format: table or json")
+	debug := fs.Bool("debug", false, "Enable debug logging")
+	_ = fs.Parse(args)
+
+	setupLogging(*debug)
+
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "cleanup: unknown --output %q (want table or json)\n", *output)
+		return 2
+	}
+	if *sortBy != "savings" && *sortBy != "staleness" {
+		fmt.Fprintf(os.Stderr, "cleanup: unknown --sort %q (want savings or staleness)\n", *sortBy)
+		return 2
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load config")
+		return 2
+	}
+	if *profile != "" {
+		cfg.AWS.Profile = *profile
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	inventory, err := scanAccount(ctx, cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("scan failed")
+		return 2
+	}
+
+	recs := filterRecommendations(cost.Recommendations(inventory, time.Now()), *minSavings)
+	if *sortBy == "staleness" {
+		sortByStaleness(recs)
+	}
+	if *output == "json" {
+		err = writeCleanupJSON(os.Stdout, recs)
+	} else {
+		err = writeCleanup(os.Stdout, recs)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to write recommendations")
+		return 2
+	}
+	return 0
+}
+
+// filterRecommendations drops recommendations saving less than minSavings.
+// Resources of unknown cost are kept only when no minimum is set.
+func filterRecommendations(recs []cost.Recommendation, minSavings float64) []cost.Recommendation {
+	if minSavings <= 0 {
+		return recs
+	}
+	return slices.DeleteFunc(recs, func(r cost.Recommendation) bool {
+		return r.MonthlySavings < minSavings
+	})
+}
+
+// sortByStaleness orders recs by descending staleness score. Ties keep
+// their savings order.
+func sortByStaleness(recs []cost.Recommendation) {
+	slices.SortStableFunc(recs, func(a, b cost.Recommendation) int {
+		return cmp.Compare(b.Staleness, a.Staleness)
+	})
+}
+
+// writeCleanupJSON writes recs as a JSON array in their given order.
+func writeCleanupJSON(w io.Writer, recs []cost.Recommendation) error {
+	if recs == nil {
+		recs = []cost.Recommendation{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(recs)
+}
+
+// writeCleanup prints recs grouped by waste category, the categories with
+// the highest total savings first. Each category keeps the order of recs.
+// ARNs are shortened to their resource part.
+func writeCleanup(w io.Writer, recs []cost.Recommendation) error {
+	if len(recs) == 0 {
+		_, err := fmt.Fprintln(w, "no waste found")
+		return err
+	}
+
+	groups := make(map[string][]cost.Recommendation)
+	totals := make(map[string]float64)
+	var categories []string
+	var total float64
+	for _, r := range recs {
+		if _, ok := groups[r.Category]; !ok {
+			categories = append(categories, r.Category)
+		}
+		groups[r.Category] = append(groups[r.Category], r)
+		totals[r.Category] += r.MonthlySavings
+		total += r.MonthlySavings
+	}
+	slices.SortStableFunc(categories, func(a, b string) int {
+		return cmp.Compare(totals[b], totals[a])
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for i, category := range categories {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintf(tw, "%s: %d, $%.2f/month\n", category, len(groups[category]), totals[category])
+		for _, r := range groups[category] {
+			id := resource.Resource{ID: r.ID}.DisplayID()
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\tstaleness %d\n", id, r.Category, r.Reason, formatSavings(r), r.Staleness)
+		}
+	}
+	fmt.Fprintf(tw, "\ntotal: %d, $%.2f/month\n", len(recs), total)
+	return tw.Flush()
+}
+
+// formatSavings formats the monthly savings of r, or "unknown".
+func formatSavings(r cost.Recommendation) string {
+	if !r.CostKnown {
+		return "unknown"
+	}
+	return fmt.Sprintf("$%.2f", r.MonthlySavings)
+}
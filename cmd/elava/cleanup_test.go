@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/cost"
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func cleanupTestInventory() []resource.Resource {
+	return []resource.Resource{
+		{ID: "vol-1", Type: "ebs", Attrs: map[string]string{"orphaned": "true", "monthly_cost_estimate": "40.00"}},
+		{ID: "eipalloc-1", Type: "eip", Status: "unattached", Attrs: map[string]string{"monthly_cost_estimate": "3.65"}},
+		{ID: "i-1", Type: "ec2", Attrs: map[string]string{"idle": "true", "monthly_cost_estimate": "70.08"}},
+		{ID: "arn:aws:lambda:us-east-1:123456789012:function:fn-1", Type: "lambda", Attrs: map[string]string{"idle": "true"}},
+		{ID: "i-2", Type: "ec2", Attrs: map[string]string{"idle": "false", "monthly_cost_estimate": "70.08"}},
+	}
+}
+
+func TestWriteCleanup(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeCleanup(&out, cost.Recommendations(cleanupTestInventory(), time.Now())))
+
+	assert.Equal(t, "idle: 2, $70.08/month\n"+
+		"  i-1            idle  no activity in the idle window  $70.08   staleness 60\n"+
+		"  function:fn-1  idle  no activity in the idle window  unknown  staleness 60\n"+
+		"\n"+
+		"orphaned: 1, $40.00/month\n"+
+		"  vol-1  orphaned  not attached to or used by anything  $40.00  staleness 60\n"+
+		"\n"+
+		"unattached: 1, $3.65/month\n"+
+		"  eipalloc-1  unattached  not attached  $3.65  staleness 60\n"+
+		"\n"+
+		"total: 4, $113.73/month\n", out.String())
+}
+
+func TestWriteCleanup_Empty(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeCleanup(&out, nil))
+	assert.Equal(t, "no waste found\n", out.String())
+}
+
+func TestFilterRecommendations(t *testing.T) {
+	recs := cost.Recommendations(cleanupTestInventory(), time.Now())
+
+	assert.Len(t, filterRecommendations(recs, 0), 4)
+
+	kept := filterRecommendations(cost.Recommendations(cleanupTestInventory(), time.Now()), 10)
+	require.Len(t, kept, 2)
+	assert.Equal(t, "i-1", kept[0].ID)
+	assert.Equal(t, "vol-1", kept[1].ID)
+}
+
+func TestSortByStaleness(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	inventory := []resource.Resource{
+		{ID: "i-new", Type: "ec2", CreatedAt: now, Labels: map[string]string{"owner": "alice"}, Attrs: map[string]string{"idle": "true", "monthly_cost_estimate": "70.08"}},
+		{ID: "vol-old", Type: "ebs", CreatedAt: now.AddDate(-1, 0, 0), Attrs: map[string]string{"orphaned": "true", "monthly_cost_estimate": "8.00"}},
+		{ID: "vol-mid", Type: "ebs", CreatedAt: now.AddDate(-1, 0, 0), Labels: map[string]string{"owner": "bob"}, Attrs: map[string]string{"orphaned": "true", "monthly_cost_estimate": "40.00"}},
+	}
+
+	recs := cost.Recommendations(inventory, now)
+	sortByStaleness(recs)
+
+	ids := make([]string, len(recs))
+	for i, r := range recs {
+		ids[i] = r.ID
+	}
+	assert.Equal(t, []string{"vol-old", "vol-mid", "i-new"}, ids)
+}
+
+func TestWriteCleanupJSON(t *testing.T) {
+	recs := filterRecommendations(cost.Recommendations(cleanupTestInventory(), time.Now()), 10)
+
+	var out bytes.Buffer
+	require.NoError(t, writeCleanupJSON(&out, recs))
+
+	var got []cost.Recommendation
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, recs, got)
+
+	out.Reset()
+	require.NoError(t, writeCleanupJSON(&out, nil))
+	assert.Equal(t, "[]\n", out.String())
+}
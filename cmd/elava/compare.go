@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/yairfalse/elava/internal/config"
+	"github.com/yairfalse/elava/internal/plugin"
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// runCompare implements `elava compare`: it scans two accounts and reports
+// resources present in one but not the other. Returns the process exit code:
+// 0 when the inventories match, 1 when they differ, 2 on error.
+func runCompare(args []string) int {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to TOML config file")
+	accountA := fs.String("account-a", "", "First account: AWS named profile or role ARN")
+	accountB := fs.String("account-b", "", "Second account: AWS named profile or role ARN")
+	debug := fs.Bool("debug", false, "Enable debug logging")
+	_ = fs.Parse(args)
+
+	setupLogging(*debug)
+
+	if *accountA == "" || *accountB == "" {
+		fmt.Fprintln(os.Stderr, "compare: --account-a and --account-b are required")
+		return 2
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load config")
+		return 2
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	inventoryA, err := scanAccount(ctx, accountConfig(cfg, *accountA))
+	if err != nil {
+		log.Error().Err(err).Str("account", *accountA).Msg("scan failed")
+		return 2
+	}
+	inventoryB, err := scanAccount(ctx, accountConfig(cfg, *accountB))
+	if err != nil {
+		log.Error().Err(err).Str("account", *accountB).Msg("scan failed")
+		return 2
+	}
+
+	onlyA, onlyB := resource.CompareInventories(inventoryA, inventoryB)
+	if err := writeComparison(os.Stdout, *accountA, *accountB, onlyA, onlyB); err != nil {
+		log.Error().Err(err).Msg("failed to write comparison")
+		return 2
+	}
+	if len(onlyA) > 0 || len(onlyB) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// accountConfig returns a copy of cfg that reaches account. A role ARN is
+// assumed after any configured assume_roles; anything else is a profile name.
+func accountConfig(cfg *config.Config, account string) *config.Config {
+	c := *cfg
+	if strings.HasPrefix(account, "arn:") {
+		c.AWS.AssumeRoles = append(slices.Clone(cfg.AWS.AssumeRoles), account)
+	} else {
+		c.AWS.Profile = account
+	}
+	return &c
+}
+
+// scanAccount scans every configured region of one account. Unlike the
+// daemon, any scanner failure fails the scan whatever the failure policy: a
+// partial inventory would be reported as missing resources.
+func scanAccount(ctx context.Context, cfg *config.Config) ([]resource.Resource, error) {
+	awsPlugins, err := newAWSPlugins(ctx, failFastConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	plugins := make([]plugin.Plugin, len(awsPlugins))
+	for i, p := range awsPlugins {
+		plugins[i] = p
+	}
+	return scanInventory(ctx, plugins)
+}
+
+// failFastConfig returns a copy of cfg with the fail-fast failure policy.
+func failFastConfig(cfg *config.Config) *config.Config {
+	c := *cfg
+	c.Scanner.FailurePolicy = config.FailurePolicyFailFast
+	return &c
+}
+
+// scanInventory scans plugins in turn and fails on the first error.
+func scanInventory(ctx context.Context, plugins []plugin.Plugin) ([]resource.Resource, error) {
+	var inventory []resource.Resource
+	for _, p := range plugins {
+		resources, err := p.Scan(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("scan %s: %w", p.Name(), err)
+		}
+		inventory = append(inventory, resources...)
+	}
+	return inventory, nil
+}
+
+// writeComparison prints the resources found only in each account.
+func writeComparison(w io.Writer, accountA, accountB string, onlyA, onlyB []resource.Resource) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	writeSide(tw, accountA, onlyA)
+	fmt.Fprintln(tw)
+	writeSide(tw, accountB, onlyB)
+	return tw.Flush()
+}
+
+func writeSide(w io.Writer, account string, resources []resource.Resource) {
+	fmt.Fprintf(w, "only in %s: %d\n", account, len(resources))
+	for _, r := range resources {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", r.TypeDisplayName(), r.Name, r.Region, r.DisplayID())
+	}
+}
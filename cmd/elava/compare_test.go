@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/internal/config"
+	"github.com/yairfalse/elava/internal/plugin"
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func TestAccountConfig(t *testing.T) {
+	base := &config.Config{AWS: config.AWSConfig{
+		Profile:     "default",
+		AssumeRoles: []string{"arn:aws:iam::111111111111:role/jump"},
+	}}
+
+	byProfile := accountConfig(base, "legacy")
+	assert.Equal(t, "legacy", byProfile.AWS.Profile)
+	assert.Equal(t, base.AWS.AssumeRoles, byProfile.AWS.AssumeRoles)
+
+	byRole := accountConfig(base, "arn:aws:iam::222222222222:role/elava")
+	assert.Equal(t, "default", byRole.AWS.Profile)
+	assert.Equal(t, []string{"arn:aws:iam::111111111111:role/jump", "arn:aws:iam::222222222222:role/elava"}, byRole.AWS.AssumeRoles)
+	assert.Len(t, base.AWS.AssumeRoles, 1, "base config is not modified")
+}
+
+type failingPlugin struct{ mockPlugin }
+
+func (*failingPlugin) Scan(context.Context) ([]resource.Resource, error) {
+	return nil, errors.New("scanner iam_role: AccessDenied")
+}
+
+func TestScanAccount_FailsOnScannerError(t *testing.T) {
+	cfg := &config.Config{Scanner: config.ScannerConfig{FailurePolicy: config.FailurePolicyBestEffort}}
+	assert.Equal(t, config.FailurePolicyFailFast, failFastConfig(cfg).Scanner.FailurePolicy)
+	assert.Equal(t, config.FailurePolicyBestEffort, cfg.Scanner.FailurePolicy, "base config is not modified")
+
+	ok := &mockPlugin{resources: []resource.Resource{{ID: "i-1"}}}
+	_, err := scanInventory(context.Background(), []plugin.Plugin{ok, &failingPlugin{}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AccessDenied")
+
+	inventory, err := scanInventory(context.Background(), []plugin.Plugin{ok})
+	require.NoError(t, err)
+	assert.Len(t, inventory, 1)
+}
+
+func TestWriteComparison(t *testing.T) {
+	onlyA := []resource.Resource{{ID: "i-aaa2", Type: "ec2", Name: "worker", Region: "us-east-1"}}
+	onlyB := []resource.Resource{{ID: "arn:aws:sns:us-east-1:123456789012:alerts", Type: "sns", Name: "alerts", Region: "us-east-1"}}
+
+	var out bytes.Buffer
+	require.NoError(t, writeComparison(&out, "old", "new", onlyA, onlyB))
+
+	assert.Equal(t, "only in old: 1\n"+
+		"  EC2 Instance  worker  us-east-1  i-aaa2\n"+
+		"\n"+
+		"only in new: 1\n"+
+		"  SNS Topic  alerts  us-east-1  alerts\n", out.String())
+}
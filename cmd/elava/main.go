@@ -11,12 +11,20 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/yairfalse/elava/internal/config"
 	"github.com/yairfalse/elava/internal/emitter"
@@ -35,15 +43,40 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "compare" {
+		os.Exit(runCompare(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "cleanup" {
+		os.Exit(runCleanup(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		os.Exit(runReport(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "tag" {
+		os.Exit(runTag(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "rules" {
+		os.Exit(runRules(os.Args[2:]))
+	}
+
+	os.Exit(run())
+}
+
+// run is the scanner itself: one scan in one-shot mode, else a scan every
+// interval until interrupted. Returns the process exit code, non-zero when a
+// one-shot scan was aborted by the fail-fast policy.
+func run() int {
 	configPath := flag.String("config", "", "Path to TOML config file")
 	metricsAddr := flag.String("metrics", ":9090", "Metrics server address")
+	profile := flag.String("profile", "", "AWS named profile (overrides config)")
+	fields := flag.String("fields", "", "Comma-separated export fields, e.g. id,region,tags.Owner (overrides config)")
 	debug := flag.Bool("debug", false, "Enable debug logging")
 	showVersion := flag.Bool("version", false, "Show version and exit")
 	flag.Parse()
 
 	if *showVersion {
 		fmt.Printf("elava %s (commit: %s, built: %s)\n", version, commit, date)
-		return
+		return 0
 	}
 
 	setupLogging(*debug)
@@ -56,6 +89,12 @@ func main() {
 	if *debug {
 		cfg.Log.Level = "debug"
 	}
+	if *profile != "" {
+		cfg.AWS.Profile = *profile
+	}
+	if *fields != "" {
+		cfg.Output.Export.Fields = strings.Split(*fields, ",")
+	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
@@ -73,7 +112,7 @@ func main() {
 		log.Fatal().Err(err).Msg("failed to register plugins")
 	}
 
-	emit, err := emitter.NewPrometheusEmitter()
+	emit, err := buildEmitter(ctx, cfg, tp)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to create emitter")
 	}
@@ -81,19 +120,33 @@ func main() {
 
 	log.Info().
 		Strs("regions", cfg.AWS.Regions).
+		Str("profile", cfg.AWS.Profile).
 		Dur("interval", cfg.Scanner.Interval).
 		Int("max_concurrency", cfg.Scanner.MaxConcurrency).
+		Int("parallel_regions", cfg.Scanner.ParallelRegions).
+		Str("failure_policy", cfg.Scanner.FailurePolicy).
 		Bool("one_shot", cfg.Scanner.OneShot).
 		Msg("elava starting")
 
-	scan(ctx, plugin.All(), emit, tp)
+	opts := scanOptions{
+		PluginTimeout:   cfg.Scanner.PluginTimeout,
+		ParallelRegions: cfg.Scanner.ParallelRegions,
+		FailFast:        cfg.Scanner.FailurePolicy == config.FailurePolicyFailFast,
+	}
+	err = scan(ctx, plugin.All(), emit, tp, opts)
 
 	if cfg.Scanner.OneShot {
 		log.Info().Msg("one-shot mode, exiting")
-		return
+		if err != nil {
+			return 1
+		}
+		return 0
 	}
 
-	runDaemon(ctx, cfg.Scanner.Interval, emit, tp)
+	runDaemon(ctx, cfg.Scanner.Interval, func(ctx context.Context) {
+		_ = scan(ctx, plugin.All(), emit, tp, opts)
+	})
+	return 0
 }
 
 func loadConfig(path string) (*config.Config, error) {
@@ -107,13 +160,7 @@ func loadConfig(path string) (*config.Config, error) {
 		}
 		return cfg, nil
 	}
-	// Default config when no file specified
-	return &config.Config{
-		AWS:     config.AWSConfig{Regions: []string{"us-east-1"}},
-		OTEL:    config.OTELConfig{ServiceName: "elava"},
-		Scanner: config.ScannerConfig{Interval: 5 * time.Minute, MaxConcurrency: 5},
-		Log:     config.LogConfig{Level: "info"},
-	}, nil
+	return config.Default()
 }
 
 func setupLogging(debug bool) {
@@ -187,51 +234,252 @@ func shutdownMetricsServer(srv *http.Server) {
 }
 
 func registerPlugins(ctx context.Context, cfg *config.Config) error {
+	plugins, err := newAWSPlugins(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	for _, p := range plugins {
+		plugin.Register(p)
+	}
+	return nil
+}
+
+// newAWSPlugins creates one AWS plugin per configured region.
+func newAWSPlugins(ctx context.Context, cfg *config.Config) ([]*awsPluginWithRegionName, error) {
 	// Create filter from config
+	aliases := resource.NewTypeAliases(cfg.Scanner.TypeAliases)
 	f := filter.New(
 		cfg.Scanner.ExcludeTypes,
 		cfg.Scanner.IncludeTags,
 		cfg.Scanner.ExcludeTags,
-	)
+	).WithIncludeTypes(cfg.Scanner.IncludeTypes).
+		WithTypeAliases(aliases).
+		WithCreatedWindow(cfg.Scanner.CreatedAfter, cfg.Scanner.CreatedBefore)
+
+	wasteRules, err := newWasteRules(cfg.Scanner.WasteRules, aliases)
+	if err != nil {
+		return nil, err
+	}
 
+	plugins := make([]*awsPluginWithRegionName, 0, len(cfg.AWS.Regions))
 	for i, region := range cfg.AWS.Regions {
 		awsPlugin, err := aws.New(ctx, aws.Config{
 			Region:          region,
+			Profile:         cfg.AWS.Profile,
+			AssumeRoles:     cfg.AWS.AssumeRoles,
 			MaxConcurrency:  cfg.Scanner.MaxConcurrency,
 			Filter:          f,
+			WasteRules:      wasteRules,
 			ScanGlobalTypes: i == 0, // Only first region scans global types (IAM, Route53, CloudFront, S3)
+			FailFast:        cfg.Scanner.FailurePolicy == config.FailurePolicyFailFast,
+			S3Enrich:        cfg.Scanner.S3Enrich,
+			SkipPartial:     cfg.Scanner.SkipPartial,
+			Cost: aws.CostConfig{
+				Enabled:  cfg.Scanner.Cost.Enabled,
+				CacheTTL: cfg.Scanner.Cost.CacheTTL,
+			},
+			Retry: aws.RetryConfig{
+				MaxRetries: cfg.Scanner.MaxRetries,
+				BaseDelay:  cfg.Scanner.RetryBaseDelay,
+				MaxDelay:   cfg.Scanner.RetryMaxDelay,
+			},
+			Breaker: aws.BreakerConfig{
+				Threshold: cfg.Scanner.BreakerThreshold,
+				Cooldown:  cfg.Scanner.BreakerCooldown,
+			},
+			Idle: aws.IdleConfig{
+				Enabled:                  cfg.Scanner.Idle.Enabled,
+				Window:                   cfg.Scanner.Idle.Window,
+				ELBRequestThreshold:      cfg.Scanner.Idle.ELBRequestThreshold,
+				CacheConnectionThreshold: cfg.Scanner.Idle.CacheConnectionThreshold,
+			},
+			Waste: newWasteConfig(cfg.Scanner.Waste),
 		})
 		if err != nil {
-			return err
+			return nil, err
 		}
-		plugin.Register(&awsPluginWithRegionName{Plugin: awsPlugin, Region: region})
+		plugins = append(plugins, &awsPluginWithRegionName{Plugin: awsPlugin, region: region})
+	}
+	return plugins, nil
+}
+
+// newWasteRules converts configured waste rules for the scanners.
+func newWasteRules(cfgs []config.WasteRuleConfig, aliases resource.TypeAliases) (*filter.Rules, error) {
+	rules := make([]filter.Rule, len(cfgs))
+	for i, c := range cfgs {
+		rules[i] = filter.Rule{
+			Name:       c.Name,
+			Type:       c.Type,
+			Tags:       c.Tags,
+			Attrs:      c.Attrs,
+			Waste:      c.Waste,
+			Confidence: c.Confidence,
+		}
+	}
+	return filter.NewRules(rules, aliases)
+}
+
+// newWasteConfig converts configured waste ages from days.
+func newWasteConfig(c config.WasteConfig) aws.WasteConfig {
+	days := func(n int) time.Duration { return time.Duration(n) * 24 * time.Hour }
+	return aws.WasteConfig{
+		SnapshotUnmanagedAge: days(c.SnapshotUnmanagedDays),
+		AMIObsoleteAge:       days(c.AMIObsoleteDays),
+		LambdaStaleAge:       days(c.LambdaStaleDays),
+		IAMRoleUnusedAge:     days(c.IAMRoleUnusedDays),
+		ECRStaleAge:          days(c.ECRStaleDays),
+		UntaggedAge:          days(c.UntaggedDays),
 	}
-	return nil
+}
+
+// newS3Client creates the client for S3 output with the configured profile,
+// in the bucket region or else the first scanned region.
+func newS3Client(ctx context.Context, cfg *config.Config) (*s3.Client, error) {
+	region := cfg.Output.S3.Region
+	if region == "" && len(cfg.AWS.Regions) > 0 {
+		region = cfg.AWS.Regions[0]
+	}
+	opts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(region)}
+	if cfg.AWS.Profile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(cfg.AWS.Profile))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config for s3 output: %w", err)
+	}
+	return s3.NewFromConfig(awsCfg), nil
 }
 
 // awsPluginWithRegionName wraps an AWS plugin and overrides Name() to include the region.
+// It embeds the concrete plugin so optional interfaces such as
+// plugin.StreamingPlugin stay visible through the wrapper.
 type awsPluginWithRegionName struct {
-	plugin.Plugin
-	Region string
+	*aws.Plugin
+	region string
 }
 
 func (p *awsPluginWithRegionName) Name() string {
-	return "aws-" + p.Region
+	return "aws-" + p.region
+}
+
+// Region implements plugin.RegionalPlugin.
+func (p *awsPluginWithRegionName) Region() string {
+	return p.region
+}
+
+// buildEmitter creates the configured emitters, wrapped with attribute redaction.
+func buildEmitter(ctx context.Context, cfg *config.Config, tp *telemetry.Provider) (emitter.Emitter, error) {
+	prom, err := emitter.NewPrometheusEmitter(emitter.PrometheusOptions{
+		DisplayIDs:  cfg.OTEL.Metrics.DisplayIDs,
+		Namespace:   cfg.OTEL.Metrics.Namespace,
+		QuietPeriod: cfg.Scanner.ChangeQuietPeriod,
+		WatchedTags: cfg.Scanner.ChangeTags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	emitters := []emitter.Emitter{prom}
+
+	if cfg.OTEL.Logs.Enabled {
+		emitters = append(emitters, emitter.NewLogEmitter(tp.Logger()))
+	}
+
+	if tf := cfg.Output.Terraform; tf.Enabled {
+		f, err := os.Create(tf.Path)
+		if err != nil {
+			return nil, fmt.Errorf("create terraform output: %w", err)
+		}
+		tfEmit, err := emitter.NewTerraformEmitter(f, emitter.TerraformOptions{NameFormat: tf.NameFormat})
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		emitters = append(emitters, tfEmit)
+	}
+
+	if ex := cfg.Output.Export; ex.Enabled {
+		f, err := os.Create(ex.Path)
+		if err != nil {
+			return nil, fmt.Errorf("create export output: %w", err)
+		}
+		var exEmit emitter.Emitter
+		if ex.Format == emitter.ExportFormatParquet {
+			exEmit, err = emitter.NewParquetEmitter(f, emitter.ParquetOptions{Fields: ex.Fields})
+		} else {
+			exEmit, err = emitter.NewExportEmitter(f, emitter.ExportOptions{Format: ex.Format, Fields: ex.Fields})
+		}
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		emitters = append(emitters, exEmit)
+	}
+
+	if sl := cfg.Output.ScanLog; sl.Enabled {
+		slEmit, err := emitter.NewJSONFileEmitter(sl.Path)
+		if err != nil {
+			return nil, err
+		}
+		emitters = append(emitters, slEmit)
+	}
+
+	if out := cfg.Output.S3; out.Enabled {
+		client, err := newS3Client(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		emitters = append(emitters, emitter.NewS3Emitter(out.Bucket, out.Prefix, client))
+	}
+
+	if wh := cfg.Output.Webhook; wh.Enabled {
+		emitters = append(emitters, emitter.NewWebhookEmitter(wh.URL, emitter.WebhookOptions{
+			MaxAttempts: wh.MaxAttempts,
+			BaseDelay:   wh.BaseDelay,
+			Headers:     wh.Headers,
+		}))
+	}
+
+	var out emitter.Emitter = prom
+	if len(emitters) > 1 {
+		out = emitter.NewMultiEmitter(emitters...)
+	}
+	return emitter.NewRedactingEmitter(out, cfg.Output.RedactAttrs), nil
 }
+
 func closeEmitter(emit io.Closer) {
 	if err := emit.Close(); err != nil {
 		log.Error().Err(err).Msg("emitter close error")
 	}
 }
 
-func runDaemon(ctx context.Context, interval time.Duration, emit emitter.Emitter, tp *telemetry.Provider) {
+// runDaemon runs scanFn on every tick. A tick that fires while the previous
+// scan is still running is skipped, so scans never overlap.
+func runDaemon(ctx context.Context, interval time.Duration, scanFn func(context.Context)) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	var (
+		running atomic.Bool
+		wg      sync.WaitGroup
+	)
+	defer wg.Wait()
+
 	for {
 		select {
 		case <-ticker.C:
-			scan(ctx, plugin.All(), emit, tp)
+			if ctx.Err() != nil {
+				continue // select may pick a pending tick over ctx.Done
+			}
+			if !running.CompareAndSwap(false, true) {
+				log.Warn().Dur("interval", interval).Msg("previous scan still running, skipping tick")
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer running.Store(false)
+				scanFn(ctx)
+			}()
 		case <-ctx.Done():
 			log.Info().Msg("shutting down")
 			return
@@ -239,46 +487,201 @@ func runDaemon(ctx context.Context, interval time.Duration, emit emitter.Emitter
 	}
 }
 
-func scan(ctx context.Context, plugins []plugin.Plugin, emit emitter.Emitter, tp *telemetry.Provider) {
+// errPluginTimeout is returned for a plugin scan that exceeded its timeout.
+var errPluginTimeout = errors.New("plugin scan timed out")
+
+// scanOptions controls a scan run.
+type scanOptions struct {
+	PluginTimeout   time.Duration // per-plugin limit (0 = none)
+	ParallelRegions int           // plugins scanned at once (below 1 = one at a time)
+	FailFast        bool          // abort every plugin on the first failure
+}
+
+// scan runs every plugin, at most opts.ParallelRegions at a time, each in its
+// own span. Plugins emit concurrently, and the batches of streamed scans
+// interleave; emitters tell them apart by emitter.WithScan. With
+// opts.FailFast the first failed plugin cancels the others, plugins not yet
+// started are skipped, and its error is returned; otherwise failures are
+// only logged and scan returns nil.
+func scan(ctx context.Context, plugins []plugin.Plugin, emit emitter.Emitter, tp *telemetry.Provider, opts scanOptions) error {
 	ctx, span := tp.StartSpan(ctx, "scan")
 	defer span.End()
 
 	log.Info().Int("plugins", len(plugins)).Msg("starting scan")
+	span.AddEvent("scan.started", trace.WithAttributes(attribute.Int("plugins", len(plugins))))
+	start := time.Now()
 
+	var total atomic.Int64
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(opts.ParallelRegions, 1))
 	for _, p := range plugins {
-		scanPlugin(ctx, p, emit, tp)
+		g.Go(func() error {
+			if opts.FailFast && gctx.Err() != nil {
+				return nil // aborted before this plugin started
+			}
+			n, err := scanPlugin(gctx, p, emit, tp, opts.PluginTimeout)
+			total.Add(int64(n))
+			if opts.FailFast && err != nil {
+				return fmt.Errorf("%s: %w", p.Name(), err)
+			}
+			return nil
+		})
 	}
+	err := g.Wait()
 
+	span.AddEvent("scan.finished", trace.WithAttributes(
+		attribute.Int("plugins", len(plugins)),
+		attribute.Int64("resources", total.Load()),
+		attribute.Int64("duration_ms", time.Since(start).Milliseconds()),
+	))
+	if err != nil {
+		log.Error().Err(err).Msg("scan aborted by fail-fast policy")
+		return err
+	}
 	log.Info().Msg("scan complete")
+	return nil
 }
 
-func scanPlugin(ctx context.Context, p plugin.Plugin, emit emitter.Emitter, tp *telemetry.Provider) {
+// scanPlugin scans a single plugin and emits the result, returning the
+// resource count. A streaming plugin's resources are passed to the emitters
+// as each batch is scanned, and the scan then ends with a Streamed result
+// that carries its error if it failed, so the emitters drop what they were
+// streamed. A failed scan that was not streamed emits nothing. Either way
+// its error is returned. A timeout of 0 means no limit.
+func scanPlugin(ctx context.Context, p plugin.Plugin, emit emitter.Emitter, tp *telemetry.Provider, timeout time.Duration) (int, error) {
 	ctx, span := tp.StartSpan(ctx, "scan."+p.Name())
 	defer span.End()
 
+	region := plugin.RegionOf(p)
+	var stream *scanStream
+	if _, ok := p.(plugin.StreamingPlugin); ok && emitter.Streams(emit) {
+		stream = &scanStream{
+			ctx:  emitter.WithScan(ctx, p.Name(), region),
+			emit: emit.(emitter.StreamEmitter),
+		}
+	}
+
 	start := time.Now()
-	resources, err := p.Scan(ctx)
+	resources, count, err := scanWithTimeout(ctx, p, stream, timeout)
 	duration := time.Since(start)
 
-	tp.RecordScanDuration(ctx, p.Name(), "", "all", duration)
-
-	if err != nil {
-		tp.RecordError(ctx, p.Name(), "", "all")
-		log.Error().Err(err).Str("plugin", p.Name()).Msg("scan failed")
-		return
-	}
-
-	tp.RecordResourceCount(ctx, p.Name(), "", "all", len(resources))
+	tp.RecordScanDuration(ctx, p.Name(), region, "all", duration)
 
 	result := resource.ScanResult{
 		Provider:  p.Name(),
-		Region:    "",
+		Region:    region,
 		Resources: resources,
 		Duration:  duration,
 		Error:     err,
+		Streamed:  stream != nil,
+	}
+	if stream != nil {
+		if err := stream.close(); err != nil {
+			log.Error().Err(err).Str("plugin", p.Name()).Msg("emit resources failed")
+		}
 	}
 
+	if err != nil {
+		reason := "error"
+		if errors.Is(err, errPluginTimeout) {
+			reason = "timeout"
+		}
+		tp.RecordError(ctx, p.Name(), region, "all", reason)
+		log.Error().Err(err).Str("plugin", p.Name()).Str("reason", reason).Msg("scan failed")
+		if stream != nil {
+			if err := emit.Emit(ctx, result); err != nil {
+				log.Error().Err(err).Str("plugin", p.Name()).Msg("emit failed")
+			}
+		}
+		return 0, err
+	}
+
+	tp.RecordResourceCount(ctx, p.Name(), region, "all", count)
+
 	if err := emit.Emit(ctx, result); err != nil {
 		log.Error().Err(err).Str("plugin", p.Name()).Msg("emit failed")
 	}
+	return count, nil
+}
+
+// scanStream passes a streamed scan's batches to the emitters as they
+// arrive. Once closed it drops further batches, e.g. from a plugin that
+// outlived its timeout.
+type scanStream struct {
+	ctx  context.Context // from emitter.WithScan
+	emit emitter.StreamEmitter
+
+	mu     sync.Mutex
+	closed bool
+	err    error
+}
+
+// write emits batch. The first error is kept and returned by close.
+func (s *scanStream) write(batch []resource.Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	for _, r := range batch {
+		if err := s.emit.EmitResource(s.ctx, r); err != nil && s.err == nil {
+			s.err = err
+		}
+	}
+}
+
+// close waits for a batch being emitted, stops further writes and returns
+// the first emit error.
+func (s *scanStream) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return s.err
+}
+
+// scanWithTimeout runs scanResources, giving up after timeout. The plugin's
+// context is cancelled at the deadline; a plugin that ignores it finishes in
+// the background and its result is dropped.
+func scanWithTimeout(ctx context.Context, p plugin.Plugin, stream *scanStream, timeout time.Duration) ([]resource.Resource, int, error) {
+	if timeout <= 0 {
+		return scanResources(ctx, p, stream)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type scanned struct {
+		resources []resource.Resource
+		count     int
+		err       error
+	}
+	done := make(chan scanned, 1)
+	go func() {
+		resources, count, err := scanResources(ctx, p, stream)
+		done <- scanned{resources, count, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, 0, fmt.Errorf("%w after %v: %w", errPluginTimeout, timeout, r.err)
+		}
+		return r.resources, r.count, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, 0, fmt.Errorf("%w after %v", errPluginTimeout, timeout)
+		}
+		return nil, 0, ctx.Err()
+	}
+}
+
+// scanResources scans p. With a stream, the plugin streams its batches into
+// it and no resources are returned.
+func scanResources(ctx context.Context, p plugin.Plugin, stream *scanStream) ([]resource.Resource, int, error) {
+	if stream == nil {
+		resources, err := p.Scan(ctx)
+		return resources, len(resources), err
+	}
+	count, err := p.(plugin.StreamingPlugin).ScanStream(ctx, stream.write)
+	return nil, count, err
 }
@@ -2,13 +2,23 @@ package main
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
+	"github.com/yairfalse/elava/internal/config"
 	"github.com/yairfalse/elava/internal/plugin"
+	"github.com/yairfalse/elava/internal/telemetry"
 	"github.com/yairfalse/elava/pkg/resource"
 )
 
@@ -55,9 +65,376 @@ func TestHandleReadyz_WithPlugins(t *testing.T) {
 	plugin.Clear()
 }
 
-type mockPlugin struct{}
+type mockPlugin struct {
+	resources []resource.Resource
+}
 
 func (m *mockPlugin) Name() string { return "mock" }
 func (m *mockPlugin) Scan(_ context.Context) ([]resource.Resource, error) {
-	return nil, nil
+	return m.resources, nil
+}
+
+type nopEmitter struct{}
+
+func (nopEmitter) Emit(_ context.Context, _ resource.ScanResult) error { return nil }
+func (nopEmitter) Close() error                                        { return nil }
+
+type streamingPlugin struct {
+	mockPlugin
+	err   error
+	sleep time.Duration // before the last batch
+	gate  chan struct{} // if set, awaited before the last batch
+}
+
+func (m *streamingPlugin) ScanStream(_ context.Context, fn func([]resource.Resource)) (int, error) {
+	for i, r := range m.resources {
+		if i == len(m.resources)-1 {
+			time.Sleep(m.sleep)
+			if m.gate != nil {
+				<-m.gate
+			}
+		}
+		fn([]resource.Resource{r})
+	}
+	return len(m.resources), m.err
+}
+
+type streamRecorder struct {
+	nopEmitter
+	mu       sync.Mutex
+	streamed []string
+	results  []resource.ScanResult
+	onStream func(id string)
+}
+
+func (s *streamRecorder) Emit(_ context.Context, result resource.ScanResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, result)
+	return nil
+}
+
+func (s *streamRecorder) EmitResource(_ context.Context, r resource.Resource) error {
+	s.mu.Lock()
+	s.streamed = append(s.streamed, r.ID)
+	s.mu.Unlock()
+	if s.onStream != nil {
+		s.onStream(r.ID)
+	}
+	return nil
+}
+
+func TestScanPlugin_Streams(t *testing.T) {
+	tp, err := telemetry.NewProvider(context.Background(), config.OTELConfig{ServiceName: "test-elava"})
+	require.NoError(t, err)
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	resources := []resource.Resource{{ID: "i-1"}, {ID: "i-2"}}
+
+	// The plugin only sends its last batch once the first one reached the
+	// emitter, so the scan cannot finish unless emission is incremental.
+	gate := make(chan struct{})
+	rec := &streamRecorder{}
+	rec.onStream = func(id string) {
+		if id != "i-1" {
+			return
+		}
+		rec.mu.Lock()
+		assert.Empty(t, rec.results, "i-1 is emitted before the scan completes")
+		rec.mu.Unlock()
+		close(gate)
+	}
+	p := &streamingPlugin{mockPlugin: mockPlugin{resources: resources}, gate: gate}
+	n, err := scanPlugin(context.Background(), p, rec, tp, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"i-1", "i-2"}, rec.streamed)
+	require.Len(t, rec.results, 1)
+	assert.True(t, rec.results[0].Streamed)
+	assert.NoError(t, rec.results[0].Error)
+	assert.Nil(t, rec.results[0].Resources, "streamed resources are not collected")
+
+	rec = &streamRecorder{}
+	_, err = scanPlugin(context.Background(), &mockPlugin{resources: resources}, rec, tp, 0)
+	require.NoError(t, err)
+	assert.Empty(t, rec.streamed, "non-streaming plugin emits the batch only")
+	require.Len(t, rec.results, 1)
+	assert.False(t, rec.results[0].Streamed)
+}
+
+func TestScanPlugin_FailedStreamEndsWithError(t *testing.T) {
+	tp, err := telemetry.NewProvider(context.Background(), config.OTELConfig{ServiceName: "test-elava"})
+	require.NoError(t, err)
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	resources := []resource.Resource{{ID: "i-1"}, {ID: "i-2"}}
+
+	rec := &streamRecorder{}
+	p := &streamingPlugin{mockPlugin: mockPlugin{resources: resources}, err: errors.New("scanner ec2: AccessDenied")}
+	n, err := scanPlugin(context.Background(), p, rec, tp, 0)
+	require.Error(t, err)
+	assert.Zero(t, n)
+	assert.Equal(t, []string{"i-1", "i-2"}, rec.streamed)
+	require.Len(t, rec.results, 1, "the emitters learn the streamed scan failed")
+	assert.True(t, rec.results[0].Streamed)
+	assert.ErrorContains(t, rec.results[0].Error, "AccessDenied")
+
+	rec = &streamRecorder{}
+	p = &streamingPlugin{mockPlugin: mockPlugin{resources: resources}, sleep: 200 * time.Millisecond}
+	n, err = scanPlugin(context.Background(), p, rec, tp, 20*time.Millisecond)
+	require.ErrorIs(t, err, errPluginTimeout)
+	assert.Zero(t, n)
+	time.Sleep(300 * time.Millisecond) // the abandoned scan streams its last batch
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	assert.Equal(t, []string{"i-1"}, rec.streamed, "nothing is emitted after the timeout")
+	require.Len(t, rec.results, 1)
+	assert.ErrorIs(t, rec.results[0].Error, errPluginTimeout)
+}
+
+func TestAWSPluginWithRegionName_Streams(t *testing.T) {
+	var p plugin.Plugin = &awsPluginWithRegionName{region: "us-east-1"}
+	_, ok := p.(plugin.StreamingPlugin)
+	assert.True(t, ok, "region wrapper must keep ScanStream visible")
+	assert.Equal(t, "aws-us-east-1", p.Name())
+	assert.Equal(t, "us-east-1", plugin.RegionOf(p))
+}
+
+type regionalPlugin struct {
+	mockPlugin
+	region string
+}
+
+func (m *regionalPlugin) Region() string { return m.region }
+
+func TestScanPlugin_RecordsRegion(t *testing.T) {
+	tp, err := telemetry.NewProvider(context.Background(), config.OTELConfig{
+		ServiceName: "test-elava",
+		Metrics:     config.MetricsConfig{Namespace: "region_test"},
+	})
+	require.NoError(t, err)
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	rec := &streamRecorder{}
+	_, err = scanPlugin(context.Background(), &regionalPlugin{mockPlugin{resources: []resource.Resource{{ID: "i-1"}}}, "eu-west-1"}, rec, tp, 0)
+	require.NoError(t, err)
+
+	require.Len(t, rec.results, 1)
+	assert.Equal(t, "eu-west-1", rec.results[0].Region)
+
+	w := httptest.NewRecorder()
+	promhttp.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	var series string
+	for _, line := range strings.Split(w.Body.String(), "\n") {
+		if strings.HasPrefix(line, "region_test_scan_duration_seconds_count{") {
+			series = line
+		}
+	}
+	require.NotEmpty(t, series, "scan duration series not found")
+	assert.Contains(t, series, `region="eu-west-1"`)
+}
+
+type sleepingPlugin struct {
+	mockPlugin
+	sleep time.Duration
+}
+
+func (m *sleepingPlugin) Scan(_ context.Context) ([]resource.Resource, error) {
+	time.Sleep(m.sleep) // ignores ctx like a hung API call
+	return m.resources, nil
+}
+
+func TestScanPlugin_Timeout(t *testing.T) {
+	tp, err := telemetry.NewProvider(context.Background(), config.OTELConfig{
+		ServiceName: "test-elava",
+		Metrics:     config.MetricsConfig{Namespace: "timeout_test"},
+	})
+	require.NoError(t, err)
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	p := &sleepingPlugin{mockPlugin{resources: []resource.Resource{{ID: "i-1"}}}, time.Second}
+	rec := &streamRecorder{}
+
+	start := time.Now()
+	n, err := scanPlugin(context.Background(), p, rec, tp, 20*time.Millisecond)
+
+	require.ErrorIs(t, err, errPluginTimeout)
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "scan must not wait for the hung plugin")
+	assert.Zero(t, n)
+	assert.Empty(t, rec.results, "timed-out scans emit nothing")
+
+	w := httptest.NewRecorder()
+	promhttp.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	var series string
+	for _, line := range strings.Split(w.Body.String(), "\n") {
+		if strings.HasPrefix(line, "timeout_test_scan_errors_total{") {
+			series = line
+		}
+	}
+	require.NotEmpty(t, series, "scan error series not found")
+	assert.Contains(t, series, `reason="timeout"`)
+	assert.True(t, strings.HasSuffix(series, " 1"), series)
+}
+
+func TestScan_Parallel(t *testing.T) {
+	tp, err := telemetry.NewProvider(context.Background(), config.OTELConfig{ServiceName: "test-elava"})
+	require.NoError(t, err)
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	const sleep = 100 * time.Millisecond
+	plugins := make([]plugin.Plugin, 4)
+	for i := range plugins {
+		plugins[i] = &sleepingPlugin{mockPlugin{resources: []resource.Resource{{ID: "i-1"}}}, sleep}
+	}
+
+	start := time.Now()
+	require.NoError(t, scan(context.Background(), plugins, nopEmitter{}, tp, scanOptions{ParallelRegions: 4}))
+
+	assert.Less(t, time.Since(start), 2*sleep, "plugins must be scanned in parallel")
+}
+
+// blockingPlugin scans until its context is cancelled.
+type blockingPlugin struct {
+	mockPlugin
+	started chan struct{}
+}
+
+func (m *blockingPlugin) Scan(ctx context.Context) ([]resource.Resource, error) {
+	close(m.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type countingPlugin struct {
+	mockPlugin
+	calls atomic.Int32
+}
+
+func (m *countingPlugin) Scan(context.Context) ([]resource.Resource, error) {
+	m.calls.Add(1)
+	return m.resources, nil
+}
+
+// failAfter fails once started is closed, so the blocking plugin is running.
+type failAfter struct {
+	mockPlugin
+	started chan struct{}
+}
+
+func (m *failAfter) Scan(context.Context) ([]resource.Resource, error) {
+	<-m.started
+	return nil, errors.New("scanner iam_role: AccessDenied")
+}
+
+func TestScan_FailFastCancelsOtherPlugins(t *testing.T) {
+	tp, err := telemetry.NewProvider(context.Background(), config.OTELConfig{ServiceName: "test-elava"})
+	require.NoError(t, err)
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	started := make(chan struct{})
+	blocked := &blockingPlugin{started: started}
+	later := &countingPlugin{}
+	plugins := []plugin.Plugin{blocked, &failAfter{started: started}, later}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- scan(context.Background(), plugins, nopEmitter{}, tp, scanOptions{ParallelRegions: 2, FailFast: true})
+	}()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "AccessDenied")
+	case <-time.After(5 * time.Second):
+		t.Fatal("fail-fast did not cancel the other plugins")
+	}
+	assert.Zero(t, later.calls.Load(), "plugins not yet started are skipped")
+}
+
+func TestScan_BestEffortKeepsScanning(t *testing.T) {
+	tp, err := telemetry.NewProvider(context.Background(), config.OTELConfig{ServiceName: "test-elava"})
+	require.NoError(t, err)
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	later := &countingPlugin{}
+	plugins := []plugin.Plugin{&failingPlugin{}, later}
+
+	require.NoError(t, scan(context.Background(), plugins, nopEmitter{}, tp, scanOptions{ParallelRegions: 1}))
+	assert.Equal(t, int32(1), later.calls.Load())
+}
+
+func TestScan_LifecycleEvents(t *testing.T) {
+	tp, err := telemetry.NewProvider(context.Background(), config.OTELConfig{ServiceName: "test-elava"})
+	require.NoError(t, err)
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp.RegisterSpanProcessor(recorder)
+
+	p := &mockPlugin{resources: []resource.Resource{{ID: "i-1"}, {ID: "i-2"}}}
+	require.NoError(t, scan(context.Background(), []plugin.Plugin{p}, nopEmitter{}, tp, scanOptions{}))
+
+	var events []string
+	for _, span := range recorder.Ended() {
+		if span.Name() != "scan" {
+			continue
+		}
+		for _, ev := range span.Events() {
+			events = append(events, ev.Name)
+			if ev.Name == "scan.finished" {
+				attrs := make(map[string]int64)
+				for _, kv := range ev.Attributes {
+					attrs[string(kv.Key)] = kv.Value.AsInt64()
+				}
+				assert.Equal(t, int64(1), attrs["plugins"])
+				assert.Equal(t, int64(2), attrs["resources"])
+				assert.Contains(t, attrs, "duration_ms")
+			}
+		}
+	}
+	assert.Equal(t, []string{"scan.started", "scan.finished"}, events)
+}
+
+func TestRunDaemon_SkipsOverlappingTicks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	var calls atomic.Int32
+
+	done := make(chan struct{})
+	go func() {
+		runDaemon(ctx, 5*time.Millisecond, func(ctx context.Context) {
+			calls.Add(1)
+			<-ctx.Done()
+			<-release
+		})
+		close(done)
+	}()
+
+	// Many ticks fire while the first scan is blocked; it only finishes once
+	// it has seen the cancellation, so no tick may start a scan after it.
+	time.Sleep(60 * time.Millisecond)
+	cancel()
+	close(release)
+	<-done
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRunDaemon_RunsEachTick(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+
+	done := make(chan struct{})
+	go func() {
+		runDaemon(ctx, 5*time.Millisecond, func(context.Context) {
+			calls.Add(1)
+		})
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return calls.Load() >= 3 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/yairfalse/elava/pkg/cost"
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// reportKeys are the --group-by values and how each reads a resource.
+var reportKeys = map[string]func(resource.Resource) string{
+	"owner": resource.Resource.Owner,
+	"team":  func(r resource.Resource) string { return r.Label("team") },
+	"environment": func(r resource.Resource) string {
+		return cmp.Or(r.TagEnvironment(), r.NameEnvironment())
+	},
+}
+
+// runReport implements `elava report`: it scans the configured regions once
+// and prints resource counts, type breakdown and monthly cost per owner,
+// team or environment. Returns the process exit code.
+func runReport(args []string) int {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to TOML config file")
+	profile := fs.String("profile", "", "AWS named profile (overrides config)")
+	groupBy := fs.String("group-by", "owner", "Group by owner (falls back to team), team or environment")
+	output := fs.String("output", "table", "Output format: table or json")
+	debug := fs.Bool("debug", false, "Enable debug logging")
+	_ = fs.Parse(args)
+
+	setupLogging(*debug)
+
+	key, ok := reportKeys[*groupBy]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "report: unknown --group-by %q (want owner, team or environment)\n", *groupBy)
+		return 2
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "report: unknown --output %q (want table or json)\n", *output)
+		return 2
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load config")
+		return 2
+	}
+	if *profile != "" {
+		cfg.AWS.Profile = *profile
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	inventory, err := scanAccount(ctx, cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("scan failed")
+		return 2
+	}
+
+	groups := cost.GroupBy(inventory, key)
+	if *output == "json" {
+		err = writeReportJSON(os.Stdout, groups)
+	} else {
+		err = writeReport(os.Stdout, *groupBy, groups)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to write report")
+		return 2
+	}
+	return 0
+}
+
+// writeReportJSON writes groups as a JSON array, highest cost first.
+func writeReportJSON(w io.Writer, groups []cost.Group) error {
+	if groups == nil {
+		groups = []cost.Group{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(groups)
+}
+
+// writeReport prints one row per group. Resources without the key are
+// listed as "(none)".
+func writeReport(w io.Writer, groupBy string, groups []cost.Group) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\tRESOURCES\tMONTHLY COST\tTYPES\n", strings.ToUpper(groupBy))
+	for _, g := range groups {
+		fmt.Fprintf(tw, "%s\t%d\t$%.2f\t%s\n", cmp.Or(g.Key, "(none)"), g.Resources, g.MonthlyCost, formatTypeCounts(g.Types))
+	}
+	return tw.Flush()
+}
+
+// formatTypeCounts lists type counts by friendly name, most common first.
+func formatTypeCounts(types map[string]int) string {
+	names := make([]string, 0, len(types))
+	for t := range types {
+		names = append(names, t)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if c := cmp.Compare(types[b], types[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+
+	parts := make([]string, len(names))
+	for i, t := range names {
+		parts[i] = fmt.Sprintf("%s: %d", resource.Resource{Type: t}.TypeDisplayName(), types[t])
+	}
+	return strings.Join(parts, ", ")
+}
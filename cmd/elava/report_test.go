@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/cost"
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func reportTestInventory() []resource.Resource {
+	return []resource.Resource{
+		{ID: "i-1", Type: "ec2", Labels: map[string]string{"Owner": "alice", "env": "prod"}, Attrs: map[string]string{"monthly_cost_estimate": "70.08"}},
+		{ID: "i-2", Type: "ec2", Labels: map[string]string{"Owner": "alice", "env": "dev"}, Attrs: map[string]string{"monthly_cost_estimate": "70.08"}},
+		{ID: "vol-1", Type: "ebs", Labels: map[string]string{"owner": "alice"}, Attrs: map[string]string{"monthly_cost_estimate": "8.00"}},
+		{ID: "db-1", Type: "rds", Name: "orders-prod", Labels: map[string]string{"team": "payments"}, Attrs: map[string]string{"monthly_cost": "300.00"}},
+		{ID: "fn-1", Type: "lambda"},
+	}
+}
+
+func TestWriteReport_ByOwner(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeReport(&out, "owner", cost.GroupBy(reportTestInventory(), reportKeys["owner"])))
+
+	assert.Equal(t, "OWNER     RESOURCES  MONTHLY COST  TYPES\n"+
+		"payments  1          $300.00       RDS Instance: 1\n"+
+		"alice     3          $148.16       EC2 Instance: 2, EBS Volume: 1\n"+
+		"(none)    1          $0.00         Lambda Function: 1\n", out.String())
+}
+
+func TestReportKeys(t *testing.T) {
+	inventory := reportTestInventory()
+
+	byTeam := cost.GroupBy(inventory, reportKeys["team"])
+	require.Len(t, byTeam, 2)
+	assert.Equal(t, "payments", byTeam[0].Key)
+	assert.Equal(t, 4, byTeam[1].Resources, "everything else has no team tag")
+
+	byEnv := cost.GroupBy(inventory, reportKeys["environment"])
+	counts := make(map[string]int)
+	for _, g := range byEnv {
+		counts[g.Key] = g.Resources
+	}
+	assert.Equal(t, map[string]int{"prod": 2, "dev": 1, "": 2}, counts, "db-1 is prod by name")
+}
+
+func TestWriteReportJSON(t *testing.T) {
+	groups := cost.GroupBy(reportTestInventory(), reportKeys["owner"])
+
+	var out bytes.Buffer
+	require.NoError(t, writeReportJSON(&out, groups))
+
+	var got []cost.Group
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, groups, got)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+
+	"github.com/yairfalse/elava/internal/config"
+)
+
+// ruleFile is a Prometheus rule file.
+type ruleFile struct {
+	Groups []ruleGroup `yaml:"groups"`
+}
+
+type ruleGroup struct {
+	Name  string      `yaml:"name"`
+	Rules []alertRule `yaml:"rules"`
+}
+
+type alertRule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// rulesHeader starts every generated rule file.
+const rulesHeader = "# Generated by `elava rules`. Edit [budgets] in the elava config instead.\n"
+
+// runRules implements `elava rules`: it prints Prometheus alerting rules for
+// the per-owner budgets in the config, using its metrics namespace. Returns
+// the process exit code.
+func runRules(args []string) int {
+	fs := flag.NewFlagSet("rules", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to TOML config file")
+	_ = fs.Parse(args)
+
+	setupLogging(false)
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load config")
+		return 2
+	}
+	if len(cfg.Budgets) == 0 {
+		log.Warn().Msg("no [budgets] configured, writing an empty rule group")
+	}
+
+	if err := writeBudgetRules(os.Stdout, cfg.OTEL.Metrics.Namespace, cfg.Budgets); err != nil {
+		log.Error().Err(err).Msg("failed to write rules")
+		return 2
+	}
+	return 0
+}
+
+// writeBudgetRules writes the budget alerting rules as a rule file.
+func writeBudgetRules(w io.Writer, namespace string, budgets map[string]config.BudgetConfig) error {
+	var buf bytes.Buffer
+	buf.WriteString(rulesHeader)
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(budgetRules(namespace, budgets)); err != nil {
+		return fmt.Errorf("encode rules: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encode rules: %w", err)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// budgetRules builds one alert per owner and limit, comparing the
+// owner_resources and owner_monthly_cost gauges against the budget. Owners
+// are sorted so the output is stable.
+func budgetRules(namespace string, budgets map[string]config.BudgetConfig) ruleFile {
+	if namespace == "" {
+		namespace = config.DefaultMetricsNamespace
+	}
+	owners := make([]string, 0, len(budgets))
+	for owner := range budgets {
+		owners = append(owners, owner)
+	}
+	slices.Sort(owners)
+
+	rules := []alertRule{}
+	for _, owner := range owners {
+		b := budgets[owner]
+		selector := "{owner=" + strconv.Quote(owner) + "}"
+		if b.Resources > 0 {
+			limit := strconv.Itoa(b.Resources)
+			rules = append(rules, alertRule{
+				Alert:  "ElavaOwnerResourceBudgetExceeded",
+				Expr:   namespace + "_owner_resources" + selector + " > " + limit,
+				For:    "30m",
+				Labels: map[string]string{"severity": "warning"},
+				Annotations: map[string]string{
+					"summary":     "Owner over resource budget",
+					"description": `Owner {{ $labels.owner }} has {{ $value | printf "%.0f" }} resources (budget: ` + limit + `).`,
+				},
+			})
+		}
+		if b.MonthlyCost > 0 {
+			limit := strconv.FormatFloat(b.MonthlyCost, 'f', -1, 64)
+			rules = append(rules, alertRule{
+				Alert:  "ElavaOwnerCostBudgetExceeded",
+				Expr:   namespace + "_owner_monthly_cost" + selector + " > " + limit,
+				For:    "30m",
+				Labels: map[string]string{"severity": "warning"},
+				Annotations: map[string]string{
+					"summary":     "Owner over cost budget",
+					"description": `Owner {{ $labels.owner }} costs ${{ $value | printf "%.2f" }} a month (budget: $` + limit + `).`,
+				},
+			})
+		}
+	}
+
+	return ruleFile{Groups: []ruleGroup{{Name: "elava-budgets", Rules: rules}}}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/internal/config"
+)
+
+func TestBudgetRules(t *testing.T) {
+	rules := budgetRules("team_a", map[string]config.BudgetConfig{
+		"platform": {Resources: 200},
+		"data":     {MonthlyCost: 1200.5},
+	})
+
+	require.Len(t, rules.Groups, 1)
+	got := rules.Groups[0].Rules
+	require.Len(t, got, 2, "unset limits get no rule")
+	assert.Equal(t, "ElavaOwnerCostBudgetExceeded", got[0].Alert)
+	assert.Equal(t, `team_a_owner_monthly_cost{owner="data"} > 1200.5`, got[0].Expr)
+	assert.Equal(t, "ElavaOwnerResourceBudgetExceeded", got[1].Alert)
+	assert.Equal(t, `team_a_owner_resources{owner="platform"} > 200`, got[1].Expr)
+}
+
+// The promtool fixtures test the rules generated from budgets.toml, so they
+// must be regenerated whenever the output changes.
+func TestBudgetRules_Fixture(t *testing.T) {
+	cfg, err := loadConfig("../../deployments/prometheus/tests/budgets.toml")
+	require.NoError(t, err)
+	want, err := os.ReadFile("../../deployments/prometheus/tests/budget_rules.yaml")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeBudgetRules(&buf, cfg.OTEL.Metrics.Namespace, cfg.Budgets))
+
+	assert.Equal(t, string(want), buf.String(), "regenerate with the command in budgets.toml")
+}
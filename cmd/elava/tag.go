@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// ec2TagTypes are tagged with `aws ec2 create-tags` by their plain ID.
+var ec2TagTypes = map[string]bool{
+	"ec2":              true,
+	"ebs":              true,
+	"ebs_snapshot":     true,
+	"ami":              true,
+	"eip":              true,
+	"vpc":              true,
+	"subnet":           true,
+	"security_group":   true,
+	"nat_gateway":      true,
+	"internet_gateway": true,
+	"route_table":      true,
+	"vpc_endpoint":     true,
+	"transit_gateway":  true,
+}
+
+// tagAssignment is a tag value chosen for a resource.
+type tagAssignment struct {
+	Resource resource.Resource
+	Value    string
+}
+
+// runTag implements `elava tag`: it scans the configured regions once and
+// writes a shell script of AWS CLI commands that set an ownership tag. Elava
+// stays read-only; the script is reviewed and run by the operator.
+//
+// With --owner and --ids the listed resources get that owner. Otherwise each
+// resource without an ownership tag is prompted for on stderr, reading
+// answers from stdin. Returns the process exit code.
+func runTag(args []string) int {
+	fs := flag.NewFlagSet("tag", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to TOML config file")
+	profile := fs.String("profile", "", "AWS named profile (overrides config)")
+	owner := fs.String("owner", "", "Owner to set on --ids (non-interactive)")
+	ids := fs.String("ids", "", "Comma-separated resource IDs to tag (non-interactive)")
+	key := fs.String("key", "owner", "Tag key to set")
+	debug := fs.Bool("debug", false, "Enable debug logging")
+	_ = fs.Parse(args)
+
+	setupLogging(*debug)
+
+	if (*owner == "") != (*ids == "") {
+		fmt.Fprintln(os.Stderr, "tag: --owner and --ids must be given together")
+		return 2
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load config")
+		return 2
+	}
+	if *profile != "" {
+		cfg.AWS.Profile = *profile
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	inventory, err := scanAccount(ctx, cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("scan failed")
+		return 2
+	}
+
+	var assignments []tagAssignment
+	if *ids != "" {
+		var missing []string
+		assignments, missing = assignByID(inventory, strings.Split(*ids, ","), *owner)
+		for _, id := range missing {
+			fmt.Fprintf(os.Stderr, "tag: %s not found in scan\n", id)
+		}
+	} else {
+		assignments, err = promptOwners(os.Stdin, os.Stderr, untaggedResources(inventory))
+		if err != nil {
+			log.Error().Err(err).Msg("failed to read owners")
+			return 2
+		}
+	}
+
+	if err := writeTagScript(os.Stdout, *key, assignments); err != nil {
+		log.Error().Err(err).Msg("failed to write tag script")
+		return 2
+	}
+	return 0
+}
+
+// assignByID assigns value to the resources with the given IDs and returns
+// the IDs that were not found.
+func assignByID(inventory []resource.Resource, ids []string, value string) (assignments []tagAssignment, missing []string) {
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		i := slices.IndexFunc(inventory, func(r resource.Resource) bool { return r.ID == id })
+		if i < 0 {
+			missing = append(missing, id)
+			continue
+		}
+		assignments = append(assignments, tagAssignment{Resource: inventory[i], Value: value})
+	}
+	return assignments, missing
+}
+
+// untaggedResources returns the resources without an ownership tag, each ID
+// once. Resources whose tags the scan could not read are left out.
+func untaggedResources(inventory []resource.Resource) []resource.Resource {
+	var untagged []resource.Resource
+	seen := make(map[string]bool)
+	for _, r := range inventory {
+		if r.TagsUnknown() || r.HasOwnershipTag() || seen[r.ID] {
+			continue
+		}
+		seen[r.ID] = true
+		untagged = append(untagged, r)
+	}
+	return untagged
+}
+
+// promptOwners asks for an owner for each resource, one line per answer.
+// An empty answer skips the resource; end of input skips the rest.
+func promptOwners(in io.Reader, out io.Writer, resources []resource.Resource) ([]tagAssignment, error) {
+	var assignments []tagAssignment
+	sc := bufio.NewScanner(in)
+	for _, r := range resources {
+		fmt.Fprintf(out, "%s %s (%s, %s) owner [skip]: ", r.TypeDisplayName(), r.ID, r.Name, r.Region)
+		if !sc.Scan() {
+			fmt.Fprintln(out)
+			break
+		}
+		if v := strings.TrimSpace(sc.Text()); v != "" {
+			assignments = append(assignments, tagAssignment{Resource: r, Value: v})
+		}
+	}
+	return assignments, sc.Err()
+}
+
+// writeTagScript writes one AWS CLI command per assignment. Resources whose
+// type cannot be addressed are left as comments.
+func writeTagScript(w io.Writer, key string, assignments []tagAssignment) error {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n# Generated by elava tag. Review before running.\nset -e\n")
+	for _, a := range assignments {
+		cmd, err := tagCommand(a.Resource, key, a.Value)
+		if err != nil {
+			fmt.Fprintf(&b, "# skipped %s %s: %v\n", a.Resource.Type, a.Resource.ID, err)
+			continue
+		}
+		b.WriteString(cmd + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// tagCommand returns the AWS CLI command setting key=value on r. IAM roles
+// are tagged by name and EC2-family resources by ID; anything else with an
+// ARN, as ID or derived from the ID, through the Resource Groups Tagging
+// API. Those regional commands need a real region, so resources in
+// "global" or "unknown" are skipped with an error saying why.
+func tagCommand(r resource.Resource, key, value string) (string, error) {
+	if r.Type == "iam_role" && r.Name != "" {
+		return fmt.Sprintf("aws iam tag-role --role-name %s --tags %s",
+			shellQuote(r.Name), shellQuote("Key="+key+",Value="+value)), nil
+	}
+	if r.Region == "" || r.Region == "global" || r.Region == "unknown" {
+		return "", fmt.Errorf("no region to tag in (%q)", r.Region)
+	}
+	if ec2TagTypes[r.Type] {
+		return fmt.Sprintf("aws ec2 create-tags --region %s --resources %s --tags %s",
+			shellQuote(r.Region), shellQuote(r.ID), shellQuote("Key="+key+",Value="+value)), nil
+	}
+	arn, ok := resourceARN(r)
+	if !ok {
+		return "", errors.New("no taggable ID or ARN")
+	}
+	return fmt.Sprintf("aws resourcegroupstaggingapi tag-resources --region %s --resource-arn-list %s --tags %s",
+		shellQuote(r.Region), shellQuote(arn), shellQuote(key+"="+value)), nil
+}
+
+// resourceARN returns the ARN of r, building it from the ID for types whose
+// scanner reports a name instead.
+func resourceARN(r resource.Resource) (string, bool) {
+	switch {
+	case strings.HasPrefix(r.ID, "arn:"):
+		return r.ID, true
+	case r.Type == "rds" && r.Account != "":
+		return fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", r.Region, r.Account, r.ID), true
+	case r.Type == "s3":
+		return "arn:aws:s3:::" + r.ID, true
+	}
+	return "", false
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func tagTestInventory() []resource.Resource {
+	return []resource.Resource{
+		{ID: "i-1", Type: "ec2", Name: "web", Region: "us-east-1"},
+		{ID: "mydb", Type: "rds", Region: "eu-west-1", Account: "123456789012"},
+		{ID: "arn:aws:lambda:us-east-1:123456789012:function:etl", Type: "lambda", Region: "us-east-1"},
+		{ID: "https://sqs.us-east-1.amazonaws.com/123456789012/jobs", Type: "sqs", Region: "us-east-1"},
+		{ID: "i-2", Type: "ec2", Region: "us-east-1", Labels: map[string]string{"Team": "data"}},
+		{ID: "logs-bucket", Type: "s3", Region: "us-east-1", Attrs: map[string]string{resource.AttrTagsUnknown: "true"}},
+	}
+}
+
+func TestAssignByID(t *testing.T) {
+	assignments, missing := assignByID(tagTestInventory(), []string{"i-1", " mydb", "i-404"}, "alice")
+
+	require.Len(t, assignments, 2)
+	assert.Equal(t, "i-1", assignments[0].Resource.ID)
+	assert.Equal(t, "mydb", assignments[1].Resource.ID)
+	assert.Equal(t, "alice", assignments[1].Value)
+	assert.Equal(t, []string{"i-404"}, missing)
+}
+
+func TestWriteTagScript(t *testing.T) {
+	inventory := tagTestInventory()
+	assignments := []tagAssignment{
+		{Resource: inventory[0], Value: "alice"},
+		{Resource: inventory[1], Value: "bob's team"},
+		{Resource: inventory[2], Value: "carol"},
+		{Resource: inventory[3], Value: "dave"},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, writeTagScript(&out, "owner", assignments))
+
+	assert.Equal(t, "#!/bin/sh\n# Generated by elava tag. Review before running.\nset -e\n"+
+		"aws ec2 create-tags --region 'us-east-1' --resources 'i-1' --tags 'Key=owner,Value=alice'\n"+
+		"aws resourcegroupstaggingapi tag-resources --region 'eu-west-1' --resource-arn-list 'arn:aws:rds:eu-west-1:123456789012:db:mydb' --tags 'owner=bob'\\''s team'\n"+
+		"aws resourcegroupstaggingapi tag-resources --region 'us-east-1' --resource-arn-list 'arn:aws:lambda:us-east-1:123456789012:function:etl' --tags 'owner=carol'\n"+
+		"# skipped sqs https://sqs.us-east-1.amazonaws.com/123456789012/jobs: no taggable ID or ARN\n",
+		out.String())
+}
+
+func TestTagCommand_IAMRole(t *testing.T) {
+	role := resource.Resource{ID: "arn:aws:iam::123456789012:role/etl-runner", Type: "iam_role", Name: "etl-runner", Region: "global"}
+
+	cmd, err := tagCommand(role, "owner", "alice")
+
+	require.NoError(t, err)
+	assert.Equal(t, "aws iam tag-role --role-name 'etl-runner' --tags 'Key=owner,Value=alice'", cmd)
+}
+
+func TestWriteTagScript_SkipsGlobalAndUnknownRegions(t *testing.T) {
+	assignments := []tagAssignment{
+		{Resource: resource.Resource{ID: "arn:aws:cloudfront::123456789012:distribution/E1", Type: "cloudfront", Region: "global"}, Value: "alice"},
+		{Resource: resource.Resource{ID: "lost-bucket", Type: "s3", Region: "unknown"}, Value: "bob"},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, writeTagScript(&out, "owner", assignments))
+
+	assert.Equal(t, "#!/bin/sh\n# Generated by elava tag. Review before running.\nset -e\n"+
+		"# skipped cloudfront arn:aws:cloudfront::123456789012:distribution/E1: no region to tag in (\"global\")\n"+
+		"# skipped s3 lost-bucket: no region to tag in (\"unknown\")\n",
+		out.String())
+}
+
+func TestPromptOwners(t *testing.T) {
+	untagged := untaggedResources(tagTestInventory())
+	require.Len(t, untagged, 4, "i-2 has a team tag, the bucket's tags are unknown")
+
+	var prompts bytes.Buffer
+	assignments, err := promptOwners(strings.NewReader("alice\n\n  carol  \n"), &prompts, untagged)
+
+	require.NoError(t, err)
+	require.Len(t, assignments, 2)
+	assert.Equal(t, "i-1", assignments[0].Resource.ID)
+	assert.Equal(t, "alice", assignments[0].Value)
+	assert.Equal(t, "carol", assignments[1].Value)
+	assert.Contains(t, prompts.String(), "EC2 Instance i-1 (web, us-east-1) owner [skip]: ")
+}
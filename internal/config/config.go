@@ -3,10 +3,14 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 )
 
 // Config is the root configuration structure.
@@ -14,13 +18,23 @@ type Config struct {
 	AWS     AWSConfig     `toml:"aws"`
 	OTEL    OTELConfig    `toml:"otel"`
 	Scanner ScannerConfig `toml:"scanner"`
+	Output  OutputConfig  `toml:"output"`
 	Log     LogConfig     `toml:"log"`
+
+	Budgets map[string]BudgetConfig `toml:"budgets"` // keyed by owner tag value, see `elava rules`
+}
+
+// BudgetConfig caps what one owner may run. Zero means no limit.
+type BudgetConfig struct {
+	Resources   int     `toml:"resources"`    // resource count
+	MonthlyCost float64 `toml:"monthly_cost"` // USD, billed cost else estimate
 }
 
 // AWSConfig holds AWS provider settings.
 type AWSConfig struct {
-	Regions []string `toml:"regions"`
-	Profile string   `toml:"profile"`
+	Regions     []string `toml:"regions"`
+	Profile     string   `toml:"profile"`
+	AssumeRoles []string `toml:"assume_roles"` // role ARNs chained in order, e.g. jump role then target role
 }
 
 // OTELConfig holds OpenTelemetry settings.
@@ -30,6 +44,7 @@ type OTELConfig struct {
 	ServiceName string        `toml:"service_name"`
 	Traces      TracesConfig  `toml:"traces"`
 	Metrics     MetricsConfig `toml:"metrics"`
+	Logs        LogsConfig    `toml:"logs"`
 }
 
 // TracesConfig holds tracing settings.
@@ -40,18 +55,176 @@ type TracesConfig struct {
 
 // MetricsConfig holds metrics settings.
 type MetricsConfig struct {
-	Enabled bool `toml:"enabled"`
+	Enabled     bool              `toml:"enabled"`
+	DisplayIDs  bool              `toml:"display_ids"`  // add a shortened display_id label for ARNs
+	Namespace   string            `toml:"namespace"`    // metric name prefix (default "elava")
+	ConstLabels map[string]string `toml:"const_labels"` // labels added to every series, e.g. instance or account
+}
+
+// LogsConfig holds OTLP log export settings.
+type LogsConfig struct {
+	Enabled bool `toml:"enabled"` // export each scanned resource as a log record
 }
 
+// DefaultMetricsNamespace is the metric name prefix when none is configured.
+const DefaultMetricsNamespace = "elava"
+
+// metricNameRe matches valid Prometheus metric and label names.
+var metricNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 // ScannerConfig holds scanner settings.
 type ScannerConfig struct {
-	IntervalStr    string `toml:"interval"`
-	Interval       time.Duration
-	OneShot        bool              `toml:"one_shot"`
-	MaxConcurrency int               `toml:"max_concurrency"`
-	ExcludeTypes   []string          `toml:"exclude_types"`
-	IncludeTags    map[string]string `toml:"include_tags"`
-	ExcludeTags    map[string]string `toml:"exclude_tags"`
+	IntervalStr     string `toml:"interval"`
+	Interval        time.Duration
+	OneShot         bool              `toml:"one_shot"`
+	MaxConcurrency  int               `toml:"max_concurrency"`
+	ParallelRegions int               `toml:"parallel_regions"` // regions (plugins) scanned at once
+	IncludeTypes    []string          `toml:"include_types"`    // only run these scanners (empty = all)
+	ExcludeTypes    []string          `toml:"exclude_types"`
+	TypeAliases     map[string]string `toml:"type_aliases"` // extra names for types, e.g. vm = "ec2"
+	IncludeTags     map[string]string `toml:"include_tags"`
+	ExcludeTags     map[string]string `toml:"exclude_tags"`
+	FailurePolicy   string            `toml:"failure_policy"`
+	CreatedAfter    time.Time         `toml:"created_after"`  // only resources created on/after (TOML date)
+	CreatedBefore   time.Time         `toml:"created_before"` // only resources created on/before (TOML date)
+	Idle            IdleConfig        `toml:"idle"`
+	Cost            CostConfig        `toml:"cost"`
+	S3Enrich        bool              `toml:"s3_enrich"`    // fetch versioning, encryption, public access block and lifecycle per bucket
+	SkipPartial     bool              `toml:"skip_partial"` // drop resources whose API response lacked required fields
+	WasteRules      []WasteRuleConfig `toml:"waste_rules"`
+	Waste           WasteConfig       `toml:"waste"`
+
+	MaxRetries        int    `toml:"max_retries"` // retries per AWS API call on throttling/transient errors (0 = none)
+	RetryBaseDelayStr string `toml:"retry_base_delay"`
+	RetryBaseDelay    time.Duration
+	RetryMaxDelayStr  string `toml:"retry_max_delay"`
+	RetryMaxDelay     time.Duration
+
+	BreakerThreshold   int    `toml:"breaker_threshold"` // consecutive failures before a scanner is paused (0 = never)
+	BreakerCooldownStr string `toml:"breaker_cooldown"`
+	BreakerCooldown    time.Duration
+
+	ChangeQuietPeriodStr string `toml:"change_quiet_period"` // report a change only once it persists this long (empty = immediately)
+	ChangeQuietPeriod    time.Duration
+	ChangeTags           []string `toml:"change_tags"` // tag keys whose changes are reported (empty = any tag)
+
+	PluginTimeoutStr string `toml:"plugin_timeout"` // abandon a plugin's scan after this long (empty = no limit)
+	PluginTimeout    time.Duration
+}
+
+// Retry defaults for AWS API calls.
+const (
+	DefaultMaxRetries     = 3
+	DefaultRetryBaseDelay = 100 * time.Millisecond
+	DefaultRetryMaxDelay  = 20 * time.Second
+)
+
+// DefaultParallelRegions is how many regions are scanned at once.
+const DefaultParallelRegions = 4
+
+// Circuit breaker defaults for repeatedly failing scanners.
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Minute
+)
+
+// MaxIdleWindow is the longest idle window: with hourly CloudWatch periods,
+// 60 days stays under the 1440 datapoints one request may return.
+const MaxIdleWindow = 60 * 24 * time.Hour
+
+// IdleConfig holds CloudWatch-based idle detection settings.
+type IdleConfig struct {
+	Enabled                  bool   `toml:"enabled"`
+	WindowStr                string `toml:"window"`
+	Window                   time.Duration
+	ELBRequestThreshold      float64 `toml:"elb_request_threshold"`
+	CacheConnectionThreshold float64 `toml:"cache_connection_threshold"`
+}
+
+// WasteRuleConfig defines a custom waste rule. Tags and attrs map keys to
+// conditions: "value", "!=value" or a numeric comparison such as ">500".
+type WasteRuleConfig struct {
+	Name       string            `toml:"name"`
+	Type       string            `toml:"type"`
+	Tags       map[string]string `toml:"tags"`
+	Attrs      map[string]string `toml:"attrs"`
+	Waste      string            `toml:"waste"`      // waste type recorded on matches
+	Confidence float64           `toml:"confidence"` // 0 to 1
+}
+
+// WasteConfig holds the ages after which scanners flag resources, in days.
+// Zero keeps the scanner default.
+type WasteConfig struct {
+	SnapshotUnmanagedDays int `toml:"snapshot_unmanaged_days"` // manual snapshots outside a schedule (default 30)
+	AMIObsoleteDays       int `toml:"ami_obsolete_days"`       // self-owned AMIs (default 90)
+	LambdaStaleDays       int `toml:"lambda_stale_days"`       // functions without a code or config change (default 30)
+	IAMRoleUnusedDays     int `toml:"iam_role_unused_days"`    // roles not assumed (default 90)
+	ECRStaleDays          int `toml:"ecr_stale_days"`          // repositories without a push (default 90)
+	UntaggedDays          int `toml:"untagged_days"`           // resources without an owner, team or project tag (default 3)
+}
+
+// CostConfig holds Cost Explorer resource cost settings.
+type CostConfig struct {
+	Enabled     bool   `toml:"enabled"`
+	CacheTTLStr string `toml:"cache_ttl"` // reuse fetched costs this long; each refresh is a billed request
+	CacheTTL    time.Duration
+}
+
+// Scanner failure policies.
+const (
+	// FailurePolicyBestEffort logs scanner errors and keeps the remaining results.
+	FailurePolicyBestEffort = "best-effort"
+	// FailurePolicyFailFast aborts the whole scan on the first scanner error.
+	FailurePolicyFailFast = "fail-fast"
+)
+
+// OutputConfig holds settings applied to every emitter.
+type OutputConfig struct {
+	RedactAttrs []string        `toml:"redact_attrs"` // attribute keys masked before emitting
+	Terraform   TerraformConfig `toml:"terraform"`
+	Export      ExportConfig    `toml:"export"`
+	ScanLog     ScanLogConfig   `toml:"scan_log"`
+	S3          S3OutputConfig  `toml:"s3"`
+	Webhook     WebhookConfig   `toml:"webhook"`
+}
+
+// TerraformConfig holds Terraform import export settings.
+type TerraformConfig struct {
+	Enabled    bool   `toml:"enabled"`
+	Path       string `toml:"path"`        // file to write import commands to
+	NameFormat string `toml:"name_format"` // text/template for the address name
+}
+
+// ExportConfig holds JSON/CSV/Parquet resource export settings.
+type ExportConfig struct {
+	Enabled bool     `toml:"enabled"`
+	Path    string   `toml:"path"`   // file to write resources to
+	Format  string   `toml:"format"` // "json" (JSON Lines, default), "csv" or "parquet"
+	Fields  []string `toml:"fields"` // projection, e.g. ["id", "region", "tags.Owner"]
+}
+
+// ScanLogConfig holds settings for appending whole scan results as NDJSON.
+type ScanLogConfig struct {
+	Enabled bool   `toml:"enabled"`
+	Path    string `toml:"path"` // file to append one JSON object per scan result to
+}
+
+// S3OutputConfig holds settings for uploading scan snapshots to S3.
+type S3OutputConfig struct {
+	Enabled bool   `toml:"enabled"`
+	Bucket  string `toml:"bucket"`
+	Prefix  string `toml:"prefix"` // key prefix, e.g. "elava/scans"
+	Region  string `toml:"region"` // bucket region (empty = first aws.regions entry)
+}
+
+// WebhookConfig holds settings for posting scan results to a URL.
+type WebhookConfig struct {
+	Enabled      bool              `toml:"enabled"`
+	URL          string            `toml:"url"`
+	Headers      map[string]string `toml:"headers"`      // added to every request, e.g. Authorization
+	MaxAttempts  int               `toml:"max_attempts"` // POSTs per scan result including the first (0 = 3)
+	BaseDelayStr string            `toml:"base_delay"`   // first retry delay, doubled per retry (empty = 500ms)
+	BaseDelay    time.Duration
 }
 
 // LogConfig holds logging settings.
@@ -66,30 +239,108 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("read config file: %w", err)
 	}
 
-	cfg := &Config{}
+	cfg := newConfig()
 	if err := toml.Unmarshal(data, cfg); err != nil {
 		return nil, fmt.Errorf("parse config: %w", err)
 	}
 
+	if err := resolve(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Default returns the configuration used without a config file: us-east-1
+// with every default applied, as Load would for a file naming only that
+// region.
+func Default() (*Config, error) {
+	cfg := newConfig()
+	cfg.AWS.Regions = []string{"us-east-1"}
+	if err := resolve(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// newConfig returns the config that a file is decoded over. Preset so an
+// explicit max_retries = 0 disables retries and breaker_threshold = 0
+// disables the circuit breaker.
+func newConfig() *Config {
+	return &Config{Scanner: ScannerConfig{MaxRetries: DefaultMaxRetries, BreakerThreshold: DefaultBreakerThreshold}}
+}
+
+// resolve applies defaults and parses the duration strings.
+func resolve(cfg *Config) error {
 	applyDefaults(cfg)
 
 	if err := parseInterval(cfg); err != nil {
-		return nil, err
+		return err
 	}
 
-	return cfg, nil
+	if err := parseIdleWindow(cfg); err != nil {
+		return err
+	}
+
+	if err := parseCostCacheTTL(cfg); err != nil {
+		return err
+	}
+
+	if err := parseRetryDelays(cfg); err != nil {
+		return err
+	}
+
+	if err := parseChangeQuietPeriod(cfg); err != nil {
+		return err
+	}
+
+	if err := parsePluginTimeout(cfg); err != nil {
+		return err
+	}
+
+	if err := parseBreakerCooldown(cfg); err != nil {
+		return err
+	}
+
+	if err := parseWebhookBaseDelay(cfg); err != nil {
+		return err
+	}
+	return nil
 }
 
 func applyDefaults(cfg *Config) {
 	if cfg.OTEL.ServiceName == "" {
 		cfg.OTEL.ServiceName = "elava"
 	}
+	if cfg.OTEL.Metrics.Namespace == "" {
+		cfg.OTEL.Metrics.Namespace = DefaultMetricsNamespace
+	}
 	if cfg.Scanner.IntervalStr == "" {
 		cfg.Scanner.IntervalStr = "5m"
 	}
 	if cfg.Scanner.MaxConcurrency == 0 {
 		cfg.Scanner.MaxConcurrency = 5
 	}
+	if cfg.Scanner.ParallelRegions == 0 {
+		cfg.Scanner.ParallelRegions = DefaultParallelRegions
+	}
+	if cfg.Scanner.Idle.WindowStr == "" {
+		cfg.Scanner.Idle.WindowStr = "168h"
+	}
+	if cfg.Scanner.Cost.CacheTTLStr == "" {
+		cfg.Scanner.Cost.CacheTTLStr = "24h"
+	}
+	if cfg.Scanner.RetryBaseDelayStr == "" {
+		cfg.Scanner.RetryBaseDelayStr = DefaultRetryBaseDelay.String()
+	}
+	if cfg.Scanner.RetryMaxDelayStr == "" {
+		cfg.Scanner.RetryMaxDelayStr = DefaultRetryMaxDelay.String()
+	}
+	if cfg.Scanner.BreakerCooldownStr == "" {
+		cfg.Scanner.BreakerCooldownStr = DefaultBreakerCooldown.String()
+	}
+	if cfg.Scanner.FailurePolicy == "" {
+		cfg.Scanner.FailurePolicy = FailurePolicyBestEffort
+	}
 	if cfg.Log.Level == "" {
 		cfg.Log.Level = "info"
 	}
@@ -104,16 +355,234 @@ func parseInterval(cfg *Config) error {
 	return nil
 }
 
+func parseIdleWindow(cfg *Config) error {
+	d, err := time.ParseDuration(cfg.Scanner.Idle.WindowStr)
+	if err != nil {
+		return fmt.Errorf("parse idle window %q: %w", cfg.Scanner.Idle.WindowStr, err)
+	}
+	cfg.Scanner.Idle.Window = d
+	return nil
+}
+
+func parseCostCacheTTL(cfg *Config) error {
+	d, err := time.ParseDuration(cfg.Scanner.Cost.CacheTTLStr)
+	if err != nil {
+		return fmt.Errorf("parse cost cache_ttl %q: %w", cfg.Scanner.Cost.CacheTTLStr, err)
+	}
+	cfg.Scanner.Cost.CacheTTL = d
+	return nil
+}
+
+func parseRetryDelays(cfg *Config) error {
+	base, err := time.ParseDuration(cfg.Scanner.RetryBaseDelayStr)
+	if err != nil {
+		return fmt.Errorf("parse retry_base_delay %q: %w", cfg.Scanner.RetryBaseDelayStr, err)
+	}
+	maxDelay, err := time.ParseDuration(cfg.Scanner.RetryMaxDelayStr)
+	if err != nil {
+		return fmt.Errorf("parse retry_max_delay %q: %w", cfg.Scanner.RetryMaxDelayStr, err)
+	}
+	cfg.Scanner.RetryBaseDelay = base
+	cfg.Scanner.RetryMaxDelay = maxDelay
+	return nil
+}
+
+func parsePluginTimeout(cfg *Config) error {
+	if cfg.Scanner.PluginTimeoutStr == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(cfg.Scanner.PluginTimeoutStr)
+	if err != nil {
+		return fmt.Errorf("parse plugin_timeout %q: %w", cfg.Scanner.PluginTimeoutStr, err)
+	}
+	cfg.Scanner.PluginTimeout = d
+	return nil
+}
+
+func parseChangeQuietPeriod(cfg *Config) error {
+	if cfg.Scanner.ChangeQuietPeriodStr == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(cfg.Scanner.ChangeQuietPeriodStr)
+	if err != nil {
+		return fmt.Errorf("parse change_quiet_period %q: %w", cfg.Scanner.ChangeQuietPeriodStr, err)
+	}
+	cfg.Scanner.ChangeQuietPeriod = d
+	return nil
+}
+
+func parseBreakerCooldown(cfg *Config) error {
+	d, err := time.ParseDuration(cfg.Scanner.BreakerCooldownStr)
+	if err != nil {
+		return fmt.Errorf("parse breaker_cooldown %q: %w", cfg.Scanner.BreakerCooldownStr, err)
+	}
+	cfg.Scanner.BreakerCooldown = d
+	return nil
+}
+
+func parseWebhookBaseDelay(cfg *Config) error {
+	if cfg.Output.Webhook.BaseDelayStr == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(cfg.Output.Webhook.BaseDelayStr)
+	if err != nil {
+		return fmt.Errorf("parse webhook base_delay %q: %w", cfg.Output.Webhook.BaseDelayStr, err)
+	}
+	cfg.Output.Webhook.BaseDelay = d
+	return nil
+}
+
 // Validate checks the configuration is valid.
 func (c *Config) Validate() error {
 	if len(c.AWS.Regions) == 0 {
 		return fmt.Errorf("aws: at least one region required")
 	}
+	for i, role := range c.AWS.AssumeRoles {
+		if !isRoleARN(role) {
+			return fmt.Errorf("aws: assume_roles[%d] %q is not an IAM role ARN", i, role)
+		}
+	}
 	if c.OTEL.Traces.SampleRate < 0.0 || c.OTEL.Traces.SampleRate > 1.0 {
 		return fmt.Errorf("otel: traces.sample_rate must be between 0.0 and 1.0 (got %v)", c.OTEL.Traces.SampleRate)
 	}
+	if c.OTEL.Metrics.Namespace != "" && !metricNameRe.MatchString(c.OTEL.Metrics.Namespace) {
+		return fmt.Errorf("otel: metrics.namespace %q is not a valid metric name prefix", c.OTEL.Metrics.Namespace)
+	}
+	if c.OTEL.Logs.Enabled && c.OTEL.Endpoint == "" {
+		return fmt.Errorf("otel: logs.enabled requires an endpoint")
+	}
+	for k := range c.OTEL.Metrics.ConstLabels {
+		if !metricNameRe.MatchString(k) {
+			return fmt.Errorf("otel: metrics.const_labels key %q is not a valid label name", k)
+		}
+	}
 	if c.Scanner.MaxConcurrency < 1 {
 		return fmt.Errorf("scanner: max_concurrency must be at least 1 (got %d)", c.Scanner.MaxConcurrency)
 	}
+	if c.Scanner.ParallelRegions < 0 {
+		return fmt.Errorf("scanner: parallel_regions must not be negative (got %d)", c.Scanner.ParallelRegions)
+	}
+	if c.Scanner.MaxRetries < 0 {
+		return fmt.Errorf("scanner: max_retries must not be negative (got %d)", c.Scanner.MaxRetries)
+	}
+	if c.Scanner.RetryBaseDelay < 0 {
+		return fmt.Errorf("scanner: retry_base_delay must not be negative (got %v)", c.Scanner.RetryBaseDelay)
+	}
+	if c.Scanner.RetryMaxDelay < 0 {
+		return fmt.Errorf("scanner: retry_max_delay must not be negative (got %v)", c.Scanner.RetryMaxDelay)
+	}
+	if c.Scanner.RetryMaxDelay > 0 && c.Scanner.RetryBaseDelay > c.Scanner.RetryMaxDelay {
+		return fmt.Errorf("scanner: retry_base_delay must not exceed retry_max_delay (got %v > %v)", c.Scanner.RetryBaseDelay, c.Scanner.RetryMaxDelay)
+	}
+	if c.Scanner.BreakerThreshold < 0 {
+		return fmt.Errorf("scanner: breaker_threshold must not be negative (got %d)", c.Scanner.BreakerThreshold)
+	}
+	if c.Scanner.BreakerThreshold > 0 && c.Scanner.BreakerCooldown <= 0 {
+		return fmt.Errorf("scanner: breaker_cooldown must be positive (got %v)", c.Scanner.BreakerCooldown)
+	}
+	if c.Scanner.ChangeQuietPeriod < 0 {
+		return fmt.Errorf("scanner: change_quiet_period must not be negative (got %v)", c.Scanner.ChangeQuietPeriod)
+	}
+	if c.Scanner.PluginTimeout < 0 {
+		return fmt.Errorf("scanner: plugin_timeout must not be negative (got %v)", c.Scanner.PluginTimeout)
+	}
+	if c.Scanner.Idle.Enabled && c.Scanner.Idle.Window <= 0 {
+		return fmt.Errorf("scanner: idle.window must be positive (got %v)", c.Scanner.Idle.Window)
+	}
+	if c.Scanner.Idle.Enabled && c.Scanner.Idle.Window > MaxIdleWindow {
+		return fmt.Errorf("scanner: idle.window must be at most %v (got %v)", MaxIdleWindow, c.Scanner.Idle.Window)
+	}
+	if c.Scanner.Idle.ELBRequestThreshold < 0 {
+		return fmt.Errorf("scanner: idle.elb_request_threshold must not be negative (got %v)", c.Scanner.Idle.ELBRequestThreshold)
+	}
+	for alias, typ := range c.Scanner.TypeAliases {
+		if strings.TrimSpace(alias) == "" || strings.TrimSpace(typ) == "" {
+			return fmt.Errorf("scanner: type_aliases entry %q = %q must name both an alias and a type", alias, typ)
+		}
+	}
+	if err := c.Scanner.Waste.validate(); err != nil {
+		return err
+	}
+	if c.Scanner.Cost.Enabled && c.Scanner.Cost.CacheTTL <= 0 {
+		return fmt.Errorf("scanner: cost.cache_ttl must be positive (got %v)", c.Scanner.Cost.CacheTTL)
+	}
+	if !c.Scanner.CreatedAfter.IsZero() && !c.Scanner.CreatedBefore.IsZero() && c.Scanner.CreatedAfter.After(c.Scanner.CreatedBefore) {
+		return fmt.Errorf("scanner: created_after must not be after created_before (got %s > %s)",
+			c.Scanner.CreatedAfter.Format(time.RFC3339), c.Scanner.CreatedBefore.Format(time.RFC3339))
+	}
+	if c.Scanner.Idle.CacheConnectionThreshold < 0 {
+		return fmt.Errorf("scanner: idle.cache_connection_threshold must not be negative (got %v)", c.Scanner.Idle.CacheConnectionThreshold)
+	}
+	if c.Output.Terraform.Enabled && c.Output.Terraform.Path == "" {
+		return fmt.Errorf("output: terraform.path required when terraform export is enabled")
+	}
+	if c.Output.Export.Enabled && c.Output.Export.Path == "" {
+		return fmt.Errorf("output: export.path required when export is enabled")
+	}
+	if c.Output.ScanLog.Enabled && c.Output.ScanLog.Path == "" {
+		return fmt.Errorf("output: scan_log.path required when scan_log is enabled")
+	}
+	if c.Output.S3.Enabled && c.Output.S3.Bucket == "" {
+		return fmt.Errorf("output: s3.bucket required when s3 output is enabled")
+	}
+	if err := c.Output.Webhook.validate(); err != nil {
+		return err
+	}
+	for owner, b := range c.Budgets {
+		if strings.TrimSpace(owner) == "" {
+			return fmt.Errorf("budgets: owner must not be empty")
+		}
+		if b.Resources < 0 || b.MonthlyCost < 0 {
+			return fmt.Errorf("budgets: %q limits must not be negative (got resources=%d, monthly_cost=%v)", owner, b.Resources, b.MonthlyCost)
+		}
+	}
+	switch c.Scanner.FailurePolicy {
+	case "", FailurePolicyBestEffort, FailurePolicyFailFast:
+	default:
+		return fmt.Errorf("scanner: failure_policy must be %q or %q (got %q)", FailurePolicyBestEffort, FailurePolicyFailFast, c.Scanner.FailurePolicy)
+	}
+	return nil
+}
+
+func (w WasteConfig) validate() error {
+	days := []struct {
+		key string
+		n   int
+	}{
+		{"snapshot_unmanaged_days", w.SnapshotUnmanagedDays},
+		{"ami_obsolete_days", w.AMIObsoleteDays},
+		{"lambda_stale_days", w.LambdaStaleDays},
+		{"iam_role_unused_days", w.IAMRoleUnusedDays},
+		{"ecr_stale_days", w.ECRStaleDays},
+		{"untagged_days", w.UntaggedDays},
+	}
+	for _, d := range days {
+		if d.n < 0 {
+			return fmt.Errorf("scanner: waste.%s must not be negative (got %d)", d.key, d.n)
+		}
+	}
 	return nil
 }
+
+func (w WebhookConfig) validate() error {
+	if !w.Enabled {
+		return nil
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("output: webhook.url must be an http(s) URL (got %q)", w.URL)
+	}
+	if w.MaxAttempts < 0 {
+		return fmt.Errorf("output: webhook.max_attempts must not be negative (got %d)", w.MaxAttempts)
+	}
+	if w.BaseDelay < 0 {
+		return fmt.Errorf("output: webhook.base_delay must not be negative (got %v)", w.BaseDelay)
+	}
+	return nil
+}
+
+// isRoleARN reports whether s is an IAM role ARN.
+func isRoleARN(s string) bool {
+	a, err := arn.Parse(s)
+	return err == nil && a.Service == "iam" && strings.HasPrefix(a.Resource, "role/")
+}
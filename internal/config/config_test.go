@@ -138,6 +138,155 @@ regions = ["us-east-1"]
 
 	require.NoError(t, err)
 	assert.Equal(t, 5, cfg.Scanner.MaxConcurrency)
+	assert.Equal(t, DefaultParallelRegions, cfg.Scanner.ParallelRegions)
+}
+
+func TestLoad_RetryDefaults(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMaxRetries, cfg.Scanner.MaxRetries)
+	assert.Equal(t, DefaultRetryBaseDelay, cfg.Scanner.RetryBaseDelay)
+	assert.Equal(t, DefaultRetryMaxDelay, cfg.Scanner.RetryMaxDelay)
+	require.NoError(t, cfg.Validate())
+}
+
+func TestLoad_RetryConfig(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[scanner]
+max_retries = 0
+retry_base_delay = "250ms"
+retry_max_delay = "5s"
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.Scanner.MaxRetries)
+	assert.Equal(t, 250*time.Millisecond, cfg.Scanner.RetryBaseDelay)
+	assert.Equal(t, 5*time.Second, cfg.Scanner.RetryMaxDelay)
+}
+
+func TestLoad_InvalidRetryDelay(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[scanner]
+retry_base_delay = "soon"
+`
+	path := writeTempConfig(t, content)
+	_, err := Load(path)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "retry_base_delay")
+}
+
+func TestConfig_Validate_AssumeRoles(t *testing.T) {
+	cfg := &Config{
+		AWS: AWSConfig{
+			Regions:     []string{"us-east-1"},
+			AssumeRoles: []string{"arn:aws:iam::111111111111:role/jump", "arn:aws:iam::222222222222:role/path/elava"},
+		},
+		Scanner: ScannerConfig{MaxConcurrency: 5},
+	}
+	require.NoError(t, cfg.Validate())
+
+	cfg.AWS.AssumeRoles = append(cfg.AWS.AssumeRoles, "arn:aws:iam::222222222222:user/bob")
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "assume_roles[2]")
+}
+
+func TestLoad_ChangeQuietPeriod(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[scanner]
+change_quiet_period = "15m"
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, cfg.Scanner.ChangeQuietPeriod)
+}
+
+func TestLoad_PluginTimeout(t *testing.T) {
+	path := writeTempConfig(t, `
+[aws]
+regions = ["us-east-1"]
+
+[scanner]
+plugin_timeout = "10m"
+`)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, cfg.Scanner.PluginTimeout)
+}
+
+func TestLoad_BreakerConfig(t *testing.T) {
+	path := writeTempConfig(t, `
+[aws]
+regions = ["us-east-1"]
+`)
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultBreakerThreshold, cfg.Scanner.BreakerThreshold)
+	assert.Equal(t, DefaultBreakerCooldown, cfg.Scanner.BreakerCooldown)
+
+	path = writeTempConfig(t, `
+[aws]
+regions = ["us-east-1"]
+
+[scanner]
+breaker_threshold = 0
+breaker_cooldown = "2h"
+`)
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.Scanner.BreakerThreshold, "explicit 0 disables the breaker")
+	assert.Equal(t, 2*time.Hour, cfg.Scanner.BreakerCooldown)
+	require.NoError(t, cfg.Validate())
+}
+
+func TestConfig_Validate_ScannerDurations(t *testing.T) {
+	tests := []struct {
+		name    string
+		scanner ScannerConfig
+		wantErr string
+	}{
+		{"negative retries", ScannerConfig{MaxRetries: -1}, "max_retries"},
+		{"negative base delay", ScannerConfig{RetryBaseDelay: -time.Second}, "retry_base_delay"},
+		{"negative max delay", ScannerConfig{RetryMaxDelay: -time.Second}, "retry_max_delay"},
+		{"base above max", ScannerConfig{RetryBaseDelay: time.Minute, RetryMaxDelay: time.Second}, "must not exceed"},
+		{"negative quiet period", ScannerConfig{ChangeQuietPeriod: -time.Minute}, "change_quiet_period"},
+		{"negative parallel regions", ScannerConfig{ParallelRegions: -1}, "parallel_regions"},
+		{"negative plugin timeout", ScannerConfig{PluginTimeout: -time.Minute}, "plugin_timeout"},
+		{"negative breaker threshold", ScannerConfig{BreakerThreshold: -1}, "breaker_threshold"},
+		{"breaker without cooldown", ScannerConfig{BreakerThreshold: 3}, "breaker_cooldown"},
+		{"negative waste age", ScannerConfig{Waste: WasteConfig{LambdaStaleDays: -1}}, "waste.lambda_stale_days"},
+		{"idle window too long", ScannerConfig{Idle: IdleConfig{Enabled: true, Window: 90 * 24 * time.Hour}}, "idle.window must be at most"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.scanner.MaxConcurrency = 5
+			cfg := &Config{AWS: AWSConfig{Regions: []string{"us-east-1"}}, Scanner: tt.scanner}
+			err := cfg.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
 }
 
 func TestLoad_FilterConfig(t *testing.T) {
@@ -190,6 +339,200 @@ func TestConfig_Validate_InvalidMaxConcurrency(t *testing.T) {
 	assert.Contains(t, err.Error(), "max_concurrency")
 }
 
+func TestLoad_FailurePolicy_Default(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, FailurePolicyBestEffort, cfg.Scanner.FailurePolicy)
+}
+
+func TestLoad_FailurePolicy_FailFast(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[scanner]
+failure_policy = "fail-fast"
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, FailurePolicyFailFast, cfg.Scanner.FailurePolicy)
+	require.NoError(t, cfg.Validate())
+}
+
+func TestLoad_WasteRules(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[[scanner.waste_rules]]
+name = "large-dev-gp2"
+type = "ebs"
+tags = { env = "dev" }
+attrs = { type = "gp2", size_gb = ">500" }
+waste = "oversized"
+confidence = 0.8
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	require.Len(t, cfg.Scanner.WasteRules, 1)
+	rule := cfg.Scanner.WasteRules[0]
+	assert.Equal(t, "large-dev-gp2", rule.Name)
+	assert.Equal(t, map[string]string{"env": "dev"}, rule.Tags)
+	assert.Equal(t, ">500", rule.Attrs["size_gb"])
+	assert.Equal(t, "oversized", rule.Waste)
+	assert.InDelta(t, 0.8, rule.Confidence, 1e-9)
+}
+
+func TestLoad_WasteConfig(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[scanner.waste]
+lambda_stale_days = 90
+ami_obsolete_days = 180
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, WasteConfig{LambdaStaleDays: 90, AMIObsoleteDays: 180}, cfg.Scanner.Waste)
+}
+
+func TestConfig_Validate_InvalidFailurePolicy(t *testing.T) {
+	cfg := &Config{
+		AWS:     AWSConfig{Regions: []string{"us-east-1"}},
+		Scanner: ScannerConfig{MaxConcurrency: 5, FailurePolicy: "sometimes"},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failure_policy")
+}
+
+func TestLoad_IdleConfig(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[scanner.idle]
+enabled = true
+window = "72h"
+elb_request_threshold = 10
+cache_connection_threshold = 3
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.True(t, cfg.Scanner.Idle.Enabled)
+	assert.Equal(t, 72*time.Hour, cfg.Scanner.Idle.Window)
+	assert.Equal(t, 10.0, cfg.Scanner.Idle.ELBRequestThreshold)
+	assert.Equal(t, 3.0, cfg.Scanner.Idle.CacheConnectionThreshold)
+}
+
+func TestLoad_IdleConfig_Defaults(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.False(t, cfg.Scanner.Idle.Enabled)
+	assert.Equal(t, 7*24*time.Hour, cfg.Scanner.Idle.Window)
+}
+
+func TestLoad_IdleConfig_InvalidWindow(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[scanner.idle]
+window = "a week"
+`
+	path := writeTempConfig(t, content)
+	_, err := Load(path)
+	require.Error(t, err)
+}
+
+func TestConfig_Validate_LogsWithoutEndpoint(t *testing.T) {
+	cfg, err := Default()
+	require.NoError(t, err)
+	cfg.OTEL.Logs.Enabled = true
+
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "logs.enabled requires an endpoint")
+
+	cfg.OTEL.Endpoint = "localhost:4317"
+	require.NoError(t, cfg.Validate())
+}
+
+func TestLoad_CostConfig(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[scanner.cost]
+enabled = true
+cache_ttl = "6h"
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.True(t, cfg.Scanner.Cost.Enabled)
+	assert.Equal(t, 6*time.Hour, cfg.Scanner.Cost.CacheTTL)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Scanner.Cost.CacheTTL = 0
+	require.ErrorContains(t, cfg.Validate(), "cost.cache_ttl")
+}
+
+func TestLoad_CostConfig_Defaults(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.False(t, cfg.Scanner.Cost.Enabled)
+	assert.Equal(t, 24*time.Hour, cfg.Scanner.Cost.CacheTTL)
+}
+
+func TestLoad_TypeAliases(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[scanner]
+include_types = ["vm"]
+type_aliases = { vm = "ec2" }
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"vm": "ec2"}, cfg.Scanner.TypeAliases)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Scanner.TypeAliases["vm"] = ""
+	require.ErrorContains(t, cfg.Validate(), "type_aliases")
+}
+
 func writeTempConfig(t *testing.T, content string) string {
 	t.Helper()
 	dir := t.TempDir()
@@ -198,3 +541,258 @@ func writeTempConfig(t *testing.T, content string) string {
 	require.NoError(t, err)
 	return path
 }
+
+func TestLoad_CreatedWindow(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[scanner]
+created_after = 2024-01-01
+created_before = 2024-06-30T12:00:00Z
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2024, cfg.Scanner.CreatedAfter.Year())
+	assert.Equal(t, time.January, cfg.Scanner.CreatedAfter.Month())
+	assert.Equal(t, time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC), cfg.Scanner.CreatedBefore)
+	require.NoError(t, cfg.Validate())
+}
+
+func TestLoad_CreatedWindow_DateOnly(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[scanner]
+created_before = 2024-06-30
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	y, m, d := cfg.Scanner.CreatedBefore.Date()
+	assert.Equal(t, []int{2024, 6, 30}, []int{y, int(m), d})
+	h, mi, sec := cfg.Scanner.CreatedBefore.Clock()
+	assert.Equal(t, []int{0, 0, 0}, []int{h, mi, sec}, "a date decodes to midnight, which the filter treats as the whole day")
+}
+
+func TestConfig_Validate_InvalidCreatedWindow(t *testing.T) {
+	cfg := &Config{
+		AWS: AWSConfig{Regions: []string{"us-east-1"}},
+		Scanner: ScannerConfig{
+			MaxConcurrency: 5,
+			CreatedAfter:   time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			CreatedBefore:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "created_after")
+}
+
+func TestLoad_OutputRedactAttrs(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[output]
+redact_attrs = ["private_ip", "endpoint"]
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"private_ip", "endpoint"}, cfg.Output.RedactAttrs)
+}
+
+func TestConfig_Validate_TerraformRequiresPath(t *testing.T) {
+	cfg := &Config{
+		AWS:     AWSConfig{Regions: []string{"us-east-1"}},
+		Scanner: ScannerConfig{MaxConcurrency: 5},
+		Output:  OutputConfig{Terraform: TerraformConfig{Enabled: true}},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "terraform.path")
+}
+
+func TestConfig_Validate_ExportRequiresPath(t *testing.T) {
+	cfg := &Config{
+		AWS:     AWSConfig{Regions: []string{"us-east-1"}},
+		Scanner: ScannerConfig{MaxConcurrency: 5},
+		Output:  OutputConfig{Export: ExportConfig{Enabled: true}},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "export.path")
+}
+
+func TestConfig_Validate_ScanLogRequiresPath(t *testing.T) {
+	cfg := &Config{
+		AWS:     AWSConfig{Regions: []string{"us-east-1"}},
+		Scanner: ScannerConfig{MaxConcurrency: 5},
+		Output:  OutputConfig{ScanLog: ScanLogConfig{Enabled: true}},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "scan_log.path")
+}
+
+func TestConfig_Validate_S3RequiresBucket(t *testing.T) {
+	cfg := &Config{
+		AWS:     AWSConfig{Regions: []string{"us-east-1"}},
+		Scanner: ScannerConfig{MaxConcurrency: 5},
+		Output:  OutputConfig{S3: S3OutputConfig{Enabled: true, Prefix: "scans"}},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "s3.bucket")
+}
+
+func TestLoad_Webhook(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[output.webhook]
+enabled = true
+url = "https://findings.internal/elava"
+max_attempts = 5
+base_delay = "2s"
+
+[output.webhook.headers]
+Authorization = "Bearer abc"
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+	wh := cfg.Output.Webhook
+	assert.Equal(t, "https://findings.internal/elava", wh.URL)
+	assert.Equal(t, 5, wh.MaxAttempts)
+	assert.Equal(t, 2*time.Second, wh.BaseDelay)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer abc"}, wh.Headers)
+}
+
+func TestConfig_Validate_Webhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook WebhookConfig
+		wantErr string
+	}{
+		{"missing url", WebhookConfig{Enabled: true}, "webhook.url"},
+		{"not http", WebhookConfig{Enabled: true, URL: "ftp://host/x"}, "webhook.url"},
+		{"negative attempts", WebhookConfig{Enabled: true, URL: "https://host/x", MaxAttempts: -1}, "webhook.max_attempts"},
+		{"disabled", WebhookConfig{URL: "not a url"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				AWS:     AWSConfig{Regions: []string{"us-east-1"}},
+				Scanner: ScannerConfig{MaxConcurrency: 5},
+				Output:  OutputConfig{Webhook: tt.webhook},
+			}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoad_MetricsNamespace(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[otel.metrics]
+namespace = "team_a"
+
+[otel.metrics.const_labels]
+elava_instance = "scanner-1"
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "team_a", cfg.OTEL.Metrics.Namespace)
+	assert.Equal(t, map[string]string{"elava_instance": "scanner-1"}, cfg.OTEL.Metrics.ConstLabels)
+	require.NoError(t, cfg.Validate())
+}
+
+func TestLoad_MetricsNamespace_Default(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMetricsNamespace, cfg.OTEL.Metrics.Namespace)
+}
+
+func TestConfig_Validate_InvalidMetricNames(t *testing.T) {
+	cfg := &Config{
+		AWS:     AWSConfig{Regions: []string{"us-east-1"}},
+		OTEL:    OTELConfig{Metrics: MetricsConfig{Namespace: "team-a"}},
+		Scanner: ScannerConfig{MaxConcurrency: 5},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metrics.namespace")
+
+	cfg.OTEL.Metrics = MetricsConfig{Namespace: "elava", ConstLabels: map[string]string{"bad-key": "x"}}
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "const_labels")
+}
+
+func TestLoad_Budgets(t *testing.T) {
+	content := `
+[aws]
+regions = ["us-east-1"]
+
+[budgets.platform]
+resources = 200
+monthly_cost = 5000.0
+
+[budgets.data]
+monthly_cost = 1200.0
+`
+	path := writeTempConfig(t, content)
+	cfg, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]BudgetConfig{
+		"platform": {Resources: 200, MonthlyCost: 5000},
+		"data":     {MonthlyCost: 1200},
+	}, cfg.Budgets)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Budgets["data"] = BudgetConfig{MonthlyCost: -1}
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "budgets")
+}
+
+func TestDefault(t *testing.T) {
+	cfg, err := Default()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"us-east-1"}, cfg.AWS.Regions)
+	assert.Equal(t, 5*time.Minute, cfg.Scanner.Interval)
+	assert.Equal(t, 168*time.Hour, cfg.Scanner.Idle.Window)
+	assert.Equal(t, 24*time.Hour, cfg.Scanner.Cost.CacheTTL)
+	assert.Equal(t, DefaultMaxRetries, cfg.Scanner.MaxRetries)
+	assert.Equal(t, DefaultBreakerCooldown, cfg.Scanner.BreakerCooldown)
+	assert.Equal(t, FailurePolicyBestEffort, cfg.Scanner.FailurePolicy)
+	require.NoError(t, cfg.Validate())
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"maps"
 	"sync"
+	"time"
 
 	"github.com/yairfalse/elava/pkg/resource"
 )
@@ -12,35 +13,121 @@ import (
 type DiffTracker struct {
 	mu          sync.RWMutex
 	previous    map[string]resource.Resource
+	hashes      map[string]string // resource key → content hash of previous
 	initialized bool
+
+	// Quiet period: a change is only reported once it has persisted this
+	// long. Until then the previous state is kept as the baseline, so a
+	// change that reverts within the window is never reported.
+	quietPeriod time.Duration
+	pending     map[string]time.Time // resource key → when its change was first seen
+	checkedAt   time.Time            // time of the last ComputeDiff, reused by Update
+	now         func() time.Time
+
+	gone           map[string]time.Time // resource key → when it was found deleted
+	reappearWindow time.Duration        // how long deletions are remembered
+
+	watchedTags []string // tag keys compared for label changes (nil = all labels)
 }
 
+// DefaultReappearWindow is how long a deleted resource is remembered, so its
+// return is reported as reappeared rather than added.
+const DefaultReappearWindow = 72 * time.Hour
+
 // NewDiffTracker creates a new diff tracker.
 func NewDiffTracker() *DiffTracker {
 	return &DiffTracker{
-		previous: make(map[string]resource.Resource),
+		previous:       make(map[string]resource.Resource),
+		hashes:         make(map[string]string),
+		pending:        make(map[string]time.Time),
+		gone:           make(map[string]time.Time),
+		reappearWindow: DefaultReappearWindow,
+		now:            time.Now,
+	}
+}
+
+// WithReappearWindow sets how long deleted resources are remembered. A
+// resource returning later is reported as added. Zero forgets deletions
+// at once.
+func (d *DiffTracker) WithReappearWindow(window time.Duration) *DiffTracker {
+	d.reappearWindow = window
+	return d
+}
+
+// WithQuietPeriod suppresses changes that do not persist for period.
+// Zero reports every change on the scan it is first seen.
+func (d *DiffTracker) WithQuietPeriod(period time.Duration) *DiffTracker {
+	d.quietPeriod = period
+	return d
+}
+
+// WithWatchedTags limits label changes to the given tag keys, matched
+// case-insensitively; each is reported as "tags.<key>". Empty reports any
+// label change as "labels".
+func (d *DiffTracker) WithWatchedTags(keys []string) *DiffTracker {
+	if len(keys) == 0 {
+		keys = nil
 	}
+	d.watchedTags = keys
+	return d
 }
 
 // ComputeDiff compares current resources against previous state.
 // Returns nil on first scan (baseline establishment).
 // Returns empty slice if no changes detected.
+// With a quiet period, changes still inside their window are left out.
 func (d *DiffTracker) ComputeDiff(current []resource.Resource) []resource.ResourceDiff {
-	d.mu.RLock()
-	defer d.mu.RUnlock()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.computeDiff(current, hashResources(current))
+}
+
+// Advance is ComputeDiff followed by Update, hashing current only once.
+func (d *DiffTracker) Advance(current []resource.Resource) []resource.ResourceDiff {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 
+	hashes := hashResources(current)
+	diffs := d.computeDiff(current, hashes)
+	d.update(current, hashes)
+	return diffs
+}
+
+func (d *DiffTracker) computeDiff(current []resource.Resource, hashes map[string]string) []resource.ResourceDiff {
 	if !d.initialized {
 		return nil
 	}
+	d.checkedAt = d.now()
 
 	currentMap := indexResources(current)
 	diffs := make([]resource.ResourceDiff, 0)
-	diffs = append(diffs, d.findDeletedAndModified(currentMap)...)
-	diffs = append(diffs, d.findAdded(currentMap)...)
+	for _, diff := range d.findDeletedAndModified(currentMap, hashes) {
+		if d.settled(resource.ResourceKey(diff.Resource)) {
+			diff.Severity = scoreSeverity(diff)
+			diffs = append(diffs, diff)
+		}
+	}
+	for _, diff := range d.findAdded(currentMap) {
+		if d.settled(resource.ResourceKey(diff.Resource)) {
+			diff.Severity = scoreSeverity(diff)
+			diffs = append(diffs, diff)
+		}
+	}
 
 	return diffs
 }
 
+// settled reports whether the change for key has outlasted the quiet period
+// as of the last ComputeDiff.
+func (d *DiffTracker) settled(key string) bool {
+	if d.quietPeriod <= 0 {
+		return true
+	}
+	since, ok := d.pending[key]
+	return ok && d.checkedAt.Sub(since) >= d.quietPeriod
+}
+
 // indexResources creates a map of resources keyed by their unique identifier.
 func indexResources(resources []resource.Resource) map[string]resource.Resource {
 	m := make(map[string]resource.Resource)
@@ -50,15 +137,35 @@ func indexResources(resources []resource.Resource) map[string]resource.Resource
 	return m
 }
 
-// findDeletedAndModified checks previous resources for deletions and modifications.
-func (d *DiffTracker) findDeletedAndModified(currentMap map[string]resource.Resource) []resource.ResourceDiff {
+// hashResources returns the content hash of each resource by key.
+func hashResources(resources []resource.Resource) map[string]string {
+	m := make(map[string]string, len(resources))
+	for _, r := range resources {
+		m[resource.ResourceKey(r)] = r.ContentHash()
+	}
+	return m
+}
+
+// findDeletedAndModified checks previous resources for deletions and
+// modifications. currentHashes holds the content hash of each current
+// resource.
+func (d *DiffTracker) findDeletedAndModified(currentMap map[string]resource.Resource, currentHashes map[string]string) []resource.ResourceDiff {
 	var diffs []resource.ResourceDiff
 	for key, prev := range d.previous {
 		if curr, exists := currentMap[key]; exists {
-			if changes := detectChanges(prev, curr); len(changes) > 0 {
+			// Identical content hash means nothing changed - skip field comparison
+			if d.hashes[key] == currentHashes[key] {
+				continue
+			}
+			if changes := detectChanges(prev, curr, d.watchedTags); len(changes) > 0 {
+				diffType := resource.DiffModified
+				if ruleChanges := detectRuleChanges(prev, curr); len(ruleChanges) > 0 {
+					diffType = resource.DiffSecurityRuleModified
+					maps.Copy(changes, ruleChanges)
+				}
 				prevCopy := prev
 				diffs = append(diffs, resource.ResourceDiff{
-					Type:     resource.DiffModified,
+					Type:     diffType,
 					Resource: curr,
 					Previous: &prevCopy,
 					Changes:  changes,
@@ -77,35 +184,128 @@ func (d *DiffTracker) findDeletedAndModified(currentMap map[string]resource.Reso
 }
 
 // findAdded checks for new resources not in previous state.
+// Resources deleted in an earlier scan are reported as reappeared.
 func (d *DiffTracker) findAdded(currentMap map[string]resource.Resource) []resource.ResourceDiff {
 	var diffs []resource.ResourceDiff
 	for key, curr := range currentMap {
 		if _, exists := d.previous[key]; !exists {
-			diffs = append(diffs, resource.ResourceDiff{
+			diff := resource.ResourceDiff{
 				Type:     resource.DiffAdded,
 				Resource: curr,
 				Previous: nil,
-			})
+			}
+			if goneAt, ok := d.gone[key]; ok && d.checkTime().Sub(goneAt) <= d.reappearWindow {
+				diff.Type = resource.DiffReappeared
+				diff.Absent = d.checkTime().Sub(goneAt)
+			}
+			diffs = append(diffs, diff)
 		}
 	}
 	return diffs
 }
 
 // Update stores the current resources as the new baseline for future comparisons.
+// With a quiet period, resources whose change has not settled keep their
+// previous baseline entry and stay pending.
 func (d *DiffTracker) Update(current []resource.Resource) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	d.previous = make(map[string]resource.Resource)
+	d.update(current, hashResources(current))
+}
+
+// update stores current as the baseline. currentHashes holds the content
+// hash of each current resource and is kept as the baseline's hashes.
+func (d *DiffTracker) update(current []resource.Resource, currentHashes map[string]string) {
+	var held map[string]bool
+	if d.initialized && d.quietPeriod > 0 {
+		held = d.holdUnsettled(indexResources(current), currentHashes)
+	}
+
+	previous := make(map[string]resource.Resource)
+	hashes := currentHashes
 	for _, r := range current {
-		d.previous[resource.ResourceKey(r)] = r
+		key := resource.ResourceKey(r)
+		if held[key] {
+			continue
+		}
+		previous[key] = r
+	}
+	for key := range held {
+		if prev, ok := d.previous[key]; ok {
+			previous[key] = prev
+			hashes[key] = d.hashes[key]
+		} else {
+			delete(hashes, key)
+		}
+	}
+
+	now := d.checkTime()
+	for key := range d.previous {
+		if _, ok := previous[key]; !ok {
+			d.gone[key] = now
+		}
+	}
+	for key, goneAt := range d.gone {
+		if _, ok := previous[key]; ok || now.Sub(goneAt) > d.reappearWindow {
+			delete(d.gone, key)
+		}
 	}
+
+	d.previous = previous
+	d.hashes = hashes
+	d.checkedAt = time.Time{}
 	d.initialized = true
 }
 
+// checkTime returns the time of the last ComputeDiff, or now if Update is
+// called without one.
+func (d *DiffTracker) checkTime() time.Time {
+	if d.checkedAt.IsZero() {
+		return d.now()
+	}
+	return d.checkedAt
+}
+
+// holdUnsettled updates pending changes against currentMap and returns the
+// keys whose baseline must be kept. Settled and reverted changes are cleared.
+func (d *DiffTracker) holdUnsettled(currentMap map[string]resource.Resource, currentHashes map[string]string) map[string]bool {
+	now := d.checkTime()
+
+	changed := make(map[string]bool)
+	for _, diff := range d.findDeletedAndModified(currentMap, currentHashes) {
+		changed[resource.ResourceKey(diff.Resource)] = true
+	}
+	for _, diff := range d.findAdded(currentMap) {
+		changed[resource.ResourceKey(diff.Resource)] = true
+	}
+
+	held := make(map[string]bool)
+	for key := range changed {
+		since, ok := d.pending[key]
+		switch {
+		case !ok:
+			d.pending[key] = now
+			held[key] = true
+		case now.Sub(since) < d.quietPeriod:
+			held[key] = true
+		default:
+			delete(d.pending, key)
+		}
+	}
+	for key := range d.pending {
+		if !changed[key] {
+			delete(d.pending, key) // reverted within the window
+		}
+	}
+	return held
+}
+
 // detectChanges compares two resources and returns detected field changes.
-// Note: ScannedAt is intentionally excluded as it changes on every scan.
-func detectChanges(prev, curr resource.Resource) map[string]resource.Change {
+// With watchedTags, only those label keys are compared.
+// Note: ScannedAt and volatile attrs such as queue depth are intentionally
+// excluded as they change on every scan.
+func detectChanges(prev, curr resource.Resource, watchedTags []string) map[string]resource.Change {
 	changes := make(map[string]resource.Change)
 
 	if prev.Name != curr.Name {
@@ -122,23 +322,90 @@ func detectChanges(prev, curr resource.Resource) map[string]resource.Change {
 		}
 	}
 
-	if !maps.Equal(prev.Labels, curr.Labels) {
+	if watchedTags != nil {
+		for _, key := range watchedTags {
+			if p, c := prev.Label(key), curr.Label(key); p != c {
+				changes["tags."+key] = resource.Change{Previous: p, Current: c}
+			}
+		}
+	} else if !maps.Equal(prev.Labels, curr.Labels) {
 		changes["labels"] = resource.Change{
 			Previous: mapToJSON(prev.Labels),
 			Current:  mapToJSON(curr.Labels),
 		}
 	}
 
-	if !maps.Equal(prev.Attrs, curr.Attrs) {
+	if prevAttrs, currAttrs := prev.StableAttrs(), curr.StableAttrs(); !maps.Equal(prevAttrs, currAttrs) {
 		changes["attrs"] = resource.Change{
-			Previous: mapToJSON(prev.Attrs),
-			Current:  mapToJSON(curr.Attrs),
+			Previous: mapToJSON(prevAttrs),
+			Current:  mapToJSON(currAttrs),
 		}
 	}
 
 	return changes
 }
 
+// securityRuleAttrs are the security group attrs that describe its rules.
+var securityRuleAttrs = []string{"inbound_rules", "outbound_rules", "has_wide_open"}
+
+// detectRuleChanges returns the rule attrs that differ between two
+// observations of a security group, keyed by attr name. Other resource
+// types never have rule changes.
+func detectRuleChanges(prev, curr resource.Resource) map[string]resource.Change {
+	if curr.Type != "security_group" {
+		return nil
+	}
+	changes := make(map[string]resource.Change)
+	for _, key := range securityRuleAttrs {
+		if prev.Attrs[key] != curr.Attrs[key] {
+			changes[key] = resource.Change{
+				Previous: prev.Attrs[key],
+				Current:  curr.Attrs[key],
+			}
+		}
+	}
+	return changes
+}
+
+// scoreSeverity classifies a diff. A security group becoming wide open or a
+// resource becoming public is critical, an owner or environment tag change
+// a warning, anything else info. Added resources count as transitions from
+// nothing; deletions are info.
+func scoreSeverity(diff resource.ResourceDiff) resource.Severity {
+	if diff.Type == resource.DiffDeleted {
+		return resource.SeverityInfo
+	}
+	var prev resource.Resource
+	if diff.Previous != nil {
+		prev = *diff.Previous
+	}
+	curr := diff.Resource
+
+	if becameTrue(prev.Attrs["public"], curr.Attrs["public"]) ||
+		(curr.Type == "security_group" && becameTrue(prev.Attrs["has_wide_open"], curr.Attrs["has_wide_open"])) {
+		return resource.SeverityCritical
+	}
+	if diff.Previous != nil && (prev.Owner() != curr.Owner() || prev.TagEnvironment() != curr.TagEnvironment()) {
+		return resource.SeverityWarning
+	}
+	return resource.SeverityInfo
+}
+
+func becameTrue(prev, curr string) bool {
+	return curr == "true" && prev != "true"
+}
+
+// FilterDiffs returns the diffs of at least the given severity.
+func FilterDiffs(diffs []resource.ResourceDiff, minSeverity resource.Severity) []resource.ResourceDiff {
+	var filtered []resource.ResourceDiff
+	for _, diff := range diffs {
+		if diff.Severity >= minSeverity {
+			filtered = append(filtered, diff)
+		}
+	}
+	return filtered
+}
+
 // mapToJSON converts a map to a deterministic JSON string for comparison.
 // JSON marshaling sorts keys alphabetically, ensuring consistent output.
 func mapToJSON(m map[string]string) string {
@@ -152,6 +152,22 @@ func TestDiffTracker_LabelsChanged(t *testing.T) {
 	assert.True(t, hasLabelsChange, "should detect label change")
 }
 
+func TestDiffTracker_WatchedTags(t *testing.T) {
+	initial := []resource.Resource{makeResource("i-001", "running", map[string]string{"owner": "alice", "data-classification": "internal"})}
+	updated := []resource.Resource{makeResource("i-001", "running", map[string]string{"owner": "alice", "data-classification": "restricted"})}
+
+	tracker := NewDiffTracker().WithWatchedTags([]string{"owner", "environment"})
+	tracker.Update(initial)
+	assert.Empty(t, tracker.ComputeDiff(updated), "data-classification is not watched")
+
+	tracker = NewDiffTracker().WithWatchedTags([]string{"owner", "Data-Classification"})
+	tracker.Update(initial)
+	diffs := tracker.ComputeDiff(updated)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, resource.Change{Previous: "internal", Current: "restricted"}, diffs[0].Changes["tags.Data-Classification"])
+	assert.NotContains(t, diffs[0].Changes, "labels")
+}
+
 func TestDiffTracker_MultipleChanges(t *testing.T) {
 	tracker := NewDiffTracker()
 
@@ -226,3 +242,271 @@ func TestMapToJSON_NilMap(t *testing.T) {
 	result := mapToJSON(nil)
 	assert.Equal(t, "{}", result)
 }
+
+func TestDiffTracker_HashShortCircuit(t *testing.T) {
+	tracker := NewDiffTracker()
+
+	initial := []resource.Resource{
+		makeResource("i-001", "running", map[string]string{"env": "prod", "team": "web"}),
+	}
+	tracker.Update(initial)
+
+	// Same content, different ScannedAt and map insertion order
+	r := makeResource("i-001", "running", map[string]string{"team": "web", "env": "prod"})
+	r.ScannedAt = r.ScannedAt.Add(time.Hour)
+	assert.Equal(t, tracker.hashes[resource.ResourceKey(r)], r.ContentHash())
+
+	diffs := tracker.ComputeDiff([]resource.Resource{r})
+	assert.Empty(t, diffs)
+}
+
+func TestDiffTracker_AttrsChanged(t *testing.T) {
+	tracker := NewDiffTracker()
+
+	initial := makeResource("i-001", "running", nil)
+	initial.Attrs["instance_type"] = "t3.micro"
+	tracker.Update([]resource.Resource{initial})
+
+	updated := makeResource("i-001", "running", nil)
+	updated.Attrs["instance_type"] = "t3.large"
+	diffs := tracker.ComputeDiff([]resource.Resource{updated})
+
+	require.Len(t, diffs, 1)
+	assert.Equal(t, resource.DiffModified, diffs[0].Type)
+	_, hasAttrsChange := diffs[0].Changes["attrs"]
+	assert.True(t, hasAttrsChange, "should detect attrs change")
+}
+
+func TestDiffTracker_IgnoresVolatileAttrs(t *testing.T) {
+	tracker := NewDiffTracker()
+
+	queue := func(available, inFlight string) resource.Resource {
+		r := makeResource("orders", "active", nil)
+		r.Type = "sqs"
+		r.Attrs["messages_available"] = available
+		r.Attrs["messages_in_flight"] = inFlight
+		r.Attrs["is_dlq"] = "false"
+		return r
+	}
+	tracker.Update([]resource.Resource{queue("3", "0")})
+
+	diffs := tracker.ComputeDiff([]resource.Resource{queue("1200", "45")})
+	assert.Empty(t, diffs, "queue depth is not a change")
+
+	// Compared field by field too, as when another field changed
+	changes := detectChanges(queue("3", "0"), queue("1200", "45"), nil)
+	assert.Empty(t, changes)
+
+	moved := queue("1200", "45")
+	moved.Attrs["is_dlq"] = "true"
+	changes = detectChanges(queue("3", "0"), moved, nil)
+	require.Contains(t, changes, "attrs")
+	assert.NotContains(t, changes["attrs"].Current, "messages_available")
+}
+
+func TestDiffTracker_SecurityRulesChanged(t *testing.T) {
+	tracker := NewDiffTracker()
+
+	initial := makeResource("sg-001", "active", nil)
+	initial.Type = "security_group"
+	initial.Attrs["inbound_rules"] = "2"
+	initial.Attrs["outbound_rules"] = "1"
+	initial.Attrs["has_wide_open"] = "false"
+	tracker.Update([]resource.Resource{initial})
+
+	updated := makeResource("sg-001", "active", nil)
+	updated.Type = "security_group"
+	updated.Attrs["inbound_rules"] = "3"
+	updated.Attrs["outbound_rules"] = "1"
+	updated.Attrs["has_wide_open"] = "true"
+	diffs := tracker.ComputeDiff([]resource.Resource{updated})
+
+	require.Len(t, diffs, 1)
+	assert.Equal(t, resource.DiffSecurityRuleModified, diffs[0].Type)
+	assert.Equal(t, resource.Change{Previous: "2", Current: "3"}, diffs[0].Changes["inbound_rules"])
+	assert.Equal(t, resource.Change{Previous: "false", Current: "true"}, diffs[0].Changes["has_wide_open"])
+	assert.NotContains(t, diffs[0].Changes, "outbound_rules")
+	assert.Contains(t, diffs[0].Changes, "attrs")
+	assert.Equal(t, resource.SeverityCritical, diffs[0].Severity, "opened to the internet")
+}
+
+func TestScoreSeverity(t *testing.T) {
+	private := makeResource("b-1", "active", map[string]string{"owner": "alice"})
+	private.Attrs["public"] = "false"
+
+	public := makeResource("b-1", "active", map[string]string{"owner": "alice"})
+	public.Attrs["public"] = "true"
+
+	reowned := makeResource("b-1", "active", map[string]string{"owner": "bob"})
+	reowned.Attrs["public"] = "false"
+
+	renamed := makeResource("b-1", "active", map[string]string{"owner": "alice"})
+	renamed.Attrs["public"] = "false"
+	renamed.Name = "renamed"
+
+	tests := []struct {
+		name string
+		diff resource.ResourceDiff
+		want resource.Severity
+	}{
+		{"made public", resource.ResourceDiff{Type: resource.DiffModified, Resource: public, Previous: &private}, resource.SeverityCritical},
+		{"still public", resource.ResourceDiff{Type: resource.DiffModified, Resource: public, Previous: &public}, resource.SeverityInfo},
+		{"added public", resource.ResourceDiff{Type: resource.DiffAdded, Resource: public}, resource.SeverityCritical},
+		{"owner changed", resource.ResourceDiff{Type: resource.DiffModified, Resource: reowned, Previous: &private}, resource.SeverityWarning},
+		{"renamed", resource.ResourceDiff{Type: resource.DiffModified, Resource: renamed, Previous: &private}, resource.SeverityInfo},
+		{"deleted public", resource.ResourceDiff{Type: resource.DiffDeleted, Resource: public, Previous: &public}, resource.SeverityInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, scoreSeverity(tt.diff))
+		})
+	}
+}
+
+func TestFilterDiffs(t *testing.T) {
+	diffs := []resource.ResourceDiff{
+		{Resource: resource.Resource{ID: "a"}, Severity: resource.SeverityInfo},
+		{Resource: resource.Resource{ID: "b"}, Severity: resource.SeverityCritical},
+		{Resource: resource.Resource{ID: "c"}, Severity: resource.SeverityWarning},
+	}
+
+	filtered := FilterDiffs(diffs, resource.SeverityWarning)
+
+	require.Len(t, filtered, 2)
+	assert.Equal(t, "b", filtered[0].Resource.ID)
+	assert.Equal(t, "c", filtered[1].Resource.ID)
+	assert.Len(t, FilterDiffs(diffs, resource.SeverityInfo), 3)
+}
+
+func TestDiffTracker_RuleAttrsOnlyForSecurityGroups(t *testing.T) {
+	tracker := NewDiffTracker()
+
+	initial := makeResource("i-001", "running", nil)
+	initial.Attrs["inbound_rules"] = "2"
+	tracker.Update([]resource.Resource{initial})
+
+	updated := makeResource("i-001", "running", nil)
+	updated.Attrs["inbound_rules"] = "3"
+	diffs := tracker.ComputeDiff([]resource.Resource{updated})
+
+	require.Len(t, diffs, 1)
+	assert.Equal(t, resource.DiffModified, diffs[0].Type)
+	assert.NotContains(t, diffs[0].Changes, "inbound_rules")
+}
+
+// quietTracker returns a tracker with a quiet period and a settable clock.
+func quietTracker(period time.Duration, now *time.Time) *DiffTracker {
+	tracker := NewDiffTracker().WithQuietPeriod(period)
+	tracker.now = func() time.Time { return *now }
+	return tracker
+}
+
+// scan runs one Advance, as the Prometheus emitter does.
+func scan(tracker *DiffTracker, resources ...resource.Resource) []resource.ResourceDiff {
+	return tracker.Advance(resources)
+}
+
+func TestDiffTracker_Reappeared(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := NewDiffTracker()
+	tracker.now = func() time.Time { return now }
+
+	scan(tracker, makeResource("i-001", "running", nil))
+
+	now = now.Add(time.Hour)
+	diffs := scan(tracker)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, resource.DiffDeleted, diffs[0].Type)
+
+	now = now.Add(3 * time.Hour)
+	diffs = scan(tracker, makeResource("i-001", "running", nil), makeResource("i-002", "running", nil))
+	require.Len(t, diffs, 2)
+	byID := map[string]resource.ResourceDiff{diffs[0].Resource.ID: diffs[0], diffs[1].Resource.ID: diffs[1]}
+	assert.Equal(t, resource.DiffReappeared, byID["i-001"].Type)
+	assert.Equal(t, 3*time.Hour, byID["i-001"].Absent)
+	assert.Equal(t, resource.DiffAdded, byID["i-002"].Type, "never seen before")
+
+	now = now.Add(time.Hour)
+	assert.Empty(t, scan(tracker, makeResource("i-001", "running", nil), makeResource("i-002", "running", nil)))
+}
+
+func TestDiffTracker_ReappearWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := NewDiffTracker().WithReappearWindow(24 * time.Hour)
+	tracker.now = func() time.Time { return now }
+
+	scan(tracker, makeResource("i-001", "running", nil), makeResource("i-002", "running", nil))
+	now = now.Add(time.Hour)
+	scan(tracker)
+	assert.Len(t, tracker.gone, 2)
+
+	now = now.Add(25 * time.Hour)
+	scan(tracker)
+	assert.Empty(t, tracker.gone, "deletions older than the window are evicted")
+
+	now = now.Add(time.Hour)
+	diffs := scan(tracker, makeResource("i-001", "running", nil))
+	require.Len(t, diffs, 1)
+	assert.Equal(t, resource.DiffAdded, diffs[0].Type, "returned after the window")
+}
+
+func TestDiffTracker_QuietPeriodSuppressesFlap(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := quietTracker(10*time.Minute, &now)
+
+	scan(tracker, makeResource("i-001", "running", nil))
+
+	now = now.Add(5 * time.Minute)
+	assert.Empty(t, scan(tracker, makeResource("i-001", "stopped", nil)), "change inside window is held")
+
+	now = now.Add(5 * time.Minute)
+	assert.Empty(t, scan(tracker, makeResource("i-001", "running", nil)), "reverted change is never reported")
+
+	now = now.Add(15 * time.Minute)
+	assert.Empty(t, scan(tracker, makeResource("i-001", "running", nil)))
+}
+
+func TestDiffTracker_QuietPeriodReportsPersistentChange(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := quietTracker(10*time.Minute, &now)
+
+	scan(tracker, makeResource("i-001", "running", nil))
+
+	now = now.Add(5 * time.Minute)
+	assert.Empty(t, scan(tracker, makeResource("i-001", "stopped", nil)))
+
+	now = now.Add(5 * time.Minute)
+	assert.Empty(t, scan(tracker, makeResource("i-001", "stopped", nil)), "window not yet elapsed")
+
+	now = now.Add(5 * time.Minute)
+	diffs := scan(tracker, makeResource("i-001", "stopped", nil))
+	require.Len(t, diffs, 1)
+	assert.Equal(t, resource.DiffModified, diffs[0].Type)
+	assert.Equal(t, "running", diffs[0].Changes["status"].Previous)
+	assert.Equal(t, "stopped", diffs[0].Changes["status"].Current)
+
+	now = now.Add(5 * time.Minute)
+	assert.Empty(t, scan(tracker, makeResource("i-001", "stopped", nil)), "reported change becomes the baseline")
+}
+
+func TestDiffTracker_QuietPeriodAddedAndDeleted(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := quietTracker(10*time.Minute, &now)
+
+	scan(tracker, makeResource("i-001", "running", nil))
+
+	// i-001 disappears and i-002 appears; both must persist for the window.
+	now = now.Add(5 * time.Minute)
+	assert.Empty(t, scan(tracker, makeResource("i-002", "running", nil)))
+
+	now = now.Add(10 * time.Minute)
+	diffs := scan(tracker, makeResource("i-002", "running", nil))
+	require.Len(t, diffs, 2)
+
+	types := map[string]resource.DiffType{}
+	for _, d := range diffs {
+		types[d.Resource.ID] = d.Type
+	}
+	assert.Equal(t, resource.DiffDeleted, types["i-001"])
+	assert.Equal(t, resource.DiffAdded, types["i-002"])
+}
@@ -3,11 +3,13 @@ package emitter
 
 import (
 	"context"
+	"errors"
 
 	"github.com/yairfalse/elava/pkg/resource"
 )
 
-// Emitter outputs scanned resources to a backend.
+// Emitter outputs scanned resources to a backend. Plugins are scanned in
+// parallel, so implementations must be safe for concurrent use.
 type Emitter interface {
 	// Emit sends resources to the backend.
 	Emit(ctx context.Context, result resource.ScanResult) error
@@ -16,7 +18,58 @@ type Emitter interface {
 	Close() error
 }
 
-// MultiEmitter fans out to multiple emitters.
+// StreamEmitter is implemented by emitters that can take resources one at a
+// time instead of as a whole scan result. A streamed scan's resources are
+// passed to EmitResource while the scan runs, with a ctx from WithScan, and
+// may interleave with other scans'. The scan then ends with Emit, with
+// Streamed set, no resources and the scan's error if it failed, so the
+// emitter can finish or drop what it was streamed.
+type StreamEmitter interface {
+	Emitter
+
+	// EmitResource sends a single resource to the backend.
+	EmitResource(ctx context.Context, r resource.Resource) error
+}
+
+type scanContextKey struct{}
+
+// WithScan returns ctx marked as carrying resources of the scan whose
+// ScanResult will have the given provider and region.
+func WithScan(ctx context.Context, provider, region string) context.Context {
+	return context.WithValue(ctx, scanContextKey{}, scanKey(provider, region))
+}
+
+// scanFrom returns the scan key set by WithScan.
+func scanFrom(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(scanContextKey{}).(string)
+	return key, ok
+}
+
+// scanKey identifies a scan by its result's provider and region.
+func scanKey(provider, region string) string {
+	return provider + "/" + region
+}
+
+// Streams reports whether every backend behind e is a StreamEmitter, so a
+// scan need not hold its resources in memory for e.
+func Streams(e Emitter) bool {
+	switch e := e.(type) {
+	case *MultiEmitter:
+		for _, child := range e.emitters {
+			if !Streams(child) {
+				return false
+			}
+		}
+		return true
+	case *RedactingEmitter:
+		return Streams(e.next)
+	}
+	_, ok := e.(StreamEmitter)
+	return ok
+}
+
+// MultiEmitter fans out to multiple emitters. Every emitter is called even
+// when an earlier one fails, so one broken backend does not starve the rest.
 type MultiEmitter struct {
 	emitters []Emitter
 }
@@ -26,22 +79,32 @@ func NewMultiEmitter(emitters ...Emitter) *MultiEmitter {
 	return &MultiEmitter{emitters: emitters}
 }
 
-// Emit sends to all emitters, returns first error.
+// Emit sends to all emitters and joins their errors.
 func (m *MultiEmitter) Emit(ctx context.Context, result resource.ScanResult) error {
+	var errs []error
 	for _, e := range m.emitters {
-		if err := e.Emit(ctx, result); err != nil {
-			return err
+		errs = append(errs, e.Emit(ctx, result))
+	}
+	return errors.Join(errs...)
+}
+
+// EmitResource sends r to every emitter that supports streaming and joins
+// their errors.
+func (m *MultiEmitter) EmitResource(ctx context.Context, r resource.Resource) error {
+	var errs []error
+	for _, e := range m.emitters {
+		if se, ok := e.(StreamEmitter); ok {
+			errs = append(errs, se.EmitResource(ctx, r))
 		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
-// Close closes all emitters.
+// Close closes all emitters and joins their errors.
 func (m *MultiEmitter) Close() error {
+	var errs []error
 	for _, e := range m.emitters {
-		if err := e.Close(); err != nil {
-			return err
-		}
+		errs = append(errs, e.Close())
 	}
-	return nil
+	return errors.Join(errs...)
 }
@@ -32,6 +32,37 @@ func (m *mockEmitter) Close() error {
 	return m.closeErr
 }
 
+// streamingMockEmitter records resources sent through EmitResource.
+type streamingMockEmitter struct {
+	mockEmitter
+	streamed []resource.Resource
+}
+
+func (m *streamingMockEmitter) EmitResource(_ context.Context, r resource.Resource) error {
+	m.streamed = append(m.streamed, r)
+	return nil
+}
+
+func TestMultiEmitter_EmitResource(t *testing.T) {
+	batch := &mockEmitter{}
+	stream := &streamingMockEmitter{}
+	multi := NewMultiEmitter(batch, stream)
+
+	require.NoError(t, multi.EmitResource(context.Background(), resource.Resource{ID: "i-123"}))
+
+	assert.Equal(t, 0, batch.emitCalls)
+	require.Len(t, stream.streamed, 1)
+	assert.Equal(t, "i-123", stream.streamed[0].ID)
+}
+
+func TestStreams(t *testing.T) {
+	assert.True(t, Streams(&streamingMockEmitter{}))
+	assert.False(t, Streams(&mockEmitter{}))
+	assert.True(t, Streams(NewMultiEmitter(&streamingMockEmitter{}, NewRedactingEmitter(&streamingMockEmitter{}, nil))))
+	assert.False(t, Streams(NewMultiEmitter(&streamingMockEmitter{}, &mockEmitter{})), "one batch-only backend needs the full result")
+	assert.False(t, Streams(NewRedactingEmitter(&mockEmitter{}, nil)))
+}
+
 func TestMultiEmitter_Emit(t *testing.T) {
 	e1 := &mockEmitter{}
 	e2 := &mockEmitter{}
@@ -60,9 +91,21 @@ func TestMultiEmitter_Emit_Error(t *testing.T) {
 
 	err := multi.Emit(context.Background(), resource.ScanResult{})
 
-	assert.Error(t, err)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "emit failed")
 	assert.Equal(t, 1, e1.emitCalls)
-	assert.Equal(t, 0, e2.emitCalls) // Should stop on first error
+	assert.Equal(t, 1, e2.emitCalls) // still called after the first failure
+}
+
+func TestMultiEmitter_Emit_JoinsErrors(t *testing.T) {
+	errA := errors.New("prometheus down")
+	errB := errors.New("disk full")
+	multi := NewMultiEmitter(&mockEmitter{emitErr: errA}, &mockEmitter{}, &mockEmitter{emitErr: errB})
+
+	err := multi.Emit(context.Background(), resource.ScanResult{})
+
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
 }
 
 func TestMultiEmitter_Close(t *testing.T) {
@@ -84,9 +127,10 @@ func TestMultiEmitter_Close_Error(t *testing.T) {
 
 	err := multi.Close()
 
-	assert.Error(t, err)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "close failed")
 	assert.Equal(t, 1, e1.closeCalls)
-	assert.Equal(t, 0, e2.closeCalls) // Should stop on first error
+	assert.Equal(t, 1, e2.closeCalls) // still closed after the first failure
 }
 
 func TestMultiEmitter_Empty(t *testing.T) {
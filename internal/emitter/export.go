@@ -0,0 +1,238 @@
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// Export formats.
+const (
+	ExportFormatJSON    = "json" // one JSON object per line
+	ExportFormatCSV     = "csv"
+	ExportFormatParquet = "parquet" // written by ParquetEmitter
+)
+
+// DefaultExportFields are exported when no fields are configured.
+var DefaultExportFields = []string{"id", "type", "provider", "region", "account", "name", "status"}
+
+// exportScalars maps top-level field names to their accessors.
+var exportScalars = map[string]func(resource.Resource) string{
+	"id":         func(r resource.Resource) string { return r.ID },
+	"display_id": resource.Resource.DisplayID, // ARNs shortened to their resource part
+	"type":       func(r resource.Resource) string { return r.Type },
+	"provider":   func(r resource.Resource) string { return r.Provider },
+	"region":     func(r resource.Resource) string { return r.Region },
+	"account":    func(r resource.Resource) string { return r.Account },
+	"name":       func(r resource.Resource) string { return r.Name },
+	"status":     func(r resource.Resource) string { return r.Status },
+	"created_at": func(r resource.Resource) string { return formatExportTime(r.CreatedAt) },
+	"scanned_at": func(r resource.Resource) string { return formatExportTime(r.ScannedAt) },
+}
+
+// exportField is a selected field and how to read it from a resource.
+// value reports false when a nested key is not set.
+type exportField struct {
+	path  string
+	value func(resource.Resource) (string, bool)
+}
+
+// ExportOptions configures the export emitter.
+type ExportOptions struct {
+	// Format is ExportFormatJSON (default) or ExportFormatCSV.
+	Format string
+	// Fields selects the exported fields, e.g. "id", "region", "tags.Owner".
+	// Nested paths read a single key from labels (alias tags) or attrs.
+	// Defaults to DefaultExportFields.
+	Fields []string
+}
+
+// ExportEmitter writes a projection of each scanned resource as JSON Lines or CSV.
+type ExportEmitter struct {
+	w      io.WriteCloser
+	format string
+	fields []exportField
+
+	mu            sync.Mutex
+	csv           *csv.Writer
+	headerWritten bool
+
+	staged scanSpools // streamed resources awaiting their scan's outcome
+}
+
+// NewExportEmitter creates an export emitter writing to w. It fails on an
+// unknown format or field path, so bad selections surface at startup.
+func NewExportEmitter(w io.WriteCloser, opts ExportOptions) (*ExportEmitter, error) {
+	format := opts.Format
+	if format == "" {
+		format = ExportFormatJSON
+	}
+	if format != ExportFormatJSON && format != ExportFormatCSV {
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+
+	paths := opts.Fields
+	if len(paths) == 0 {
+		paths = DefaultExportFields
+	}
+	fields, err := parseExportFields(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &ExportEmitter{w: w, format: format, fields: fields}
+	if format == ExportFormatCSV {
+		e.csv = csv.NewWriter(w)
+	}
+	return e, nil
+}
+
+// parseExportFields resolves field paths to accessors.
+func parseExportFields(paths []string) ([]exportField, error) {
+	fields := make([]exportField, 0, len(paths))
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		f, err := parseExportField(path)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func parseExportField(path string) (exportField, error) {
+	if get, ok := exportScalars[path]; ok {
+		return exportField{path: path, value: func(r resource.Resource) (string, bool) { return get(r), true }}, nil
+	}
+
+	root, key, nested := strings.Cut(path, ".")
+	if !nested || key == "" {
+		return exportField{}, fmt.Errorf("unknown export field %q", path)
+	}
+	switch root {
+	case "tags", "labels":
+		return exportField{path: path, value: func(r resource.Resource) (string, bool) {
+			v, ok := r.Labels[key]
+			return v, ok
+		}}, nil
+	case "attrs":
+		return exportField{path: path, value: func(r resource.Resource) (string, bool) {
+			v, ok := r.Attrs[key]
+			return v, ok
+		}}, nil
+	}
+	return exportField{}, fmt.Errorf("unknown export field %q", path)
+}
+
+// Emit writes one line or row per resource. Failed scans are skipped; a
+// streamed scan writes the resources staged by EmitResource if it succeeded.
+func (e *ExportEmitter) Emit(_ context.Context, result resource.ScanResult) error {
+	if result.Streamed {
+		return e.staged.finish(result, e.write)
+	}
+	if result.Error != nil {
+		return nil
+	}
+	return e.write(result.Resources)
+}
+
+// EmitResource stages r until its scan finishes, or writes it right away
+// if ctx names no scan.
+func (e *ExportEmitter) EmitResource(ctx context.Context, r resource.Resource) error {
+	if staged, err := e.staged.write(ctx, r); staged {
+		return err
+	}
+	return e.write([]resource.Resource{r})
+}
+
+func (e *ExportEmitter) write(resources []resource.Resource) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.format == ExportFormatCSV {
+		return e.writeCSV(resources)
+	}
+	return e.writeJSON(resources)
+}
+
+// writeJSON writes each resource as a flat object keyed by field path, in
+// the configured field order. Unset nested keys are written as null.
+func (e *ExportEmitter) writeJSON(resources []resource.Resource) error {
+	var buf bytes.Buffer
+	for _, r := range resources {
+		buf.WriteByte('{')
+		for i, f := range e.fields {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(f.path)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if v, ok := f.value(r); ok {
+				val, _ := json.Marshal(v)
+				buf.Write(val)
+			} else {
+				buf.WriteString("null")
+			}
+		}
+		buf.WriteString("}\n")
+	}
+	if _, err := e.w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+	return nil
+}
+
+// writeCSV writes a header row once, then one row per resource. Unset
+// nested keys are written as empty cells.
+func (e *ExportEmitter) writeCSV(resources []resource.Resource) error {
+	if !e.headerWritten {
+		header := make([]string, len(e.fields))
+		for i, f := range e.fields {
+			header[i] = f.path
+		}
+		if err := e.csv.Write(header); err != nil {
+			return fmt.Errorf("write export header: %w", err)
+		}
+		e.headerWritten = true
+	}
+
+	row := make([]string, len(e.fields))
+	for _, r := range resources {
+		for i, f := range e.fields {
+			row[i], _ = f.value(r)
+		}
+		if err := e.csv.Write(row); err != nil {
+			return fmt.Errorf("write export row: %w", err)
+		}
+	}
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+	return nil
+}
+
+// formatExportTime formats t as RFC 3339, or "" if unknown.
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Close drops the resources of unfinished scans and closes the underlying
+// writer.
+func (e *ExportEmitter) Close() error {
+	e.staged.discard()
+	return e.w.Close()
+}
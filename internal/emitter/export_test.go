@@ -0,0 +1,149 @@
+package emitter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func exportTestResult() resource.ScanResult {
+	return resource.ScanResult{
+		Provider: "aws",
+		Resources: []resource.Resource{
+			{
+				ID: "i-1", Type: "ec2", Region: "us-east-1", Name: "web",
+				Labels:    map[string]string{"Owner": "alice", "env": "prod"},
+				CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+			{ID: "vol-1", Type: "ebs", Region: "eu-west-1", Attrs: map[string]string{"orphaned": "true"}},
+		},
+	}
+}
+
+func TestExportEmitter_JSONProjection(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewExportEmitter(out, ExportOptions{Fields: []string{"id", "type", "region", "tags.Owner", "created_at"}})
+	require.NoError(t, err)
+
+	require.NoError(t, e.Emit(context.Background(), exportTestResult()))
+
+	assert.Equal(t,
+		`{"id":"i-1","type":"ec2","region":"us-east-1","tags.Owner":"alice","created_at":"2024-01-02T03:04:05Z"}`+"\n"+
+			`{"id":"vol-1","type":"ebs","region":"eu-west-1","tags.Owner":null,"created_at":""}`+"\n",
+		out.String())
+}
+
+func TestExportEmitter_CSVProjection(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewExportEmitter(out, ExportOptions{Format: ExportFormatCSV, Fields: []string{"id", "tags.Owner", "attrs.orphaned"}})
+	require.NoError(t, err)
+
+	result := exportTestResult()
+	require.NoError(t, e.Emit(context.Background(), result))
+	require.NoError(t, e.Emit(context.Background(), resource.ScanResult{Resources: result.Resources[:1]}))
+
+	assert.Equal(t, "id,tags.Owner,attrs.orphaned\n"+
+		"i-1,alice,\n"+
+		"vol-1,,true\n"+
+		"i-1,alice,\n", out.String())
+}
+
+func TestExportEmitter_DefaultFields(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewExportEmitter(out, ExportOptions{Format: ExportFormatCSV})
+	require.NoError(t, err)
+
+	require.NoError(t, e.Emit(context.Background(), resource.ScanResult{Resources: []resource.Resource{
+		{ID: "i-1", Type: "ec2", Provider: "aws", Region: "us-east-1", Account: "123", Name: "web", Status: "running"},
+	}}))
+
+	assert.Equal(t, "id,type,provider,region,account,name,status\n"+
+		"i-1,ec2,aws,us-east-1,123,web,running\n", out.String())
+}
+
+func TestExportEmitter_DisplayID(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewExportEmitter(out, ExportOptions{Format: ExportFormatCSV, Fields: []string{"id", "display_id"}})
+	require.NoError(t, err)
+
+	require.NoError(t, e.Emit(context.Background(), resource.ScanResult{Resources: []resource.Resource{
+		{ID: "arn:aws:sns:us-east-1:123456789012:alerts"},
+		{ID: "i-1"},
+	}}))
+
+	assert.Equal(t, "id,display_id\n"+
+		"arn:aws:sns:us-east-1:123456789012:alerts,alerts\n"+
+		"i-1,i-1\n", out.String())
+}
+
+func TestExportEmitter_EmitResource(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewExportEmitter(out, ExportOptions{Format: ExportFormatCSV, Fields: []string{"id", "region"}})
+	require.NoError(t, err)
+
+	result := exportTestResult()
+	for _, r := range result.Resources {
+		require.NoError(t, e.EmitResource(context.Background(), r))
+	}
+	assert.Equal(t, "id,region\n"+
+		"i-1,us-east-1\n"+
+		"vol-1,eu-west-1\n", out.String())
+
+	// The final result of a streamed scan is not written again.
+	result.Streamed = true
+	require.NoError(t, e.Emit(context.Background(), result))
+	assert.Equal(t, "id,region\ni-1,us-east-1\nvol-1,eu-west-1\n", out.String())
+}
+
+func TestExportEmitter_StreamedScan(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewExportEmitter(out, ExportOptions{Fields: []string{"id"}})
+	require.NoError(t, err)
+
+	east := WithScan(context.Background(), "aws-us-east-1", "us-east-1")
+	west := WithScan(context.Background(), "aws-us-west-2", "us-west-2")
+	require.NoError(t, e.EmitResource(east, resource.Resource{ID: "i-1"}))
+	require.NoError(t, e.EmitResource(west, resource.Resource{ID: "i-2"}))
+	require.NoError(t, e.EmitResource(east, resource.Resource{ID: "i-3"}))
+	assert.Empty(t, out.String(), "rows wait for their scan to succeed")
+
+	require.NoError(t, e.Emit(context.Background(), resource.ScanResult{Provider: "aws-us-west-2", Region: "us-west-2", Streamed: true, Error: errors.New("timed out")}))
+	require.NoError(t, e.Emit(context.Background(), resource.ScanResult{Provider: "aws-us-east-1", Region: "us-east-1", Streamed: true}))
+	assert.Equal(t, "{\"id\":\"i-1\"}\n{\"id\":\"i-3\"}\n", out.String(), "the failed scan's rows are dropped")
+}
+
+func TestExportEmitter_SkipsScanErrors(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewExportEmitter(out, ExportOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, e.Emit(context.Background(), resource.ScanResult{Error: errors.New("denied")}))
+	assert.Empty(t, out.String())
+
+	require.NoError(t, e.Close())
+	assert.True(t, out.closed)
+}
+
+func TestNewExportEmitter_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ExportOptions
+	}{
+		{"unknown field", ExportOptions{Fields: []string{"id", "owner"}}},
+		{"unknown root", ExportOptions{Fields: []string{"meta.owner"}}},
+		{"empty key", ExportOptions{Fields: []string{"tags."}}},
+		{"unknown format", ExportOptions{Format: "xml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewExportEmitter(&nopWriteCloser{}, tt.opts)
+			require.Error(t, err)
+		})
+	}
+}
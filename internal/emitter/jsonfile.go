@@ -0,0 +1,81 @@
+package emitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// scanRecord is one line of the JSON file emitter's output.
+type scanRecord struct {
+	Provider   string              `json:"provider"`
+	Region     string              `json:"region"`
+	DurationMS int64               `json:"duration_ms"`
+	Error      string              `json:"error,omitempty"`
+	Resources  []resource.Resource `json:"resources"`
+}
+
+// newScanRecord converts result for JSON output. Failed scans keep their
+// error so gaps in the output can be explained.
+func newScanRecord(result resource.ScanResult) scanRecord {
+	rec := scanRecord{
+		Provider:   result.Provider,
+		Region:     result.Region,
+		DurationMS: result.Duration.Milliseconds(),
+		Resources:  result.Resources,
+	}
+	if rec.Resources == nil {
+		rec.Resources = []resource.Resource{}
+	}
+	if result.Error != nil {
+		rec.Error = result.Error.Error()
+	}
+	return rec
+}
+
+// JSONFileEmitter appends each scan result to a file as a single JSON
+// object per line (NDJSON), for log pipelines and jq.
+type JSONFileEmitter struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewJSONFileEmitter opens path for appending, creating it if needed.
+func NewJSONFileEmitter(path string) (*JSONFileEmitter, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open json output: %w", err)
+	}
+	return &JSONFileEmitter{f: f}, nil
+}
+
+// Emit appends result as one line.
+func (e *JSONFileEmitter) Emit(_ context.Context, result resource.ScanResult) error {
+	line, err := json.Marshal(newScanRecord(result))
+	if err != nil {
+		return fmt.Errorf("encode scan result: %w", err)
+	}
+	line = append(line, '\n')
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, err := e.f.Write(line); err != nil {
+		return fmt.Errorf("write json output: %w", err)
+	}
+	return nil
+}
+
+// Close syncs the file to disk and closes it.
+func (e *JSONFileEmitter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.f.Sync(); err != nil {
+		_ = e.f.Close()
+		return fmt.Errorf("sync json output: %w", err)
+	}
+	return e.f.Close()
+}
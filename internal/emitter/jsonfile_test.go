@@ -0,0 +1,89 @@
+package emitter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func readJSONLines(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	var lines []map[string]any
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &line), "line %q", sc.Text())
+		lines = append(lines, line)
+	}
+	require.NoError(t, sc.Err())
+	return lines
+}
+
+func TestJSONFileEmitter_OneLinePerResult(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scans.ndjson")
+	e, err := NewJSONFileEmitter(path)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, e.Emit(ctx, resource.ScanResult{
+		Provider:  "aws",
+		Region:    "us-east-1",
+		Duration:  1500 * time.Millisecond,
+		Resources: []resource.Resource{{ID: "i-1", Type: "ec2"}, {ID: "vol-1", Type: "ebs"}},
+	}))
+	require.NoError(t, e.Emit(ctx, resource.ScanResult{
+		Provider: "aws",
+		Region:   "eu-west-1",
+		Error:    errors.New("access denied"),
+	}))
+	require.NoError(t, e.Close())
+
+	lines := readJSONLines(t, path)
+	require.Len(t, lines, 2)
+
+	assert.Equal(t, "aws", lines[0]["provider"])
+	assert.Equal(t, "us-east-1", lines[0]["region"])
+	assert.InDelta(t, 1500, lines[0]["duration_ms"], 0)
+	resources := lines[0]["resources"].([]any)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "i-1", resources[0].(map[string]any)["id"])
+	assert.NotContains(t, lines[0], "error")
+
+	assert.Equal(t, "eu-west-1", lines[1]["region"])
+	assert.Equal(t, "access denied", lines[1]["error"])
+	assert.Empty(t, lines[1]["resources"])
+}
+
+func TestJSONFileEmitter_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scans.ndjson")
+	require.NoError(t, os.WriteFile(path, []byte(`{"provider":"earlier"}`+"\n"), 0o644))
+
+	e, err := NewJSONFileEmitter(path)
+	require.NoError(t, err)
+	require.NoError(t, e.Emit(context.Background(), resource.ScanResult{Provider: "aws"}))
+	require.NoError(t, e.Close())
+
+	lines := readJSONLines(t, path)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "earlier", lines[0]["provider"])
+	assert.Equal(t, "aws", lines[1]["provider"])
+}
+
+func TestNewJSONFileEmitter_BadPath(t *testing.T) {
+	_, err := NewJSONFileEmitter(filepath.Join(t.TempDir(), "missing", "scans.ndjson"))
+	require.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package emitter
+
+import (
+	"context"
+	"time"
+
+	otellog "go.opentelemetry.io/otel/log"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// LogEmitter emits every scanned resource as an OTEL log record, so findings
+// land in the logging backend alongside metrics and traces.
+type LogEmitter struct {
+	logger otellog.Logger
+	now    func() time.Time
+
+	staged scanSpools // streamed resources awaiting their scan's outcome
+}
+
+// NewLogEmitter creates a log emitter writing to logger, typically from
+// telemetry.Provider.Logger.
+func NewLogEmitter(logger otellog.Logger) *LogEmitter {
+	return &LogEmitter{logger: logger, now: time.Now}
+}
+
+// Emit writes one record per resource, or a single error record for a failed
+// scan. A streamed scan writes the resources staged by EmitResource if it
+// succeeded.
+func (e *LogEmitter) Emit(ctx context.Context, result resource.ScanResult) error {
+	var err error
+	if result.Streamed {
+		err = e.staged.finish(result, func(resources []resource.Resource) error {
+			e.write(ctx, resources)
+			return nil
+		})
+	}
+
+	if result.Error != nil {
+		var rec otellog.Record
+		rec.SetTimestamp(e.now())
+		rec.SetSeverity(otellog.SeverityError)
+		rec.SetSeverityText("ERROR")
+		rec.SetBody(otellog.StringValue("scan error"))
+		rec.AddAttributes(
+			otellog.String("provider", result.Provider),
+			otellog.String("region", result.Region),
+			otellog.String("error", result.Error.Error()),
+		)
+		e.logger.Emit(ctx, rec)
+		return err
+	}
+
+	e.write(ctx, result.Resources)
+	return err
+}
+
+// EmitResource stages r until its scan finishes, or writes its record right
+// away if ctx names no scan.
+func (e *LogEmitter) EmitResource(ctx context.Context, r resource.Resource) error {
+	if staged, err := e.staged.write(ctx, r); staged {
+		return err
+	}
+	e.logger.Emit(ctx, e.record(r))
+	return nil
+}
+
+func (e *LogEmitter) write(ctx context.Context, resources []resource.Resource) {
+	for _, r := range resources {
+		e.logger.Emit(ctx, e.record(r))
+	}
+}
+
+// record builds the log record for a resource. Labels and attrs are
+// flattened into label.<key> and attr.<key> attributes.
+func (e *LogEmitter) record(r resource.Resource) otellog.Record {
+	var rec otellog.Record
+
+	ts := r.ScannedAt
+	if ts.IsZero() {
+		ts = e.now()
+	}
+	rec.SetTimestamp(ts)
+	rec.SetSeverity(otellog.SeverityInfo)
+	rec.SetSeverityText("INFO")
+	rec.SetBody(otellog.StringValue("resource scanned"))
+
+	rec.AddAttributes(
+		otellog.String("id", r.ID),
+		otellog.String("type", r.Type),
+		otellog.String("provider", r.Provider),
+		otellog.String("region", r.Region),
+		otellog.String("status", r.Status),
+	)
+	if r.Account != "" {
+		rec.AddAttributes(otellog.String("account", r.Account))
+	}
+	if r.Name != "" {
+		rec.AddAttributes(otellog.String("name", r.Name))
+	}
+	if !r.CreatedAt.IsZero() {
+		rec.AddAttributes(otellog.String("created_at", r.CreatedAt.UTC().Format(time.RFC3339)))
+	}
+	for k, v := range r.Labels {
+		rec.AddAttributes(otellog.String("label."+k, v))
+	}
+	for k, v := range r.Attrs {
+		rec.AddAttributes(otellog.String("attr."+k, v))
+	}
+
+	return rec
+}
+
+// Close drops the resources of unfinished scans; the logger provider is
+// flushed by telemetry shutdown.
+func (e *LogEmitter) Close() error {
+	e.staged.discard()
+	return nil
+}
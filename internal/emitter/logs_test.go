@@ -0,0 +1,121 @@
+package emitter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// memoryLogExporter keeps exported log records in memory.
+type memoryLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (m *memoryLogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range records {
+		m.records = append(m.records, r.Clone())
+	}
+	return nil
+}
+
+func (m *memoryLogExporter) Shutdown(context.Context) error   { return nil }
+func (m *memoryLogExporter) ForceFlush(context.Context) error { return nil }
+
+func newTestLogEmitter(t *testing.T) (*LogEmitter, *memoryLogExporter) {
+	exp := &memoryLogExporter{}
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exp)))
+	t.Cleanup(func() { _ = lp.Shutdown(context.Background()) })
+	return NewLogEmitter(lp.Logger("elava")), exp
+}
+
+func recordAttrs(r sdklog.Record) map[string]string {
+	attrs := make(map[string]string)
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value.AsString()
+		return true
+	})
+	return attrs
+}
+
+func TestLogEmitter_Resources(t *testing.T) {
+	e, exp := newTestLogEmitter(t)
+	scanned := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	result := resource.ScanResult{
+		Provider: "aws",
+		Region:   "us-east-1",
+		Resources: []resource.Resource{
+			{
+				ID: "vol-1", Type: "ebs", Provider: "aws", Region: "us-east-1", Account: "123456789012",
+				Status: "available", ScannedAt: scanned,
+				Labels: map[string]string{"team": "platform"},
+				Attrs:  map[string]string{"orphaned": "true"},
+			},
+			{ID: "i-1", Type: "ec2", Provider: "aws", Region: "us-east-1", Name: "web", Status: "running"},
+		},
+	}
+	require.NoError(t, e.Emit(context.Background(), result))
+
+	require.Len(t, exp.records, 2)
+
+	vol := exp.records[0]
+	assert.Equal(t, "resource scanned", vol.Body().AsString())
+	assert.Equal(t, otellog.SeverityInfo, vol.Severity())
+	assert.Equal(t, scanned, vol.Timestamp())
+	assert.Equal(t, map[string]string{
+		"id":            "vol-1",
+		"type":          "ebs",
+		"provider":      "aws",
+		"region":        "us-east-1",
+		"status":        "available",
+		"account":       "123456789012",
+		"label.team":    "platform",
+		"attr.orphaned": "true",
+	}, recordAttrs(vol))
+
+	ec2 := exp.records[1]
+	assert.Equal(t, "web", recordAttrs(ec2)["name"])
+	assert.False(t, ec2.Timestamp().IsZero())
+}
+
+func TestLogEmitter_ScanError(t *testing.T) {
+	e, exp := newTestLogEmitter(t)
+
+	result := resource.ScanResult{Provider: "aws", Region: "eu-west-1", Error: errors.New("access denied")}
+	require.NoError(t, e.Emit(context.Background(), result))
+
+	require.Len(t, exp.records, 1)
+	assert.Equal(t, otellog.SeverityError, exp.records[0].Severity())
+	assert.Equal(t, "access denied", recordAttrs(exp.records[0])["error"])
+	assert.Equal(t, "eu-west-1", recordAttrs(exp.records[0])["region"])
+}
+
+func TestLogEmitter_StreamedScan(t *testing.T) {
+	e, exp := newTestLogEmitter(t)
+	ctx := context.Background()
+	east := WithScan(ctx, "aws-us-east-1", "us-east-1")
+	west := WithScan(ctx, "aws-us-west-2", "us-west-2")
+
+	require.NoError(t, e.EmitResource(east, resource.Resource{ID: "i-1", Type: "ec2"}))
+	require.NoError(t, e.EmitResource(west, resource.Resource{ID: "i-2", Type: "ec2"}))
+	assert.Empty(t, exp.records, "records wait for their scan to succeed")
+
+	require.NoError(t, e.Emit(ctx, resource.ScanResult{Provider: "aws-us-west-2", Region: "us-west-2", Streamed: true, Error: errors.New("timed out")}))
+	require.NoError(t, e.Emit(ctx, resource.ScanResult{Provider: "aws-us-east-1", Region: "us-east-1", Streamed: true}))
+
+	require.Len(t, exp.records, 2, "the failed scan leaves only its error record")
+	assert.Equal(t, otellog.SeverityError, exp.records[0].Severity())
+	assert.Equal(t, "i-1", recordAttrs(exp.records[1])["id"])
+}
@@ -0,0 +1,168 @@
+package emitter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// defaultParquetRowGroupSize bounds how many rows are buffered in memory
+// before a row group is written.
+const defaultParquetRowGroupSize = 10000
+
+// ParquetOptions configures the Parquet emitter.
+type ParquetOptions struct {
+	// Fields selects the exported fields, as in ExportOptions. Each field is
+	// an optional string column named after its path with "." replaced by
+	// "_", e.g. "tags.Owner" becomes "tags_Owner". Defaults to
+	// DefaultExportFields.
+	Fields []string
+	// RowGroupSize is the number of rows per row group (0 = 10000).
+	RowGroupSize int
+}
+
+// parquetColumn is an exported field and its column index in the schema.
+type parquetColumn struct {
+	field exportField
+	index int
+}
+
+// ParquetEmitter writes a projection of each scanned resource as Parquet
+// rows. Rows are flushed as row groups of RowGroupSize, so memory stays
+// bounded on large inventories; the file footer is written on Close.
+type ParquetEmitter struct {
+	w            io.WriteCloser
+	columns      []parquetColumn
+	rowGroupSize int
+
+	mu       sync.Mutex
+	writer   *parquet.Writer
+	buffered int
+
+	staged scanSpools // streamed resources awaiting their scan's outcome
+}
+
+// NewParquetEmitter creates a Parquet emitter writing to w. It fails on an
+// unknown field path or on two fields mapping to the same column name.
+func NewParquetEmitter(w io.WriteCloser, opts ParquetOptions) (*ParquetEmitter, error) {
+	paths := opts.Fields
+	if len(paths) == 0 {
+		paths = DefaultExportFields
+	}
+	fields, err := parseExportFields(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	group := make(parquet.Group, len(fields))
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		name := parquetColumnName(f.path)
+		if _, dup := group[name]; dup {
+			return nil, fmt.Errorf("duplicate parquet column %q", name)
+		}
+		group[name] = parquet.Optional(parquet.String())
+		names[i] = name
+	}
+	schema := parquet.NewSchema("resource", group)
+
+	// Group columns are ordered by name, not by field order.
+	columns := make([]parquetColumn, len(fields))
+	for i, f := range fields {
+		leaf, _ := schema.Lookup(names[i])
+		columns[i] = parquetColumn{field: f, index: leaf.ColumnIndex}
+	}
+
+	size := opts.RowGroupSize
+	if size <= 0 {
+		size = defaultParquetRowGroupSize
+	}
+
+	return &ParquetEmitter{
+		w:            w,
+		columns:      columns,
+		rowGroupSize: size,
+		writer:       parquet.NewWriter(w, schema),
+	}, nil
+}
+
+// parquetColumnName returns the column name for a field path. Dots are
+// replaced because query engines read them as struct access.
+func parquetColumnName(path string) string {
+	return strings.ReplaceAll(path, ".", "_")
+}
+
+// Emit writes one row per resource. Failed scans are skipped; a streamed
+// scan writes the resources staged by EmitResource if it succeeded.
+func (e *ParquetEmitter) Emit(_ context.Context, result resource.ScanResult) error {
+	if result.Streamed {
+		return e.staged.finish(result, e.write)
+	}
+	if result.Error != nil {
+		return nil
+	}
+	return e.write(result.Resources)
+}
+
+// EmitResource stages r until its scan finishes, or writes it right away
+// if ctx names no scan.
+func (e *ParquetEmitter) EmitResource(ctx context.Context, r resource.Resource) error {
+	if staged, err := e.staged.write(ctx, r); staged {
+		return err
+	}
+	return e.write([]resource.Resource{r})
+}
+
+func (e *ParquetEmitter) write(resources []resource.Resource) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, r := range resources {
+		if _, err := e.writer.WriteRows([]parquet.Row{e.row(r)}); err != nil {
+			return fmt.Errorf("write parquet row: %w", err)
+		}
+		e.buffered++
+		if e.buffered < e.rowGroupSize {
+			continue
+		}
+		if err := e.writer.Flush(); err != nil {
+			return fmt.Errorf("write parquet row group: %w", err)
+		}
+		e.buffered = 0
+	}
+	return nil
+}
+
+// row builds a row in schema column order. Unset nested keys are null.
+func (e *ParquetEmitter) row(r resource.Resource) parquet.Row {
+	row := make(parquet.Row, len(e.columns))
+	for _, c := range e.columns {
+		if v, ok := c.field.value(r); ok {
+			row[c.index] = parquet.ValueOf(v).Level(0, 1, c.index)
+		} else {
+			row[c.index] = parquet.Value{}.Level(0, 0, c.index)
+		}
+	}
+	return row
+}
+
+// Close drops the resources of unfinished scans, writes the remaining rows
+// and the file footer, then closes the underlying writer.
+func (e *ParquetEmitter) Close() error {
+	e.staged.discard()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.writer.Close(); err != nil {
+		_ = e.w.Close()
+		return fmt.Errorf("close parquet writer: %w", err)
+	}
+	return e.w.Close()
+}
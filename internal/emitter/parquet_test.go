@@ -0,0 +1,109 @@
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// readParquet returns the file's column names and its rows keyed by column.
+// Null values are omitted from a row.
+func readParquet(t *testing.T, data []byte) (*parquet.File, []map[string]string) {
+	t.Helper()
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	columns := f.Schema().Columns()
+	r := parquet.NewReader(bytes.NewReader(data))
+	defer func() { _ = r.Close() }()
+
+	var out []map[string]string
+	rows := make([]parquet.Row, 1)
+	for {
+		n, err := r.ReadRows(rows)
+		for _, row := range rows[:n] {
+			m := make(map[string]string)
+			for _, v := range row {
+				if !v.IsNull() {
+					m[columns[v.Column()][0]] = v.String()
+				}
+			}
+			out = append(out, m)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+	}
+	return f, out
+}
+
+func TestParquetEmitter_RoundTrip(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewParquetEmitter(out, ParquetOptions{Fields: []string{"id", "type", "region", "tags.Owner", "attrs.orphaned", "created_at"}})
+	require.NoError(t, err)
+
+	require.NoError(t, e.Emit(context.Background(), exportTestResult()))
+	require.NoError(t, e.Close())
+	assert.True(t, out.closed)
+
+	f, rows := readParquet(t, out.Bytes())
+
+	var names []string
+	for _, col := range f.Schema().Columns() {
+		names = append(names, col[0])
+	}
+	assert.ElementsMatch(t, []string{"id", "type", "region", "tags_Owner", "attrs_orphaned", "created_at"}, names)
+
+	assert.Equal(t, []map[string]string{
+		{"id": "i-1", "type": "ec2", "region": "us-east-1", "tags_Owner": "alice", "created_at": "2024-01-02T03:04:05Z"},
+		{"id": "vol-1", "type": "ebs", "region": "eu-west-1", "attrs_orphaned": "true", "created_at": ""},
+	}, rows)
+}
+
+func TestParquetEmitter_RowGroups(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewParquetEmitter(out, ParquetOptions{RowGroupSize: 2})
+	require.NoError(t, err)
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		require.NoError(t, e.EmitResource(context.Background(), resource.Resource{ID: id, Type: "ec2"}))
+	}
+	require.NoError(t, e.Close())
+
+	f, rows := readParquet(t, out.Bytes())
+	assert.Len(t, f.RowGroups(), 3)
+	require.Len(t, rows, 5)
+	assert.Equal(t, "e", rows[4]["id"])
+}
+
+func TestParquetEmitter_SkipsFailedAndStreamed(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewParquetEmitter(out, ParquetOptions{})
+	require.NoError(t, err)
+
+	result := exportTestResult()
+	result.Streamed = true
+	require.NoError(t, e.Emit(context.Background(), result))
+	require.NoError(t, e.Emit(context.Background(), resource.ScanResult{Error: errors.New("boom"), Resources: result.Resources}))
+	require.NoError(t, e.Close())
+
+	_, rows := readParquet(t, out.Bytes())
+	assert.Empty(t, rows)
+}
+
+func TestParquetEmitter_InvalidFields(t *testing.T) {
+	_, err := NewParquetEmitter(&nopWriteCloser{}, ParquetOptions{Fields: []string{"bogus"}})
+	assert.Error(t, err)
+
+	_, err = NewParquetEmitter(&nopWriteCloser{}, ParquetOptions{Fields: []string{"tags.a_b", "tags.a.b"}})
+	assert.ErrorContains(t, err, "duplicate parquet column")
+}
@@ -3,19 +3,42 @@ package emitter
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 
+	"github.com/yairfalse/elava/internal/config"
+	"github.com/yairfalse/elava/pkg/cost"
 	"github.com/yairfalse/elava/pkg/resource"
 )
 
+// PrometheusOptions configures the Prometheus emitter.
+type PrometheusOptions struct {
+	// DisplayIDs adds a display_id label with ARNs shortened to their
+	// resource portion. The id label always carries the canonical ID.
+	DisplayIDs bool
+
+	// Namespace prefixes every metric name. Empty means "elava".
+	Namespace string
+
+	// QuietPeriod only reports a resource change once it has persisted
+	// this long, so flapping resources do not produce change events.
+	QuietPeriod time.Duration
+
+	// WatchedTags limits tag changes to these keys. Empty reports a change
+	// to any tag.
+	WatchedTags []string
+}
+
 // PrometheusEmitter emits metrics in Prometheus format via OTEL.
 type PrometheusEmitter struct {
 	meter metric.Meter
+	opts  PrometheusOptions
 
 	// Metrics
 	resourceInfo         metric.Int64ObservableGauge
@@ -23,23 +46,35 @@ type PrometheusEmitter struct {
 	scanResourcesTotal   metric.Int64Counter
 	scanErrorsTotal      metric.Int64Counter
 	resourceChangesTotal metric.Int64Counter
+	ownerResources       metric.Int64ObservableGauge
+	ownerMonthlyCost     metric.Float64ObservableGauge
+
+	// emitMu serializes Emit so diff computation and tracker updates stay consistent
+	emitMu  sync.Mutex
+	pending map[string][]resource.Resource // streamed resources by scan, awaiting its Emit
 
-	// State for observable gauge
+	// State for observable gauges: the latest resources of each
+	// provider/region, so one region's scan does not replace another's
 	mu        sync.RWMutex
-	resources []resource.Resource
+	resources map[string][]resource.Resource
 
 	// Diff tracking
 	diffTracker *DiffTracker
 }
 
 // NewPrometheusEmitter creates a Prometheus emitter.
-func NewPrometheusEmitter() (*PrometheusEmitter, error) {
+func NewPrometheusEmitter(opts PrometheusOptions) (*PrometheusEmitter, error) {
 	meter := otel.Meter("elava")
+	if opts.Namespace == "" {
+		opts.Namespace = config.DefaultMetricsNamespace
+	}
 
 	e := &PrometheusEmitter{
 		meter:       meter,
-		resources:   make([]resource.Resource, 0),
-		diffTracker: NewDiffTracker(),
+		opts:        opts,
+		pending:     make(map[string][]resource.Resource),
+		resources:   make(map[string][]resource.Resource),
+		diffTracker: NewDiffTracker().WithQuietPeriod(opts.QuietPeriod).WithWatchedTags(opts.WatchedTags),
 	}
 
 	if err := e.initMetrics(); err != nil {
@@ -54,7 +89,7 @@ func (e *PrometheusEmitter) initMetrics() error {
 
 	// Resource info gauge - shows current resources
 	e.resourceInfo, err = e.meter.Int64ObservableGauge(
-		"elava_resource_info",
+		e.metricName("resource_info"),
 		metric.WithDescription("Cloud resource information"),
 		metric.WithInt64Callback(e.observeResources),
 	)
@@ -64,7 +99,7 @@ func (e *PrometheusEmitter) initMetrics() error {
 
 	// Scan duration histogram
 	e.scanDuration, err = e.meter.Float64Histogram(
-		"elava_scan_duration_seconds",
+		e.metricName("scan_duration_seconds"),
 		metric.WithDescription("Time taken to scan resources"),
 		metric.WithUnit("s"),
 	)
@@ -74,7 +109,7 @@ func (e *PrometheusEmitter) initMetrics() error {
 
 	// Resources scanned counter
 	e.scanResourcesTotal, err = e.meter.Int64Counter(
-		"elava_scan_resources_total",
+		e.metricName("scan_resources_total"),
 		metric.WithDescription("Total resources scanned"),
 	)
 	if err != nil {
@@ -83,7 +118,7 @@ func (e *PrometheusEmitter) initMetrics() error {
 
 	// Scan errors counter
 	e.scanErrorsTotal, err = e.meter.Int64Counter(
-		"elava_scan_errors_total",
+		e.metricName("scan_errors_total"),
 		metric.WithDescription("Total scan errors"),
 	)
 	if err != nil {
@@ -92,18 +127,54 @@ func (e *PrometheusEmitter) initMetrics() error {
 
 	// Resource changes counter
 	e.resourceChangesTotal, err = e.meter.Int64Counter(
-		"elava_resource_changes_total",
+		e.metricName("resource_changes_total"),
 		metric.WithDescription("Total resource changes detected"),
 	)
 	if err != nil {
 		return fmt.Errorf("create resource_changes counter: %w", err)
 	}
 
+	// Per-owner totals, which budget alerts compare against
+	e.ownerResources, err = e.meter.Int64ObservableGauge(
+		e.metricName("owner_resources"),
+		metric.WithDescription("Current resources per owner"),
+	)
+	if err != nil {
+		return fmt.Errorf("create owner_resources gauge: %w", err)
+	}
+
+	e.ownerMonthlyCost, err = e.meter.Float64ObservableGauge(
+		e.metricName("owner_monthly_cost"),
+		metric.WithDescription("Monthly cost per owner in USD, billed cost else estimate"),
+	)
+	if err != nil {
+		return fmt.Errorf("create owner_monthly_cost gauge: %w", err)
+	}
+
+	if _, err := e.meter.RegisterCallback(e.observeOwners, e.ownerResources, e.ownerMonthlyCost); err != nil {
+		return fmt.Errorf("register owner callback: %w", err)
+	}
+
 	return nil
 }
 
-// Emit records the scan result as metrics.
+// metricName prefixes name with the configured namespace.
+func (e *PrometheusEmitter) metricName(name string) string {
+	return e.opts.Namespace + "_" + name
+}
+
+// Emit records the scan result as metrics. A streamed result uses the
+// resources passed to EmitResource for its scan. Safe for concurrent use.
 func (e *PrometheusEmitter) Emit(ctx context.Context, result resource.ScanResult) error {
+	e.emitMu.Lock()
+	defer e.emitMu.Unlock()
+
+	key := scanKey(result.Provider, result.Region)
+	if result.Streamed {
+		result.Resources = e.pending[key]
+		delete(e.pending, key)
+	}
+
 	attrs := []attribute.KeyValue{
 		attribute.String("provider", result.Provider),
 		attribute.String("region", result.Region),
@@ -126,17 +197,14 @@ func (e *PrometheusEmitter) Emit(ctx context.Context, result resource.ScanResult
 	// Record resource count
 	e.scanResourcesTotal.Add(ctx, int64(len(result.Resources)), metric.WithAttributes(attrs...))
 
-	// Compute and emit diffs
+	// Compute and emit diffs, then advance the diff tracker
 	e.emitDiffs(ctx, result)
 
 	// Update resources for observable gauge
 	e.mu.Lock()
-	e.resources = result.Resources
+	e.resources[key] = result.Resources
 	e.mu.Unlock()
 
-	// Update diff tracker state
-	e.diffTracker.Update(result.Resources)
-
 	log.Info().
 		Str("provider", result.Provider).
 		Str("region", result.Region).
@@ -147,9 +215,20 @@ func (e *PrometheusEmitter) Emit(ctx context.Context, result resource.ScanResult
 	return nil
 }
 
-// emitDiffs computes diffs and emits metrics/logs for changes.
+// EmitResource holds r for the Emit that completes the scan named by ctx.
+// The resources of a failed scan are dropped.
+func (e *PrometheusEmitter) EmitResource(ctx context.Context, r resource.Resource) error {
+	key, _ := scanFrom(ctx)
+	e.emitMu.Lock()
+	defer e.emitMu.Unlock()
+	e.pending[key] = append(e.pending[key], r)
+	return nil
+}
+
+// emitDiffs computes diffs, emits metrics/logs for changes and moves the
+// diff tracker's baseline to this scan.
 func (e *PrometheusEmitter) emitDiffs(ctx context.Context, result resource.ScanResult) {
-	diffs := e.diffTracker.ComputeDiff(result.Resources)
+	diffs := e.diffTracker.Advance(result.Resources)
 	if diffs == nil {
 		// First scan - baseline established
 		return
@@ -161,6 +240,7 @@ func (e *PrometheusEmitter) emitDiffs(ctx context.Context, result resource.ScanR
 			attribute.String("type", diff.Resource.Type),
 			attribute.String("region", diff.Resource.Region),
 			attribute.String("change_type", string(diff.Type)),
+			attribute.String("severity", diff.Severity.String()),
 		}
 		e.resourceChangesTotal.Add(ctx, 1, metric.WithAttributes(attrs...))
 
@@ -170,10 +250,11 @@ func (e *PrometheusEmitter) emitDiffs(ctx context.Context, result resource.ScanR
 			Str("type", diff.Resource.Type).
 			Str("provider", diff.Resource.Provider).
 			Str("region", diff.Resource.Region).
-			Str("change", string(diff.Type))
+			Str("change", string(diff.Type)).
+			Str("severity", diff.Severity.String())
 
 		// Add change details for modifications
-		if diff.Type == resource.DiffModified {
+		if diff.Type == resource.DiffModified || diff.Type == resource.DiffSecurityRuleModified {
 			for field, change := range diff.Changes {
 				logEvent = logEvent.
 					Str(field+".from", change.Previous).
@@ -181,6 +262,10 @@ func (e *PrometheusEmitter) emitDiffs(ctx context.Context, result resource.ScanR
 			}
 		}
 
+		if diff.Type == resource.DiffReappeared {
+			logEvent = logEvent.Dur("absent", diff.Absent)
+		}
+
 		logEvent.Msg("resource changed")
 	}
 }
@@ -190,31 +275,71 @@ func (e *PrometheusEmitter) observeResources(_ context.Context, o metric.Int64Ob
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
-	for _, r := range e.resources {
-		attrs := []attribute.KeyValue{
-			attribute.String("id", r.ID),
-			attribute.String("type", r.Type),
-			attribute.String("provider", r.Provider),
-			attribute.String("region", r.Region),
-			attribute.String("status", r.Status),
+	for _, resources := range e.resources {
+		for _, r := range resources {
+			o.Observe(1, metric.WithAttributes(e.resourceAttributes(r)...))
 		}
+	}
 
-		// Add name if present
-		if r.Name != "" {
-			attrs = append(attrs, attribute.String("name", r.Name))
-		}
+	return nil
+}
 
-		// Add common labels
-		for k, v := range r.Labels {
-			if v != "" {
-				attrs = append(attrs, attribute.String("label_"+k, v))
-			}
-		}
+// observeOwners is the callback for the per-owner gauges.
+func (e *PrometheusEmitter) observeOwners(_ context.Context, o metric.Observer) error {
+	for _, g := range e.ownerGroups() {
+		attrs := metric.WithAttributes(attribute.String("owner", g.Key))
+		o.ObserveInt64(e.ownerResources, int64(g.Resources), attrs)
+		o.ObserveFloat64(e.ownerMonthlyCost, g.MonthlyCost, attrs)
+	}
+	return nil
+}
 
-		o.Observe(1, metric.WithAttributes(attrs...))
+// ownerGroups totals the current resources of every provider/region by
+// owner, skipping unowned ones.
+func (e *PrometheusEmitter) ownerGroups() []cost.Group {
+	e.mu.RLock()
+	var all []resource.Resource
+	for _, resources := range e.resources {
+		all = append(all, resources...)
 	}
+	e.mu.RUnlock()
 
-	return nil
+	groups := cost.GroupBy(all, resource.Resource.Owner)
+	return slices.DeleteFunc(groups, func(g cost.Group) bool { return g.Key == "" })
+}
+
+// resourceAttributes builds the resource_info labels for a resource.
+func (e *PrometheusEmitter) resourceAttributes(r resource.Resource) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("id", r.ID),
+		attribute.String("type", r.Type),
+		attribute.String("provider", r.Provider),
+		attribute.String("region", r.Region),
+		attribute.String("status", r.Status),
+	}
+
+	// Owner from the owner, elava:owner or team tag in any casing
+	if owner := r.Owner(); owner != "" {
+		attrs = append(attrs, attribute.String("owner", owner))
+	}
+
+	if e.opts.DisplayIDs {
+		attrs = append(attrs, attribute.String("display_id", r.DisplayID()))
+	}
+
+	// Add name if present
+	if r.Name != "" {
+		attrs = append(attrs, attribute.String("name", r.Name))
+	}
+
+	// Add common labels
+	for k, v := range r.Labels {
+		if v != "" {
+			attrs = append(attrs, attribute.String("label_"+k, v))
+		}
+	}
+
+	return attrs
 }
 
 // Close is a no-op for Prometheus emitter.
@@ -0,0 +1,155 @@
+package emitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func attrValue(attrs []attribute.KeyValue, key string) (string, bool) {
+	for _, kv := range attrs {
+		if string(kv.Key) == key {
+			return kv.Value.AsString(), true
+		}
+	}
+	return "", false
+}
+
+func TestResourceAttributes_DisplayID(t *testing.T) {
+	arn := "arn:aws:sns:us-east-1:123456789012:alerts"
+	e, err := NewPrometheusEmitter(PrometheusOptions{DisplayIDs: true})
+	require.NoError(t, err)
+
+	attrs := e.resourceAttributes(resource.Resource{ID: arn, Type: "sns_topic"})
+
+	id, _ := attrValue(attrs, "id")
+	assert.Equal(t, arn, id)
+	displayID, ok := attrValue(attrs, "display_id")
+	require.True(t, ok)
+	assert.Equal(t, "alerts", displayID)
+}
+
+func TestResourceAttributes_DisplayIDDisabled(t *testing.T) {
+	e, err := NewPrometheusEmitter(PrometheusOptions{})
+	require.NoError(t, err)
+
+	attrs := e.resourceAttributes(resource.Resource{ID: "arn:aws:sns:us-east-1:123456789012:alerts"})
+
+	_, ok := attrValue(attrs, "display_id")
+	assert.False(t, ok)
+}
+
+func TestPrometheusEmitter_Streamed(t *testing.T) {
+	e, err := NewPrometheusEmitter(PrometheusOptions{})
+	require.NoError(t, err)
+	ctx := context.Background()
+	east := WithScan(ctx, "aws", "us-east-1")
+	west := WithScan(ctx, "aws", "us-west-2")
+
+	require.NoError(t, e.EmitResource(east, resource.Resource{ID: "i-1", Type: "ec2"}))
+	require.NoError(t, e.EmitResource(west, resource.Resource{ID: "i-3", Type: "ec2"}))
+	require.NoError(t, e.EmitResource(east, resource.Resource{ID: "i-2", Type: "ec2"}))
+	require.NoError(t, e.Emit(ctx, resource.ScanResult{Provider: "aws", Region: "us-east-1", Streamed: true}))
+	require.NoError(t, e.Emit(ctx, resource.ScanResult{Provider: "aws", Region: "us-west-2", Streamed: true, Error: errors.New("timed out")}))
+
+	e.mu.RLock()
+	assert.Len(t, e.resources["aws/us-east-1"], 2, "streamed resources back the gauge")
+	assert.NotContains(t, e.resources, "aws/us-west-2", "a failed scan's resources are dropped")
+	e.mu.RUnlock()
+	assert.Empty(t, e.pending)
+}
+
+func TestPrometheusEmitter_ConcurrentEmit(t *testing.T) {
+	e, err := NewPrometheusEmitter(PrometheusOptions{})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result := resource.ScanResult{
+				Provider:  "aws",
+				Region:    "us-east-1",
+				Resources: []resource.Resource{{ID: fmt.Sprintf("i-%d", i), Type: "ec2", Provider: "aws", Status: "running"}},
+			}
+			assert.NoError(t, e.Emit(context.Background(), result))
+		}(i)
+	}
+	wg.Wait()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	assert.Len(t, e.resources["aws/us-east-1"], 1)
+}
+
+func TestResourceAttributes_Owner(t *testing.T) {
+	e, err := NewPrometheusEmitter(PrometheusOptions{})
+	require.NoError(t, err)
+
+	attrs := e.resourceAttributes(resource.Resource{ID: "i-1", Labels: map[string]string{"Owner": "platform"}})
+
+	owner, ok := attrValue(attrs, "owner")
+	require.True(t, ok)
+	assert.Equal(t, "platform", owner)
+}
+
+func TestPrometheusEmitter_OwnerGroups(t *testing.T) {
+	e, err := NewPrometheusEmitter(PrometheusOptions{})
+	require.NoError(t, err)
+
+	result := resource.ScanResult{
+		Provider: "aws",
+		Resources: []resource.Resource{
+			{ID: "i-1", Type: "ec2", Labels: map[string]string{"owner": "platform"}, Attrs: map[string]string{"monthly_cost_estimate": "30"}},
+			{ID: "i-2", Type: "ec2", Labels: map[string]string{"Owner": "platform"}, Attrs: map[string]string{"monthly_cost": "12.5"}},
+			{ID: "i-3", Type: "ec2", Labels: map[string]string{"team": "data"}},
+			{ID: "i-4", Type: "ec2"},
+		},
+	}
+	require.NoError(t, e.Emit(context.Background(), result))
+
+	groups := e.ownerGroups()
+	require.Len(t, groups, 2, "unowned resources are not totalled")
+	assert.Equal(t, "platform", groups[0].Key)
+	assert.Equal(t, 2, groups[0].Resources)
+	assert.InDelta(t, 42.5, groups[0].MonthlyCost, 0.001)
+	assert.Equal(t, "data", groups[1].Key)
+	assert.Equal(t, 1, groups[1].Resources)
+}
+
+func TestPrometheusEmitter_OwnerGroupsAcrossRegions(t *testing.T) {
+	e, err := NewPrometheusEmitter(PrometheusOptions{})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	scan := func(region string, resources ...resource.Resource) {
+		require.NoError(t, e.Emit(ctx, resource.ScanResult{Provider: "aws", Region: region, Resources: resources}))
+	}
+	owned := func(id, cost string) resource.Resource {
+		return resource.Resource{ID: id, Type: "ec2", Labels: map[string]string{"owner": "platform"}, Attrs: map[string]string{"monthly_cost": cost}}
+	}
+
+	scan("us-east-1", owned("i-east", "30"))
+	scan("eu-west-1", owned("i-west-1", "10"), owned("i-west-2", "5"))
+
+	groups := e.ownerGroups()
+	require.Len(t, groups, 1)
+	assert.Equal(t, 3, groups[0].Resources, "both regions count")
+	assert.InDelta(t, 45.0, groups[0].MonthlyCost, 0.001)
+
+	// A region's next scan replaces only that region's resources
+	scan("us-east-1")
+	groups = e.ownerGroups()
+	require.Len(t, groups, 1)
+	assert.Equal(t, 2, groups[0].Resources)
+	assert.InDelta(t, 15.0, groups[0].MonthlyCost, 0.001)
+}
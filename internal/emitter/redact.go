@@ -0,0 +1,78 @@
+package emitter
+
+import (
+	"context"
+	"maps"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// RedactedValue replaces the value of redacted attributes.
+const RedactedValue = "[REDACTED]"
+
+// RedactingEmitter masks sensitive resource attributes before forwarding
+// to the wrapped emitter. Wrap the MultiEmitter to redact for every output.
+type RedactingEmitter struct {
+	next Emitter
+	keys map[string]bool
+}
+
+// NewRedactingEmitter wraps next, masking the given attribute keys.
+func NewRedactingEmitter(next Emitter, keys []string) *RedactingEmitter {
+	keyMap := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		keyMap[k] = true
+	}
+	return &RedactingEmitter{next: next, keys: keyMap}
+}
+
+// Emit redacts attributes on a copy of the resources and forwards the result.
+func (e *RedactingEmitter) Emit(ctx context.Context, result resource.ScanResult) error {
+	if len(e.keys) == 0 || len(result.Resources) == 0 {
+		return e.next.Emit(ctx, result)
+	}
+
+	redacted := make([]resource.Resource, len(result.Resources))
+	for i, r := range result.Resources {
+		redacted[i] = e.redact(r)
+	}
+	result.Resources = redacted
+	return e.next.Emit(ctx, result)
+}
+
+// EmitResource redacts a copy of r and forwards it if the wrapped emitter streams.
+func (e *RedactingEmitter) EmitResource(ctx context.Context, r resource.Resource) error {
+	se, ok := e.next.(StreamEmitter)
+	if !ok {
+		return nil
+	}
+	return se.EmitResource(ctx, e.redact(r))
+}
+
+// redact returns r with a copied Attrs map. The scanner's map is never modified.
+// A Name equal to a redacted value is masked too, since some scanners name
+// resources after e.g. their public IP.
+func (e *RedactingEmitter) redact(r resource.Resource) resource.Resource {
+	var attrs map[string]string
+	for k, v := range r.Attrs {
+		if !e.keys[k] {
+			continue
+		}
+		if attrs == nil {
+			attrs = maps.Clone(r.Attrs)
+		}
+		attrs[k] = RedactedValue
+		if r.Name != "" && r.Name == v {
+			r.Name = RedactedValue
+		}
+	}
+	if attrs != nil {
+		r.Attrs = attrs
+	}
+	return r
+}
+
+// Close closes the wrapped emitter.
+func (e *RedactingEmitter) Close() error {
+	return e.next.Close()
+}
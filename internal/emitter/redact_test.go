@@ -0,0 +1,81 @@
+package emitter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func TestRedactingEmitter_MasksKeys(t *testing.T) {
+	next := &mockEmitter{}
+	e := NewRedactingEmitter(next, []string{"private_ip", "endpoint"})
+
+	original := resource.Resource{
+		ID: "i-123",
+		Attrs: map[string]string{
+			"private_ip":    "10.0.1.5",
+			"endpoint":      "db.internal.example.com",
+			"instance_type": "t3.micro",
+		},
+	}
+	err := e.Emit(context.Background(), resource.ScanResult{Provider: "aws", Resources: []resource.Resource{original}})
+	require.NoError(t, err)
+
+	require.Len(t, next.results, 1)
+	got := next.results[0].Resources[0]
+	assert.Equal(t, RedactedValue, got.Attrs["private_ip"])
+	assert.Equal(t, RedactedValue, got.Attrs["endpoint"])
+	assert.Equal(t, "t3.micro", got.Attrs["instance_type"])
+	assert.Equal(t, "aws", next.results[0].Provider)
+
+	// Scanner output must not be mutated
+	assert.Equal(t, "10.0.1.5", original.Attrs["private_ip"])
+}
+
+func TestRedactingEmitter_EmitResource(t *testing.T) {
+	next := &streamingMockEmitter{}
+	e := NewRedactingEmitter(next, []string{"private_ip"})
+
+	original := resource.Resource{ID: "i-123", Attrs: map[string]string{"private_ip": "10.0.1.5"}}
+	require.NoError(t, e.EmitResource(context.Background(), original))
+
+	require.Len(t, next.streamed, 1)
+	assert.Equal(t, RedactedValue, next.streamed[0].Attrs["private_ip"])
+	assert.Equal(t, "10.0.1.5", original.Attrs["private_ip"])
+}
+
+func TestRedactingEmitter_MasksNameOfRedactedValue(t *testing.T) {
+	next := &mockEmitter{}
+	e := NewRedactingEmitter(next, []string{"public_ip"})
+
+	eip := resource.Resource{ID: "eipalloc-1", Type: "eip", Name: "52.1.2.3", Attrs: map[string]string{"public_ip": "52.1.2.3"}}
+	db := resource.Resource{ID: "db-1", Name: "orders", Attrs: map[string]string{"public_ip": "52.9.9.9"}}
+	require.NoError(t, e.Emit(context.Background(), resource.ScanResult{Resources: []resource.Resource{eip, db}}))
+
+	got := next.results[0].Resources
+	assert.Equal(t, RedactedValue, got[0].Name, "EIPs are named after their public IP")
+	assert.Equal(t, "orders", got[1].Name)
+	assert.Equal(t, "52.1.2.3", eip.Name)
+}
+
+func TestRedactingEmitter_NoKeys(t *testing.T) {
+	next := &mockEmitter{}
+	e := NewRedactingEmitter(next, nil)
+
+	r := resource.Resource{ID: "i-123", Attrs: map[string]string{"private_ip": "10.0.1.5"}}
+	require.NoError(t, e.Emit(context.Background(), resource.ScanResult{Resources: []resource.Resource{r}}))
+
+	assert.Equal(t, "10.0.1.5", next.results[0].Resources[0].Attrs["private_ip"])
+}
+
+func TestRedactingEmitter_Close(t *testing.T) {
+	next := &mockEmitter{}
+	e := NewRedactingEmitter(next, []string{"private_ip"})
+
+	require.NoError(t, e.Close())
+	assert.Equal(t, 1, next.closeCalls)
+}
@@ -0,0 +1,84 @@
+package emitter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// S3PutAPI defines the S3 operation used by the S3 emitter.
+type S3PutAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// s3TimeLayout names snapshot objects so they sort chronologically.
+const s3TimeLayout = "20060102T150405Z"
+
+// S3Emitter writes each scan result to S3 as a gzipped JSON snapshot.
+type S3Emitter struct {
+	client S3PutAPI
+	bucket string
+	prefix string
+	now    func() time.Time
+}
+
+// NewS3Emitter creates an emitter writing snapshots under prefix in bucket.
+func NewS3Emitter(bucket, prefix string, client S3PutAPI) *S3Emitter {
+	return &S3Emitter{client: client, bucket: bucket, prefix: prefix, now: time.Now}
+}
+
+// Emit uploads result to prefix/<provider>/<timestamp>.json.gz. When the
+// result has a region it is appended to the timestamp, so regions scanned
+// in the same second do not overwrite each other. The object holds the same
+// record as a JSONFileEmitter line.
+func (e *S3Emitter) Emit(ctx context.Context, result resource.ScanResult) error {
+	line, err := json.Marshal(newScanRecord(result))
+	if err != nil {
+		return fmt.Errorf("encode scan result: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(line); err != nil {
+		return fmt.Errorf("compress scan result: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("compress scan result: %w", err)
+	}
+
+	key := e.key(result)
+	_, err = e.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:          aws.String(e.bucket),
+		Key:             aws.String(key),
+		Body:            bytes.NewReader(buf.Bytes()),
+		ContentType:     aws.String("application/json"),
+		ContentEncoding: aws.String("gzip"),
+	})
+	if err != nil {
+		return fmt.Errorf("put s3://%s/%s: %w", e.bucket, key, err)
+	}
+	return nil
+}
+
+// key returns the object key for result.
+func (e *S3Emitter) key(result resource.ScanResult) string {
+	name := e.now().UTC().Format(s3TimeLayout)
+	if result.Region != "" {
+		name += "-" + result.Region
+	}
+	return path.Join(e.prefix, result.Provider, name+".json.gz")
+}
+
+// Close is a no-op; every snapshot is uploaded by Emit.
+func (e *S3Emitter) Close() error {
+	return nil
+}
@@ -0,0 +1,81 @@
+package emitter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// fakeS3Put records the objects passed to PutObject.
+type fakeS3Put struct {
+	keys   []string
+	bodies [][]byte
+	err    error
+}
+
+func (f *fakeS3Put) PutObject(_ context.Context, in *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	body, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.keys = append(f.keys, aws.ToString(in.Key))
+	f.bodies = append(f.bodies, body)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestS3Emitter_Emit(t *testing.T) {
+	fake := &fakeS3Put{}
+	e := NewS3Emitter("lake", "elava/scans", fake)
+	e.now = func() time.Time { return time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC) }
+
+	resources := []resource.Resource{
+		{ID: "i-1", Type: "ec2", Labels: map[string]string{"team": "data"}},
+		{ID: "vol-1", Type: "ebs", Attrs: map[string]string{"orphaned": "true"}},
+	}
+	require.NoError(t, e.Emit(context.Background(), resource.ScanResult{
+		Provider:  "aws",
+		Region:    "us-east-1",
+		Resources: resources,
+	}))
+
+	require.Len(t, fake.keys, 1)
+	assert.Equal(t, "elava/scans/aws/20240601T123000Z-us-east-1.json.gz", fake.keys[0])
+
+	zr, err := gzip.NewReader(bytes.NewReader(fake.bodies[0]))
+	require.NoError(t, err)
+	var rec struct {
+		Provider  string              `json:"provider"`
+		Region    string              `json:"region"`
+		Resources []resource.Resource `json:"resources"`
+	}
+	require.NoError(t, json.NewDecoder(zr).Decode(&rec))
+	assert.Equal(t, "aws", rec.Provider)
+	assert.Equal(t, "us-east-1", rec.Region)
+	assert.Equal(t, resources, rec.Resources)
+
+	assert.NoError(t, e.Close())
+}
+
+func TestS3Emitter_PutError(t *testing.T) {
+	e := NewS3Emitter("lake", "", &fakeS3Put{err: errors.New("access denied")})
+
+	err := e.Emit(context.Background(), resource.ScanResult{Provider: "aws"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "s3://lake/aws/")
+}
@@ -0,0 +1,175 @@
+package emitter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// spoolReplayBatch is how many staged resources are read back per call.
+const spoolReplayBatch = 500
+
+// spool stages a streamed scan's resources in a temporary file, so they
+// reach the output only once the scan has succeeded without being held in
+// memory. After replay or discard, further writes are dropped.
+type spool struct {
+	mu     sync.Mutex
+	f      *os.File
+	w      *bufio.Writer
+	enc    *json.Encoder
+	err    error
+	closed bool
+}
+
+func newSpool() (*spool, error) {
+	f, err := os.CreateTemp("", "elava-scan-*.jsonl")
+	if err != nil {
+		return nil, fmt.Errorf("create scan spool: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	return &spool{f: f, w: w, enc: json.NewEncoder(w)}, nil
+}
+
+// write appends batch. The first error is kept and returned by replay.
+func (s *spool) write(batch []resource.Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.err != nil {
+		return
+	}
+	for _, r := range batch {
+		if err := s.enc.Encode(r); err != nil {
+			s.err = fmt.Errorf("write scan spool: %w", err)
+			return
+		}
+	}
+}
+
+// replay calls fn with the spooled resources in write order, up to
+// spoolReplayBatch at a time, then removes the spool. Errors from fn are
+// joined and do not stop the replay.
+func (s *spool) replay(fn func([]resource.Resource) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.remove()
+
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.w.Flush(); err != nil {
+		return fmt.Errorf("flush scan spool: %w", err)
+	}
+	if _, err := s.f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind scan spool: %w", err)
+	}
+
+	var (
+		errs  []error
+		batch []resource.Resource
+	)
+	dec := json.NewDecoder(bufio.NewReader(s.f))
+	for {
+		var r resource.Resource
+		if err := dec.Decode(&r); err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Join(append(errs, fmt.Errorf("read scan spool: %w", err))...)
+		}
+		batch = append(batch, r)
+		if len(batch) == spoolReplayBatch {
+			errs = append(errs, fn(batch))
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		errs = append(errs, fn(batch))
+	}
+	return errors.Join(errs...)
+}
+
+// discard drops everything spooled, e.g. for a failed scan.
+func (s *spool) discard() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove()
+}
+
+func (s *spool) remove() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	_ = s.f.Close()
+	_ = os.Remove(s.f.Name())
+}
+
+// scanSpools keeps one spool per streamed scan, for emitters that must not
+// write anything of a scan that later fails.
+type scanSpools struct {
+	mu     sync.Mutex
+	spools map[string]*spool
+}
+
+// write stages r for the scan named by ctx. It reports false, staging
+// nothing, when ctx names no scan.
+func (s *scanSpools) write(ctx context.Context, r resource.Resource) (bool, error) {
+	key, ok := scanFrom(ctx)
+	if !ok {
+		return false, nil
+	}
+
+	s.mu.Lock()
+	sp, ok := s.spools[key]
+	if !ok {
+		var err error
+		if sp, err = newSpool(); err != nil {
+			s.mu.Unlock()
+			return true, err
+		}
+		if s.spools == nil {
+			s.spools = make(map[string]*spool)
+		}
+		s.spools[key] = sp
+	}
+	s.mu.Unlock()
+
+	sp.write([]resource.Resource{r})
+	return true, nil
+}
+
+// finish ends result's streamed scan: its staged resources are passed to fn
+// if the scan succeeded and dropped if it failed.
+func (s *scanSpools) finish(result resource.ScanResult, fn func([]resource.Resource) error) error {
+	key := scanKey(result.Provider, result.Region)
+
+	s.mu.Lock()
+	sp, ok := s.spools[key]
+	delete(s.spools, key)
+	s.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	if result.Error != nil {
+		sp.discard()
+		return nil
+	}
+	return sp.replay(fn)
+}
+
+// discard drops the spools of scans that never finished.
+func (s *scanSpools) discard() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, sp := range s.spools {
+		sp.discard()
+		delete(s.spools, key)
+	}
+}
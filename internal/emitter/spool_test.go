@@ -0,0 +1,78 @@
+package emitter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func TestSpool_Replay(t *testing.T) {
+	sp, err := newSpool()
+	require.NoError(t, err)
+
+	sp.write([]resource.Resource{{ID: "i-1", Labels: map[string]string{"team": "a"}}, {ID: "i-2"}})
+	sp.write([]resource.Resource{{ID: "db-1", Attrs: map[string]string{"engine": "postgres"}}})
+
+	var got []resource.Resource
+	require.NoError(t, sp.replay(func(batch []resource.Resource) error {
+		got = append(got, batch...)
+		return nil
+	}))
+
+	require.Len(t, got, 3)
+	assert.Equal(t, "i-1", got[0].ID)
+	assert.Equal(t, "a", got[0].Labels["team"])
+	assert.Equal(t, "postgres", got[2].Attrs["engine"])
+	_, err = os.Stat(sp.f.Name())
+	assert.True(t, os.IsNotExist(err), "spool file is removed after replay")
+}
+
+func TestSpool_DiscardDropsLaterWrites(t *testing.T) {
+	sp, err := newSpool()
+	require.NoError(t, err)
+
+	sp.write([]resource.Resource{{ID: "i-1"}})
+	sp.discard()
+	sp.write([]resource.Resource{{ID: "i-2"}})
+
+	_, err = os.Stat(sp.f.Name())
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestScanSpools_Finish(t *testing.T) {
+	var s scanSpools
+	east := WithScan(context.Background(), "aws-us-east-1", "us-east-1")
+	west := WithScan(context.Background(), "aws-us-west-2", "us-west-2")
+
+	for _, w := range []struct {
+		ctx context.Context
+		id  string
+	}{{east, "i-1"}, {west, "i-2"}, {east, "i-3"}} {
+		staged, err := s.write(w.ctx, resource.Resource{ID: w.id})
+		require.NoError(t, err)
+		assert.True(t, staged)
+	}
+	staged, err := s.write(context.Background(), resource.Resource{ID: "i-4"})
+	require.NoError(t, err)
+	assert.False(t, staged, "resources outside a scan are not staged")
+
+	var got []string
+	collect := func(batch []resource.Resource) error {
+		for _, r := range batch {
+			got = append(got, r.ID)
+		}
+		return nil
+	}
+	require.NoError(t, s.finish(resource.ScanResult{Provider: "aws-us-west-2", Region: "us-west-2", Error: errors.New("AccessDenied"), Streamed: true}, collect))
+	assert.Empty(t, got, "a failed scan's resources are dropped")
+
+	require.NoError(t, s.finish(resource.ScanResult{Provider: "aws-us-east-1", Region: "us-east-1", Streamed: true}, collect))
+	assert.Equal(t, []string{"i-1", "i-3"}, got)
+	assert.Empty(t, s.spools)
+}
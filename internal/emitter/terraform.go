@@ -0,0 +1,134 @@
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// DefaultTerraformNameFormat names import addresses after the resource name.
+const DefaultTerraformNameFormat = "{{.Name}}"
+
+// terraformTypes maps elava resource types to Terraform AWS resource types.
+var terraformTypes = map[string]string{
+	"ec2":            "aws_instance",
+	"rds":            "aws_db_instance",
+	"s3":             "aws_s3_bucket",
+	"security_group": "aws_security_group",
+}
+
+// TerraformOptions configures the Terraform import emitter.
+type TerraformOptions struct {
+	// NameFormat is a text/template rendered against each resource to build
+	// the address name, e.g. "{{.Labels.team}}_{{.Name}}". Defaults to
+	// DefaultTerraformNameFormat. Falls back to the ID when it renders empty.
+	NameFormat string
+}
+
+// TerraformEmitter writes `terraform import` commands for supported resource types.
+// Each resource is written once for the lifetime of the emitter.
+type TerraformEmitter struct {
+	w        io.WriteCloser
+	nameTmpl *template.Template
+
+	mu    sync.Mutex
+	seen  map[string]bool // resource IDs already written
+	names map[string]int  // address -> times used, for de-duplication
+}
+
+// NewTerraformEmitter creates a Terraform import emitter writing to w.
+func NewTerraformEmitter(w io.WriteCloser, opts TerraformOptions) (*TerraformEmitter, error) {
+	format := opts.NameFormat
+	if format == "" {
+		format = DefaultTerraformNameFormat
+	}
+	tmpl, err := template.New("name").Option("missingkey=zero").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("parse name format: %w", err)
+	}
+
+	return &TerraformEmitter{
+		w:        w,
+		nameTmpl: tmpl,
+		seen:     make(map[string]bool),
+		names:    make(map[string]int),
+	}, nil
+}
+
+// Emit writes an import command for each supported, not yet written resource.
+func (e *TerraformEmitter) Emit(_ context.Context, result resource.ScanResult) error {
+	if result.Error != nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, r := range result.Resources {
+		tfType, ok := terraformTypes[r.Type]
+		if !ok || e.seen[r.ID] {
+			continue
+		}
+
+		address, err := e.address(tfType, r)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(e.w, "terraform import %s %s\n", address, r.ID); err != nil {
+			return fmt.Errorf("write import command: %w", err)
+		}
+		e.seen[r.ID] = true
+	}
+	return nil
+}
+
+// address builds a unique Terraform resource address for r.
+func (e *TerraformEmitter) address(tfType string, r resource.Resource) (string, error) {
+	var buf bytes.Buffer
+	if err := e.nameTmpl.Execute(&buf, r); err != nil {
+		return "", fmt.Errorf("render name for %s: %w", r.ID, err)
+	}
+
+	name := terraformIdentifier(buf.String())
+	if name == "" {
+		name = terraformIdentifier(r.ID)
+	}
+
+	address := tfType + "." + name
+	e.names[address]++
+	if n := e.names[address]; n > 1 {
+		address = fmt.Sprintf("%s_%d", address, n)
+	}
+	return address, nil
+}
+
+// terraformIdentifier converts s into a valid Terraform identifier:
+// lowercase letters, digits, underscores and dashes, not starting with a digit.
+func terraformIdentifier(s string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(s) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '_', c == '-':
+			b.WriteRune(c)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	id := strings.Trim(b.String(), "_")
+	if id != "" && id[0] >= '0' && id[0] <= '9' {
+		id = "r_" + id
+	}
+	return id
+}
+
+// Close closes the underlying writer.
+func (e *TerraformEmitter) Close() error {
+	return e.w.Close()
+}
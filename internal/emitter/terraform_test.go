@@ -0,0 +1,80 @@
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// nopWriteCloser adapts a bytes.Buffer to io.WriteCloser.
+type nopWriteCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (w *nopWriteCloser) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestTerraformEmitter_ImportCommands(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewTerraformEmitter(out, TerraformOptions{})
+	require.NoError(t, err)
+
+	result := resource.ScanResult{
+		Provider: "aws",
+		Resources: []resource.Resource{
+			{ID: "i-abc123", Type: "ec2", Name: "Web Server"},
+			{ID: "prod-db", Type: "rds", Name: "prod-db"},
+			{ID: "my.logs.bucket", Type: "s3", Name: "my.logs.bucket"},
+			{ID: "sg-123", Type: "security_group", Name: ""},
+			{ID: "vpc-123", Type: "vpc", Name: "main"},
+		},
+	}
+	require.NoError(t, e.Emit(context.Background(), result))
+
+	assert.Equal(t, "terraform import aws_instance.web_server i-abc123\n"+
+		"terraform import aws_db_instance.prod-db prod-db\n"+
+		"terraform import aws_s3_bucket.my_logs_bucket my.logs.bucket\n"+
+		"terraform import aws_security_group.sg-123 sg-123\n", out.String())
+}
+
+func TestTerraformEmitter_NameFormat(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewTerraformEmitter(out, TerraformOptions{NameFormat: "{{.Labels.team}}_{{.Name}}"})
+	require.NoError(t, err)
+
+	result := resource.ScanResult{Resources: []resource.Resource{
+		{ID: "i-1", Type: "ec2", Name: "api", Labels: map[string]string{"team": "payments"}},
+		{ID: "i-2", Type: "ec2", Name: "api", Labels: map[string]string{"team": "payments"}},
+	}}
+	require.NoError(t, e.Emit(context.Background(), result))
+
+	assert.Equal(t, "terraform import aws_instance.payments_api i-1\n"+
+		"terraform import aws_instance.payments_api_2 i-2\n", out.String())
+}
+
+func TestTerraformEmitter_SkipsAlreadyWritten(t *testing.T) {
+	out := &nopWriteCloser{}
+	e, err := NewTerraformEmitter(out, TerraformOptions{})
+	require.NoError(t, err)
+
+	result := resource.ScanResult{Resources: []resource.Resource{{ID: "i-1", Type: "ec2", Name: "web"}}}
+	require.NoError(t, e.Emit(context.Background(), result))
+	require.NoError(t, e.Emit(context.Background(), result))
+
+	assert.Equal(t, "terraform import aws_instance.web i-1\n", out.String())
+	require.NoError(t, e.Close())
+	assert.True(t, out.closed)
+}
+
+func TestNewTerraformEmitter_InvalidFormat(t *testing.T) {
+	_, err := NewTerraformEmitter(&nopWriteCloser{}, TerraformOptions{NameFormat: "{{.Name"})
+	require.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package emitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+const (
+	defaultWebhookMaxAttempts = 3
+	defaultWebhookBaseDelay   = 500 * time.Millisecond
+	defaultWebhookTimeout     = 30 * time.Second
+)
+
+// WebhookOptions configures the webhook emitter.
+type WebhookOptions struct {
+	// MaxAttempts is the number of POSTs per scan result, including the
+	// first (0 = 3).
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry; it doubles on every
+	// further retry (0 = 500ms).
+	BaseDelay time.Duration
+	// Headers are added to every request, e.g. Authorization.
+	Headers map[string]string
+	// Client sends the requests (nil = http.Client with a 30s timeout).
+	Client *http.Client
+}
+
+// WebhookEmitter POSTs each scan result as JSON to a URL.
+type WebhookEmitter struct {
+	url         string
+	headers     map[string]string
+	client      *http.Client
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+// NewWebhookEmitter creates an emitter posting to url.
+func NewWebhookEmitter(url string, opts WebhookOptions) *WebhookEmitter {
+	e := &WebhookEmitter{
+		url:         url,
+		headers:     opts.Headers,
+		client:      opts.Client,
+		maxAttempts: opts.MaxAttempts,
+		baseDelay:   opts.BaseDelay,
+	}
+	if e.client == nil {
+		e.client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+	if e.maxAttempts <= 0 {
+		e.maxAttempts = defaultWebhookMaxAttempts
+	}
+	if e.baseDelay <= 0 {
+		e.baseDelay = defaultWebhookBaseDelay
+	}
+	return e
+}
+
+// Emit posts result with the same record as a scan_log line. Transport
+// errors, 5xx and 429 responses are retried with exponential backoff; other
+// 4xx responses fail at once. Cancelling ctx stops any further attempt.
+func (e *WebhookEmitter) Emit(ctx context.Context, result resource.ScanResult) error {
+	body, err := json.Marshal(newScanRecord(result))
+	if err != nil {
+		return fmt.Errorf("encode scan result: %w", err)
+	}
+
+	delay := e.baseDelay
+	for attempt := 1; ; attempt++ {
+		retryable, err := e.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt == e.maxAttempts {
+			return fmt.Errorf("webhook %s (attempt %d/%d): %w", e.url, attempt, e.maxAttempts, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// post sends body once and reports whether a failure may be retried.
+func (e *WebhookEmitter) post(ctx context.Context, body []byte) (retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body) // drain so the connection is reused
+
+	switch {
+	case resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return true, fmt.Errorf("status %s", resp.Status)
+	default:
+		return false, fmt.Errorf("status %s", resp.Status)
+	}
+}
+
+// Close is a no-op; every result is sent by Emit.
+func (e *WebhookEmitter) Close() error {
+	return nil
+}
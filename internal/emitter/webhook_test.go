@@ -0,0 +1,95 @@
+package emitter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func TestWebhookEmitter_RetriesServerErrors(t *testing.T) {
+	var requests atomic.Int32
+	var got scanRecord
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	e := NewWebhookEmitter(srv.URL, WebhookOptions{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+	})
+	err := e.Emit(context.Background(), resource.ScanResult{
+		Provider:  "aws",
+		Region:    "us-east-1",
+		Resources: []resource.Resource{{ID: "i-1", Type: "ec2"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+	assert.Equal(t, "us-east-1", got.Region)
+	require.Len(t, got.Resources, 1)
+	assert.Equal(t, "i-1", got.Resources[0].ID)
+}
+
+func TestWebhookEmitter_GivesUpAfterMaxAttempts(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	e := NewWebhookEmitter(srv.URL, WebhookOptions{MaxAttempts: 2, BaseDelay: time.Millisecond})
+	err := e.Emit(context.Background(), resource.ScanResult{Provider: "aws"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestWebhookEmitter_ClientErrorNotRetried(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	e := NewWebhookEmitter(srv.URL, WebhookOptions{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	err := e.Emit(context.Background(), resource.ScanResult{Provider: "aws"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestWebhookEmitter_CancelledDuringBackoff(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	e := NewWebhookEmitter(srv.URL, WebhookOptions{MaxAttempts: 5, BaseDelay: time.Hour})
+	err := e.Emit(ctx, resource.ScanResult{Provider: "aws"})
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
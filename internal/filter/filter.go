@@ -1,33 +1,85 @@
-// Package filter provides resource filtering for Elava scanners.
+// Package filter provides resource filtering and user-defined waste rules
+// for Elava scanners.
 package filter
 
 import (
+	"maps"
+	"slices"
+	"time"
+
 	"github.com/yairfalse/elava/pkg/resource"
 )
 
 // Filter controls which resource types to scan and which resources to include.
 type Filter struct {
-	excludeTypes map[string]bool
-	includeTags  map[string]string
-	excludeTags  map[string]string
+	aliases       resource.TypeAliases
+	includeTypes  map[string]bool
+	excludeTypes  map[string]bool
+	includeTags   map[string]string
+	excludeTags   map[string]string
+	createdAfter  time.Time
+	createdBefore time.Time
 }
 
-// New creates a new Filter from the provided configuration.
+// New creates a new Filter from the provided configuration. Type names may
+// be aliases such as "instance" for "ec2"; see resource.DefaultTypeAliases.
 func New(excludeTypes []string, includeTags, excludeTags map[string]string) *Filter {
-	excludeMap := make(map[string]bool)
-	for _, t := range excludeTypes {
-		excludeMap[t] = true
+	f := &Filter{
+		aliases:     resource.NewTypeAliases(nil),
+		includeTags: includeTags,
+		excludeTags: excludeTags,
+	}
+	f.excludeTypes = f.typeSet(excludeTypes)
+	return f
+}
+
+// WithTypeAliases replaces the alias table, e.g. with defaults extended from
+// config, and re-resolves the configured include and exclude types.
+func (f *Filter) WithTypeAliases(aliases resource.TypeAliases) *Filter {
+	f.aliases = aliases
+	f.excludeTypes = f.typeSet(slices.Collect(maps.Keys(f.excludeTypes)))
+	if f.includeTypes != nil {
+		f.includeTypes = f.typeSet(slices.Collect(maps.Keys(f.includeTypes)))
 	}
+	return f
+}
 
-	return &Filter{
-		excludeTypes: excludeMap,
-		includeTags:  includeTags,
-		excludeTags:  excludeTags,
+// typeSet resolves types to their canonical names.
+func (f *Filter) typeSet(types []string) map[string]bool {
+	set := make(map[string]bool, len(types))
+	for _, t := range types {
+		set[f.aliases.Canonical(t)] = true
 	}
+	return set
+}
+
+// WithIncludeTypes restricts scanning to the given resource types. An empty
+// list scans every type. Excluded types are skipped even if included.
+func (f *Filter) WithIncludeTypes(types []string) *Filter {
+	if len(types) == 0 {
+		f.includeTypes = nil
+		return f
+	}
+	f.includeTypes = f.typeSet(types)
+	return f
+}
+
+// WithCreatedWindow restricts resources to those created within [after, before].
+// Bounds are inclusive; a zero bound is ignored. A before at midnight, such
+// as a TOML date, includes that whole day. Resources with an unknown
+// creation time are excluded when a lower bound is set.
+func (f *Filter) WithCreatedWindow(after, before time.Time) *Filter {
+	f.createdAfter = after
+	f.createdBefore = before
+	return f
 }
 
 // ShouldScanType returns true if the given resource type should be scanned.
 func (f *Filter) ShouldScanType(typ string) bool {
+	typ = f.aliases.Canonical(typ)
+	if len(f.includeTypes) > 0 && !f.includeTypes[typ] {
+		return false
+	}
 	return !f.excludeTypes[typ]
 }
 
@@ -51,12 +103,43 @@ func (f *Filter) ShouldIncludeResource(r resource.Resource) bool {
 		}
 	}
 
+	return f.inCreatedWindow(r)
+}
+
+// inCreatedWindow returns true if the resource creation time is within bounds.
+func (f *Filter) inCreatedWindow(r resource.Resource) bool {
+	if !f.createdAfter.IsZero() && (r.CreatedAt.IsZero() || r.CreatedAt.Before(f.createdAfter)) {
+		return false
+	}
+	if !f.createdBefore.IsZero() && !r.CreatedAt.IsZero() && !r.CreatedAt.Before(f.createdEnd()) {
+		return false
+	}
 	return true
 }
 
+// createdEnd returns the exclusive upper bound for createdBefore: the start
+// of the next day for a date-only (midnight) bound, else just after it.
+func (f *Filter) createdEnd() time.Time {
+	if f.createdBefore.Equal(startOfDay(f.createdBefore)) {
+		return f.createdBefore.AddDate(0, 0, 1)
+	}
+	return f.createdBefore.Add(time.Nanosecond)
+}
+
+// startOfDay returns midnight of t's day in its own location.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// hasCreatedWindow returns true if any creation-date bound is set.
+func (f *Filter) hasCreatedWindow() bool {
+	return !f.createdAfter.IsZero() || !f.createdBefore.IsZero()
+}
+
 // FilterResources returns only resources that pass the filter.
 func (f *Filter) FilterResources(resources []resource.Resource) []resource.Resource {
-	if len(f.includeTags) == 0 && len(f.excludeTags) == 0 {
+	if len(f.includeTags) == 0 && len(f.excludeTags) == 0 && !f.hasCreatedWindow() {
 		return resources
 	}
 
@@ -71,5 +154,5 @@ func (f *Filter) FilterResources(resources []resource.Resource) []resource.Resou
 
 // IsEmpty returns true if no filters are configured.
 func (f *Filter) IsEmpty() bool {
-	return len(f.excludeTypes) == 0 && len(f.includeTags) == 0 && len(f.excludeTags) == 0
+	return len(f.includeTypes) == 0 && len(f.excludeTypes) == 0 && len(f.includeTags) == 0 && len(f.excludeTags) == 0 && !f.hasCreatedWindow()
 }
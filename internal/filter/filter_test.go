@@ -2,8 +2,10 @@ package filter
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/yairfalse/elava/pkg/resource"
 )
@@ -22,6 +24,30 @@ func TestShouldScanType_WithExclusions(t *testing.T) {
 	assert.False(t, f.ShouldScanType("cloudwatch_logs"))
 }
 
+func TestShouldScanType_WithIncludeTypes(t *testing.T) {
+	f := New([]string{"rds"}, nil, nil).WithIncludeTypes([]string{"ec2", "rds"})
+	assert.True(t, f.ShouldScanType("ec2"))
+	assert.False(t, f.ShouldScanType("rds"), "exclude wins over include")
+	assert.False(t, f.ShouldScanType("s3"))
+}
+
+func TestShouldScanType_Aliases(t *testing.T) {
+	f := New([]string{"ALB"}, nil, nil).WithIncludeTypes([]string{"instance", "ec2", "bucket", "elb"})
+	assert.True(t, f.ShouldScanType("ec2"))
+	assert.True(t, f.ShouldScanType("instance"))
+	assert.True(t, f.ShouldScanType("s3"))
+	assert.False(t, f.ShouldScanType("elb"), "alb excludes elb")
+	assert.False(t, f.ShouldScanType("rds"))
+}
+
+func TestShouldScanType_ConfigAliases(t *testing.T) {
+	f := New(nil, nil, nil).
+		WithIncludeTypes([]string{"vm"}).
+		WithTypeAliases(resource.NewTypeAliases(map[string]string{"vm": "ec2"}))
+	assert.True(t, f.ShouldScanType("ec2"))
+	assert.False(t, f.ShouldScanType("rds"))
+}
+
 func TestShouldIncludeResource_NoFilters(t *testing.T) {
 	f := New(nil, nil, nil)
 	r := resource.Resource{
@@ -189,4 +215,60 @@ func TestIsEmpty(t *testing.T) {
 	assert.False(t, New([]string{"ec2"}, nil, nil).IsEmpty())
 	assert.False(t, New(nil, map[string]string{"env": "prod"}, nil).IsEmpty())
 	assert.False(t, New(nil, nil, map[string]string{"skip": "true"}).IsEmpty())
+	assert.False(t, New(nil, nil, nil).WithCreatedWindow(time.Now(), time.Time{}).IsEmpty())
+	assert.False(t, New(nil, nil, nil).WithIncludeTypes([]string{"ec2"}).IsEmpty())
+	assert.True(t, New(nil, nil, nil).WithIncludeTypes(nil).IsEmpty())
+}
+
+func TestShouldIncludeResource_CreatedWindow_InclusiveBounds(t *testing.T) {
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	f := New(nil, nil, nil).WithCreatedWindow(after, before)
+
+	assert.True(t, f.ShouldIncludeResource(resource.Resource{CreatedAt: after}))
+	assert.True(t, f.ShouldIncludeResource(resource.Resource{CreatedAt: before}))
+	assert.True(t, f.ShouldIncludeResource(resource.Resource{CreatedAt: after.AddDate(0, 2, 0)}))
+	assert.False(t, f.ShouldIncludeResource(resource.Resource{CreatedAt: after.Add(-time.Second)}))
+	assert.False(t, f.ShouldIncludeResource(resource.Resource{CreatedAt: before.AddDate(0, 0, 1)}))
+}
+
+func TestShouldIncludeResource_CreatedWindow_DateOnlyBefore(t *testing.T) {
+	// created_before = 2024-06-30 covers the whole of June 30th
+	before := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	f := New(nil, nil, nil).WithCreatedWindow(time.Time{}, before)
+
+	assert.True(t, f.ShouldIncludeResource(resource.Resource{CreatedAt: before.Add(15 * time.Hour)}))
+	assert.True(t, f.ShouldIncludeResource(resource.Resource{CreatedAt: before.Add(24*time.Hour - time.Nanosecond)}))
+	assert.False(t, f.ShouldIncludeResource(resource.Resource{CreatedAt: before.Add(24 * time.Hour)}))
+}
+
+func TestShouldIncludeResource_CreatedWindow_DatetimeBefore(t *testing.T) {
+	before := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	f := New(nil, nil, nil).WithCreatedWindow(time.Time{}, before)
+
+	assert.True(t, f.ShouldIncludeResource(resource.Resource{CreatedAt: before}))
+	assert.False(t, f.ShouldIncludeResource(resource.Resource{CreatedAt: before.Add(time.Second)}))
+}
+
+func TestShouldIncludeResource_CreatedWindow_ZeroTimestamp(t *testing.T) {
+	unknown := resource.Resource{ID: "sqs-1"}
+
+	lower := New(nil, nil, nil).WithCreatedWindow(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	assert.False(t, lower.ShouldIncludeResource(unknown))
+
+	upper := New(nil, nil, nil).WithCreatedWindow(time.Time{}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.True(t, upper.ShouldIncludeResource(unknown))
+}
+
+func TestFilterResources_CreatedWindow(t *testing.T) {
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := New(nil, nil, nil).WithCreatedWindow(time.Time{}, cutoff)
+	resources := []resource.Resource{
+		{ID: "i-old", CreatedAt: cutoff.AddDate(-1, 0, 0)},
+		{ID: "i-new", CreatedAt: cutoff.AddDate(0, 1, 0)},
+	}
+
+	filtered := f.FilterResources(resources)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "i-old", filtered[0].ID)
 }
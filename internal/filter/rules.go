@@ -0,0 +1,175 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// Rule flags resources of a type that meet every condition as waste.
+//
+// Conditions map a tag or attr key to an expression: a plain value or
+// "=value" for equality, "!=value", or a numeric comparison such as ">500"
+// or "<=0". A condition on an unset key never matches.
+type Rule struct {
+	Name       string
+	Type       string            // resource type or alias
+	Tags       map[string]string // conditions on labels
+	Attrs      map[string]string // conditions on attrs
+	Waste      string            // waste type recorded on matches, e.g. "oversized"
+	Confidence float64           // 0 to 1
+}
+
+// condition is a parsed rule expression for one key.
+type condition struct {
+	key   string
+	op    string
+	value string
+	num   float64
+}
+
+// compiledRule is a validated Rule with a canonical type.
+type compiledRule struct {
+	Rule
+	tags  []condition
+	attrs []condition
+}
+
+// Rules evaluates user-defined waste rules. The zero value has no rules.
+type Rules struct {
+	rules []compiledRule
+}
+
+// NewRules validates rules and resolves their types through aliases. It
+// fails on a missing name, type or waste type, an out of range confidence,
+// or an unparseable condition, so bad rules surface at startup.
+func NewRules(rules []Rule, aliases resource.TypeAliases) (*Rules, error) {
+	rs := &Rules{rules: make([]compiledRule, 0, len(rules))}
+	for _, r := range rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("waste rule: name required")
+		}
+		if r.Type == "" || r.Waste == "" {
+			return nil, fmt.Errorf("waste rule %q: type and waste required", r.Name)
+		}
+		if r.Confidence < 0 || r.Confidence > 1 {
+			return nil, fmt.Errorf("waste rule %q: confidence must be between 0 and 1 (got %v)", r.Name, r.Confidence)
+		}
+		c := compiledRule{Rule: r}
+		c.Type = aliases.Canonical(r.Type)
+
+		var err error
+		if c.tags, err = parseConditions(r.Tags); err != nil {
+			return nil, fmt.Errorf("waste rule %q: %w", r.Name, err)
+		}
+		if c.attrs, err = parseConditions(r.Attrs); err != nil {
+			return nil, fmt.Errorf("waste rule %q: %w", r.Name, err)
+		}
+		rs.rules = append(rs.rules, c)
+	}
+	return rs, nil
+}
+
+func parseConditions(exprs map[string]string) ([]condition, error) {
+	conds := make([]condition, 0, len(exprs))
+	for key, expr := range exprs {
+		c, err := parseCondition(key, expr)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, c)
+	}
+	return conds, nil
+}
+
+// conditionOps are checked longest first so ">=" is not read as ">".
+var conditionOps = []string{">=", "<=", "!=", ">", "<", "="}
+
+func parseCondition(key, expr string) (condition, error) {
+	c := condition{key: key, op: "=", value: expr}
+	for _, op := range conditionOps {
+		if rest, ok := strings.CutPrefix(expr, op); ok {
+			c.op, c.value = op, strings.TrimSpace(rest)
+			break
+		}
+	}
+	if c.op == "=" || c.op == "!=" {
+		return c, nil
+	}
+	num, err := strconv.ParseFloat(c.value, 64)
+	if err != nil {
+		return condition{}, fmt.Errorf("condition %s %q: %s needs a number", key, expr, c.op)
+	}
+	c.num = num
+	return c, nil
+}
+
+// matches reports whether values[c.key] satisfies c.
+func (c condition) matches(values map[string]string) bool {
+	v, ok := values[c.key]
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "=":
+		return v == c.value
+	case "!=":
+		return v != c.value
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return false
+	}
+	switch c.op {
+	case ">":
+		return n > c.num
+	case ">=":
+		return n >= c.num
+	case "<":
+		return n < c.num
+	default: // "<="
+		return n <= c.num
+	}
+}
+
+func (r compiledRule) matches(res resource.Resource) bool {
+	if res.Type != r.Type {
+		return false
+	}
+	for _, c := range r.tags {
+		if !c.matches(res.Labels) {
+			return false
+		}
+	}
+	for _, c := range r.attrs {
+		if !c.matches(res.Attrs) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply sets waste, waste_rule and waste_confidence on resources matching a
+// rule. Rules are evaluated in order and the first match wins.
+func (rs *Rules) Apply(resources []resource.Resource) {
+	if rs == nil {
+		return
+	}
+	for i := range resources {
+		res := &resources[i]
+		for _, r := range rs.rules {
+			if !r.matches(*res) {
+				continue
+			}
+			if res.Attrs == nil {
+				res.Attrs = make(map[string]string)
+			}
+			res.Attrs["waste"] = r.Waste
+			res.Attrs["waste_rule"] = r.Name
+			res.Attrs["waste_confidence"] = strconv.FormatFloat(r.Confidence, 'f', 2, 64)
+			break
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func largeDevGP2Rule() Rule {
+	return Rule{
+		Name:       "large-dev-gp2",
+		Type:       "volume",
+		Tags:       map[string]string{"env": "dev"},
+		Attrs:      map[string]string{"type": "gp2", "size_gb": ">500"},
+		Waste:      "oversized",
+		Confidence: 0.8,
+	}
+}
+
+func TestRules_Apply(t *testing.T) {
+	rules, err := NewRules([]Rule{largeDevGP2Rule()}, resource.NewTypeAliases(nil))
+	require.NoError(t, err)
+
+	resources := []resource.Resource{
+		{ID: "vol-big", Type: "ebs", Labels: map[string]string{"env": "dev"}, Attrs: map[string]string{"type": "gp2", "size_gb": "1000"}},
+		{ID: "vol-small", Type: "ebs", Labels: map[string]string{"env": "dev"}, Attrs: map[string]string{"type": "gp2", "size_gb": "500"}},
+		{ID: "vol-prod", Type: "ebs", Labels: map[string]string{"env": "prod"}, Attrs: map[string]string{"type": "gp2", "size_gb": "1000"}},
+		{ID: "vol-gp3", Type: "ebs", Labels: map[string]string{"env": "dev"}, Attrs: map[string]string{"type": "gp3", "size_gb": "1000"}},
+		{ID: "i-1", Type: "ec2", Labels: map[string]string{"env": "dev"}, Attrs: map[string]string{"type": "gp2", "size_gb": "1000"}},
+	}
+	rules.Apply(resources)
+
+	assert.Equal(t, "oversized", resources[0].Attrs["waste"])
+	assert.Equal(t, "large-dev-gp2", resources[0].Attrs["waste_rule"])
+	assert.Equal(t, "0.80", resources[0].Attrs["waste_confidence"])
+	for _, r := range resources[1:] {
+		assert.NotContains(t, r.Attrs, "waste", r.ID)
+	}
+}
+
+func TestRules_FirstMatchWins(t *testing.T) {
+	rules, err := NewRules([]Rule{
+		{Name: "stopped", Type: "ec2", Attrs: map[string]string{"state": "=stopped"}, Waste: "idle", Confidence: 0.9},
+		{Name: "any-non-prod", Type: "ec2", Tags: map[string]string{"env": "!=prod"}, Waste: "review", Confidence: 0.3},
+	}, resource.NewTypeAliases(nil))
+	require.NoError(t, err)
+
+	resources := []resource.Resource{
+		{ID: "i-1", Type: "ec2", Labels: map[string]string{"env": "dev"}, Attrs: map[string]string{"state": "stopped"}},
+		{ID: "i-2", Type: "ec2", Labels: map[string]string{"env": "dev"}},
+		{ID: "i-3", Type: "ec2"},
+	}
+	rules.Apply(resources)
+
+	assert.Equal(t, "idle", resources[0].Attrs["waste"])
+	assert.Equal(t, "review", resources[1].Attrs["waste"])
+	assert.Nil(t, resources[2].Attrs, "unset tag never matches")
+}
+
+func TestRules_NilIsNoop(t *testing.T) {
+	var rules *Rules
+	resources := []resource.Resource{{ID: "i-1", Type: "ec2"}}
+	rules.Apply(resources)
+	assert.Nil(t, resources[0].Attrs)
+}
+
+func TestNewRules_Invalid(t *testing.T) {
+	aliases := resource.NewTypeAliases(nil)
+	tests := []struct {
+		name string
+		rule Rule
+		want string
+	}{
+		{"no name", Rule{Type: "ebs", Waste: "x"}, "name required"},
+		{"no waste", Rule{Name: "r", Type: "ebs"}, "type and waste required"},
+		{"confidence", Rule{Name: "r", Type: "ebs", Waste: "x", Confidence: 2}, "confidence"},
+		{"non-numeric", Rule{Name: "r", Type: "ebs", Waste: "x", Attrs: map[string]string{"size_gb": ">big"}}, "needs a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRules([]Rule{tt.rule}, aliases)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package aws
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerConfig controls the per-scanner circuit breaker.
+type BreakerConfig struct {
+	Threshold int           // consecutive failures that open the breaker (0 = disabled)
+	Cooldown  time.Duration // how long an open breaker skips the scanner
+}
+
+// breaker skips scanners that keep failing, e.g. on a permanently missing
+// permission. After Threshold consecutive failures a scanner is skipped until
+// Cooldown has passed; the next run is a trial that reopens the breaker on
+// failure. Any success resets the scanner's state. A nil breaker allows all.
+type breaker struct {
+	cfg BreakerConfig
+
+	mu        sync.Mutex
+	failures  map[string]int
+	openUntil map[string]time.Time
+}
+
+// newBreaker returns a breaker, or nil when cfg disables it.
+func newBreaker(cfg BreakerConfig) *breaker {
+	if cfg.Threshold <= 0 {
+		return nil
+	}
+	return &breaker{
+		cfg:       cfg,
+		failures:  make(map[string]int),
+		openUntil: make(map[string]time.Time),
+	}
+}
+
+// allow reports whether scanner may run at now.
+func (b *breaker) allow(scanner string, now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	until, open := b.openUntil[scanner]
+	return !open || !now.Before(until)
+}
+
+// record updates scanner's state with the outcome of a run and reports
+// whether the run opened the breaker.
+func (b *breaker) record(scanner string, err error, now time.Time) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		delete(b.failures, scanner)
+		delete(b.openUntil, scanner)
+		return false
+	}
+
+	b.failures[scanner]++
+	if b.failures[scanner] < b.cfg.Threshold {
+		return false
+	}
+	b.openUntil[scanner] = now.Add(b.cfg.Cooldown)
+	return true
+}
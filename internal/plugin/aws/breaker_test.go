@@ -0,0 +1,88 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	errDenied := errors.New("access denied")
+	b := newBreaker(BreakerConfig{Threshold: 3, Cooldown: time.Hour})
+
+	assert.False(t, b.record("s3", errDenied, now))
+	assert.False(t, b.record("s3", errDenied, now))
+	assert.True(t, b.allow("s3", now), "closed below threshold")
+	assert.True(t, b.record("s3", errDenied, now), "third failure opens")
+
+	assert.False(t, b.allow("s3", now.Add(30*time.Minute)))
+	assert.True(t, b.allow("ec2", now), "state is per scanner")
+
+	// After the cooldown a trial run is allowed; another failure reopens.
+	now = now.Add(time.Hour)
+	assert.True(t, b.allow("s3", now))
+	assert.True(t, b.record("s3", errDenied, now))
+	assert.False(t, b.allow("s3", now.Add(time.Minute)))
+
+	// A successful trial closes the breaker and resets the count.
+	now = now.Add(time.Hour)
+	require.True(t, b.allow("s3", now))
+	assert.False(t, b.record("s3", nil, now))
+	assert.False(t, b.record("s3", errDenied, now))
+	assert.True(t, b.allow("s3", now))
+}
+
+func TestBreaker_Disabled(t *testing.T) {
+	b := newBreaker(BreakerConfig{})
+	assert.Nil(t, b)
+	assert.False(t, b.record("s3", errors.New("access denied"), time.Now()))
+	assert.True(t, b.allow("s3", time.Now()))
+}
+
+func TestRunScanners_CircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	calls := 0
+	failing := true
+	scanners := []scanner{
+		{"s3", func(context.Context) ([]resource.Resource, error) {
+			calls++
+			if failing {
+				return nil, errors.New("access denied")
+			}
+			return []resource.Resource{{ID: "bucket", Type: "s3"}}, nil
+		}, false},
+	}
+
+	p := &Plugin{
+		region:         "us-east-1",
+		accountID:      "123456789012",
+		maxConcurrency: 5,
+		now:            func() time.Time { return now },
+		breaker:        newBreaker(BreakerConfig{Threshold: 2, Cooldown: time.Hour}),
+	}
+	scan := func() []resource.Resource {
+		resources, err := p.runScanners(context.Background(), scanners, nil)
+		require.NoError(t, err)
+		return resources
+	}
+
+	scan()
+	scan()
+	assert.Equal(t, 2, calls)
+	scan()
+	assert.Equal(t, 2, calls, "open breaker skips the scanner")
+
+	now = now.Add(time.Hour)
+	failing = false
+	assert.Len(t, scan(), 1, "trial run after cooldown")
+	assert.Equal(t, 3, calls)
+	scan()
+	assert.Equal(t, 4, calls, "success closed the breaker")
+}
@@ -0,0 +1,178 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	cetypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/rs/zerolog/log"
+
+	"github.com/yairfalse/elava/pkg/cost"
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// CostConfig controls per-resource cost lookup via Cost Explorer.
+// Disabled by default: Cost Explorer bills every request, and resource-level
+// data must first be enabled in the billing console.
+type CostConfig struct {
+	Enabled  bool
+	CacheTTL time.Duration // how long fetched costs are reused (0 = 24h)
+}
+
+const (
+	// costLookback is the window Cost Explorer keeps resource-level data for.
+	costLookback = 14 * 24 * time.Hour
+	// costMetric is the Cost Explorer metric reported as monthly_cost.
+	costMetric = "UnblendedCost"
+	// defaultCostCacheTTL matches how often Cost Explorer refreshes its data.
+	defaultCostCacheTTL = 24 * time.Hour
+)
+
+// costCache holds the last Cost Explorer result so repeated scans reuse it.
+type costCache struct {
+	mu        sync.Mutex
+	costs     map[string]float64
+	fetchedAt time.Time
+}
+
+// ResourceCosts returns the billed cost per resource in the plugin's region
+// over the last 14 days, scaled to a 30-day month. Costs are keyed by the
+// Cost Explorer resource ID and, for ARNs, also by the trailing name, which
+// is the ID elava uses for most types. Results are cached for CacheTTL and
+// concurrent callers share a single request.
+func (p *Plugin) ResourceCosts(ctx context.Context) (map[string]float64, error) {
+	p.costs.mu.Lock()
+	defer p.costs.mu.Unlock()
+
+	now := p.clock()
+	ttl := p.cost.CacheTTL
+	if ttl <= 0 {
+		ttl = defaultCostCacheTTL
+	}
+	if p.costs.costs != nil && now.Sub(p.costs.fetchedAt) < ttl {
+		return p.costs.costs, nil
+	}
+
+	costs, err := p.fetchResourceCosts(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	p.costs.costs, p.costs.fetchedAt = costs, now
+	return costs, nil
+}
+
+func (p *Plugin) fetchResourceCosts(ctx context.Context, now time.Time) (map[string]float64, error) {
+	end := now.UTC().Truncate(24 * time.Hour)
+	input := &costexplorer.GetCostAndUsageWithResourcesInput{
+		TimePeriod: &cetypes.DateInterval{
+			Start: aws.String(end.Add(-costLookback).Format(time.DateOnly)),
+			End:   aws.String(end.Format(time.DateOnly)),
+		},
+		Granularity: cetypes.GranularityDaily,
+		Metrics:     []string{costMetric},
+		Filter: &cetypes.Expression{
+			Dimensions: &cetypes.DimensionValues{Key: cetypes.DimensionRegion, Values: []string{p.region}},
+		},
+		GroupBy: []cetypes.GroupDefinition{{Type: cetypes.GroupDefinitionTypeDimension, Key: aws.String("RESOURCE_ID")}},
+	}
+
+	totals := make(map[string]float64)
+	for {
+		output, err := p.costExplorerClient().GetCostAndUsageWithResources(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("get cost and usage with resources: %w", err)
+		}
+
+		for _, period := range output.ResultsByTime {
+			for _, group := range period.Groups {
+				if len(group.Keys) == 0 {
+					continue
+				}
+				amount, err := strconv.ParseFloat(aws.ToString(group.Metrics[costMetric].Amount), 64)
+				if err != nil {
+					continue
+				}
+				totals[group.Keys[0]] += amount
+			}
+		}
+
+		if output.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = output.NextPageToken
+	}
+
+	scale := float64(30*24*time.Hour) / float64(costLookback)
+	costs := make(map[string]float64, len(totals))
+	for id, total := range totals {
+		costs[id] = total * scale
+	}
+	for id, total := range totals {
+		if short := shortResourceID(id); short != id {
+			if _, taken := costs[short]; !taken {
+				costs[short] = total * scale
+			}
+		}
+	}
+	return costs, nil
+}
+
+// shortResourceID returns the part of an ARN after the last '/' or ':',
+// e.g. "my-db" for arn:aws:rds:us-east-1:123456789012:db:my-db.
+func shortResourceID(id string) string {
+	if !strings.HasPrefix(id, "arn:") {
+		return id
+	}
+	return id[strings.LastIndexAny(id, "/:")+1:]
+}
+
+// resourceCosts returns the cost map for this scan, or nil when cost lookup
+// is disabled or fails. Cost is an enrichment, not required.
+func (p *Plugin) resourceCosts(ctx context.Context) map[string]float64 {
+	if !p.cost.Enabled {
+		return nil
+	}
+	costs, err := p.ResourceCosts(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to get resource costs")
+		return nil
+	}
+	return costs
+}
+
+// applyCosts sets monthly_cost (USD) on resources with billed cost.
+func applyCosts(resources []resource.Resource, costs map[string]float64) {
+	for i := range resources {
+		r := &resources[i]
+		c, ok := costs[r.ID]
+		if !ok {
+			continue
+		}
+		if r.Attrs == nil {
+			r.Attrs = make(map[string]string)
+		}
+		r.Attrs["monthly_cost"] = strconv.FormatFloat(c, 'f', 2, 64)
+	}
+}
+
+// applyCostEstimates sets monthly_cost_estimate (USD) from list prices on
+// resources cost.Estimate can price. Unlike monthly_cost it needs no API call.
+func applyCostEstimates(resources []resource.Resource) {
+	for i := range resources {
+		r := &resources[i]
+		est := cost.Estimate(*r)
+		if est == 0 {
+			continue
+		}
+		if r.Attrs == nil {
+			r.Attrs = make(map[string]string)
+		}
+		r.Attrs["monthly_cost_estimate"] = strconv.FormatFloat(est, 'f', 2, 64)
+	}
+}
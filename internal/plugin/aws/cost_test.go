@@ -0,0 +1,163 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	cetypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// mockCostExplorerClient implements CostExplorerAPI for testing.
+type mockCostExplorerClient struct {
+	calls                            int
+	GetCostAndUsageWithResourcesFunc func(ctx context.Context, params *costexplorer.GetCostAndUsageWithResourcesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error)
+}
+
+func (m *mockCostExplorerClient) GetCostAndUsageWithResources(ctx context.Context, params *costexplorer.GetCostAndUsageWithResourcesInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error) {
+	m.calls++
+	return m.GetCostAndUsageWithResourcesFunc(ctx, params, optFns...)
+}
+
+// costGroup returns a Cost Explorer group for one resource.
+func costGroup(id, amount string) cetypes.Group {
+	return cetypes.Group{
+		Keys:    []string{id},
+		Metrics: map[string]cetypes.MetricValue{costMetric: {Amount: aws.String(amount), Unit: aws.String("USD")}},
+	}
+}
+
+func costPlugin(mock *mockCostExplorerClient, now *time.Time) *Plugin {
+	return &Plugin{
+		region:             "us-east-1",
+		accountID:          "123456789012",
+		maxConcurrency:     5,
+		cost:               CostConfig{Enabled: true, CacheTTL: time.Hour},
+		now:                func() time.Time { return *now },
+		costExplorerClient: func() CostExplorerAPI { return mock },
+	}
+}
+
+func TestResourceCosts(t *testing.T) {
+	now := time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+	mock := &mockCostExplorerClient{
+		GetCostAndUsageWithResourcesFunc: func(_ context.Context, in *costexplorer.GetCostAndUsageWithResourcesInput, _ ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error) {
+			assert.Equal(t, "2024-06-01", aws.ToString(in.TimePeriod.Start))
+			assert.Equal(t, "2024-06-15", aws.ToString(in.TimePeriod.End))
+			assert.Equal(t, []string{"us-east-1"}, in.Filter.Dimensions.Values)
+			assert.Equal(t, "RESOURCE_ID", aws.ToString(in.GroupBy[0].Key))
+
+			if in.NextPageToken == nil {
+				return &costexplorer.GetCostAndUsageWithResourcesOutput{
+					ResultsByTime: []cetypes.ResultByTime{
+						{Groups: []cetypes.Group{costGroup("i-abc123", "7.00"), costGroup("arn:aws:rds:us-east-1:123456789012:db:orders", "14.00")}},
+					},
+					NextPageToken: aws.String("page2"),
+				}, nil
+			}
+			return &costexplorer.GetCostAndUsageWithResourcesOutput{
+				ResultsByTime: []cetypes.ResultByTime{
+					{Groups: []cetypes.Group{costGroup("i-abc123", "7.00"), costGroup("vol-1", "not-a-number")}},
+				},
+			}, nil
+		},
+	}
+
+	p := costPlugin(mock, &now)
+	costs, err := p.ResourceCosts(context.Background())
+
+	require.NoError(t, err)
+	// 14 days of spend scaled to 30 days
+	assert.InDelta(t, 30.0, costs["i-abc123"], 0.001)
+	assert.InDelta(t, 30.0, costs["arn:aws:rds:us-east-1:123456789012:db:orders"], 0.001)
+	assert.InDelta(t, 30.0, costs["orders"], 0.001)
+	assert.NotContains(t, costs, "vol-1")
+	assert.Equal(t, 2, mock.calls)
+}
+
+func TestResourceCosts_Cached(t *testing.T) {
+	now := time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+	mock := &mockCostExplorerClient{
+		GetCostAndUsageWithResourcesFunc: func(_ context.Context, _ *costexplorer.GetCostAndUsageWithResourcesInput, _ ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error) {
+			return &costexplorer.GetCostAndUsageWithResourcesOutput{}, nil
+		},
+	}
+	p := costPlugin(mock, &now)
+
+	_, err := p.ResourceCosts(context.Background())
+	require.NoError(t, err)
+	now = now.Add(30 * time.Minute)
+	_, err = p.ResourceCosts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, mock.calls, "within cache_ttl")
+
+	now = now.Add(time.Hour)
+	_, err = p.ResourceCosts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, mock.calls, "after cache_ttl")
+}
+
+func TestRunScanners_AppliesCosts(t *testing.T) {
+	now := time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+	mock := &mockCostExplorerClient{
+		GetCostAndUsageWithResourcesFunc: func(_ context.Context, _ *costexplorer.GetCostAndUsageWithResourcesInput, _ ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error) {
+			return &costexplorer.GetCostAndUsageWithResourcesOutput{
+				ResultsByTime: []cetypes.ResultByTime{{Groups: []cetypes.Group{costGroup("arn:aws:rds:us-east-1:123456789012:db:orders", "65.33")}}},
+			}, nil
+		},
+	}
+	scanners := []scanner{
+		{"rds", func(context.Context) ([]resource.Resource, error) {
+			return []resource.Resource{{ID: "orders", Type: "rds"}, {ID: "reports", Type: "rds"}}, nil
+		}, false},
+	}
+
+	p := costPlugin(mock, &now)
+	resources, err := p.runScanners(context.Background(), scanners, nil)
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "139.99", resources[0].Attrs["monthly_cost"])
+	assert.NotContains(t, resources[1].Attrs, "monthly_cost")
+}
+
+func TestRunScanners_CostErrorIsNotFatal(t *testing.T) {
+	now := time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+	mock := &mockCostExplorerClient{
+		GetCostAndUsageWithResourcesFunc: func(_ context.Context, _ *costexplorer.GetCostAndUsageWithResourcesInput, _ ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageWithResourcesOutput, error) {
+			return nil, errors.New("data unavailable")
+		},
+	}
+	scanners := []scanner{
+		{"rds", func(context.Context) ([]resource.Resource, error) {
+			return []resource.Resource{{ID: "orders", Type: "rds"}}, nil
+		}, false},
+	}
+
+	p := costPlugin(mock, &now)
+	resources, err := p.runScanners(context.Background(), scanners, nil)
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.NotContains(t, resources[0].Attrs, "monthly_cost")
+}
+
+func TestApplyCostEstimates(t *testing.T) {
+	resources := []resource.Resource{
+		{ID: "nat-1", Type: "nat_gateway"},
+		{ID: "vol-1", Type: "ebs", Attrs: map[string]string{"type": "gp3", "size_gb": "250"}},
+		{ID: "fn-1", Type: "lambda"},
+	}
+	applyCostEstimates(resources)
+
+	assert.Equal(t, "32.85", resources[0].Attrs["monthly_cost_estimate"])
+	assert.Equal(t, "20.00", resources[1].Attrs["monthly_cost_estimate"])
+	assert.Nil(t, resources[2].Attrs, "unpriced types are left alone")
+}
@@ -13,14 +13,18 @@ import (
 
 // mockEC2Client implements EC2API for testing.
 type mockEC2Client struct {
-	DescribeInstancesFunc      func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
-	describeVpcsFunc           func(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
-	describeSubnetsFunc        func(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
-	describeSecurityGroupsFunc func(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
-	describeVolumesFunc        func(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
-	describeAddressesFunc      func(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error)
-	describeNatGatewaysFunc    func(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error)
-	describeAccountAttrsFunc   func(ctx context.Context, params *ec2.DescribeAccountAttributesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAccountAttributesOutput, error)
+	DescribeInstancesFunc        func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	describeVpcsFunc             func(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
+	describeSubnetsFunc          func(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+	describeSecurityGroupsFunc   func(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+	describeVolumesFunc          func(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
+	describeAddressesFunc        func(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error)
+	describeNatGatewaysFunc      func(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error)
+	describeSnapshotsFunc        func(ctx context.Context, params *ec2.DescribeSnapshotsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error)
+	describeImagesFunc           func(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error)
+	describeVpcEndpointsFunc     func(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error)
+	describeInternetGatewaysFunc func(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error)
+	describeRouteTablesFunc      func(ctx context.Context, params *ec2.DescribeRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error)
 }
 
 func (m *mockEC2Client) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
@@ -72,11 +76,39 @@ func (m *mockEC2Client) DescribeNatGateways(ctx context.Context, params *ec2.Des
 	return &ec2.DescribeNatGatewaysOutput{}, nil
 }
 
-func (m *mockEC2Client) DescribeAccountAttributes(ctx context.Context, params *ec2.DescribeAccountAttributesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAccountAttributesOutput, error) {
-	if m.describeAccountAttrsFunc != nil {
-		return m.describeAccountAttrsFunc(ctx, params, optFns...)
+func (m *mockEC2Client) DescribeSnapshots(ctx context.Context, params *ec2.DescribeSnapshotsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+	if m.describeSnapshotsFunc != nil {
+		return m.describeSnapshotsFunc(ctx, params, optFns...)
 	}
-	return &ec2.DescribeAccountAttributesOutput{}, nil
+	return &ec2.DescribeSnapshotsOutput{}, nil
+}
+
+func (m *mockEC2Client) DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	if m.describeImagesFunc != nil {
+		return m.describeImagesFunc(ctx, params, optFns...)
+	}
+	return &ec2.DescribeImagesOutput{}, nil
+}
+
+func (m *mockEC2Client) DescribeVpcEndpoints(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error) {
+	if m.describeVpcEndpointsFunc != nil {
+		return m.describeVpcEndpointsFunc(ctx, params, optFns...)
+	}
+	return &ec2.DescribeVpcEndpointsOutput{}, nil
+}
+
+func (m *mockEC2Client) DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+	if m.describeInternetGatewaysFunc != nil {
+		return m.describeInternetGatewaysFunc(ctx, params, optFns...)
+	}
+	return &ec2.DescribeInternetGatewaysOutput{}, nil
+}
+
+func (m *mockEC2Client) DescribeRouteTables(ctx context.Context, params *ec2.DescribeRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error) {
+	if m.describeRouteTablesFunc != nil {
+		return m.describeRouteTablesFunc(ctx, params, optFns...)
+	}
+	return &ec2.DescribeRouteTablesOutput{}, nil
 }
 
 func newTestInstance() types.Instance {
@@ -119,6 +151,31 @@ func TestScanEC2(t *testing.T) {
 	assert.Equal(t, "t2.micro", r.Attrs["instance_type"])
 }
 
+func TestScanEC2_MissingFields(t *testing.T) {
+	instance := newTestInstance()
+	instance.State = nil
+	instance.Placement = nil
+	mock := &mockEC2Client{
+		DescribeInstancesFunc: func(_ context.Context, _ *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+			return &ec2.DescribeInstancesOutput{
+				Reservations: []types.Reservation{{Instances: []types.Instance{instance}}},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", ec2Client: func() EC2API { return mock }}
+	resources, err := p.scanEC2(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	r := resources[0]
+	assert.Equal(t, "i-abc123", r.ID)
+	assert.Equal(t, "unknown", r.Status)
+	assert.Equal(t, "true", r.Attrs["partial_data"])
+	assert.NotContains(t, r.Attrs, "az")
+}
+
 func TestScanEC2_Empty(t *testing.T) {
 	mock := &mockEC2Client{
 		DescribeInstancesFunc: func(_ context.Context, _ *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
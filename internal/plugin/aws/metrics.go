@@ -0,0 +1,209 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	ectypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/rs/zerolog/log"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// IdleConfig controls CloudWatch-based idle detection.
+// Disabled by default: every enriched resource costs one GetMetricStatistics call.
+type IdleConfig struct {
+	Enabled                  bool
+	Window                   time.Duration // how far back to look at metrics
+	ELBRequestThreshold      float64       // at or below this many requests/new flows an ELB is idle
+	CacheConnectionThreshold float64       // at or below this many peak connections a cache cluster may be idle
+}
+
+// metricPeriod is the CloudWatch aggregation period. One hour keeps us under
+// the 1440 datapoint limit for windows up to config.MaxIdleWindow (60 days).
+const metricPeriod = 3600
+
+// sumMetric returns the sum of a CloudWatch metric over the idle window.
+// found is false when there were no datapoints, i.e. nothing is known.
+func (p *Plugin) sumMetric(ctx context.Context, namespace, metricName string, dims []cwtypes.Dimension) (sum float64, found bool, err error) {
+	datapoints, err := p.metricDatapoints(ctx, namespace, metricName, dims, cwtypes.StatisticSum)
+	if err != nil {
+		return 0, false, err
+	}
+
+	for _, dp := range datapoints {
+		sum += aws.ToFloat64(dp.Sum)
+	}
+	return sum, len(datapoints) > 0, nil
+}
+
+// maxMetric returns the peak of a CloudWatch metric over the idle window.
+// found is false when there were no datapoints, i.e. nothing is known.
+func (p *Plugin) maxMetric(ctx context.Context, namespace, metricName string, dims []cwtypes.Dimension) (peak float64, found bool, err error) {
+	datapoints, err := p.metricDatapoints(ctx, namespace, metricName, dims, cwtypes.StatisticMaximum)
+	if err != nil {
+		return 0, false, err
+	}
+
+	for _, dp := range datapoints {
+		peak = max(peak, aws.ToFloat64(dp.Maximum))
+	}
+	return peak, len(datapoints) > 0, nil
+}
+
+// newerThanIdleWindow reports whether r was created inside the idle window,
+// too recently for a lack of traffic to mean it is idle.
+func (p *Plugin) newerThanIdleWindow(r *resource.Resource) bool {
+	return !r.CreatedAt.IsZero() && p.clock().Sub(r.CreatedAt) < p.idle.Window
+}
+
+// metricDatapoints fetches hourly datapoints for one statistic over the idle window.
+func (p *Plugin) metricDatapoints(ctx context.Context, namespace, metricName string, dims []cwtypes.Dimension, stat cwtypes.Statistic) ([]cwtypes.Datapoint, error) {
+	end := p.clock()
+	output, err := p.cloudwatchClient().GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String(namespace),
+		MetricName: aws.String(metricName),
+		Dimensions: dims,
+		StartTime:  aws.Time(end.Add(-p.idle.Window)),
+		EndTime:    aws.Time(end),
+		Period:     aws.Int32(metricPeriod),
+		Statistics: []cwtypes.Statistic{stat},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get metric statistics %s/%s: %w", namespace, metricName, err)
+	}
+	return output.Datapoints, nil
+}
+
+// enrichELBTraffic records request/flow counts and flags ELBs without traffic as idle.
+// ALBs are measured by RequestCount, NLBs by NewFlowCount, both summed over
+// the window. Other types, ELBs younger than the window and ELBs without
+// datapoints are left without idle attrs.
+func (p *Plugin) enrichELBTraffic(ctx context.Context, r *resource.Resource, lb elbtypes.LoadBalancer) {
+	var namespace, metricName, attr string
+	switch lb.Type {
+	case elbtypes.LoadBalancerTypeEnumApplication:
+		namespace, metricName, attr = "AWS/ApplicationELB", "RequestCount", "request_count"
+	case elbtypes.LoadBalancerTypeEnumNetwork:
+		namespace, metricName, attr = "AWS/NetworkELB", "NewFlowCount", "new_flow_count"
+	default:
+		return
+	}
+	if p.newerThanIdleWindow(r) {
+		return
+	}
+
+	dims := []cwtypes.Dimension{{
+		Name:  aws.String("LoadBalancer"),
+		Value: aws.String(elbMetricDimension(aws.ToString(lb.LoadBalancerArn))),
+	}}
+	sum, found, err := p.sumMetric(ctx, namespace, metricName, dims)
+	if err != nil {
+		log.Warn().Err(err).Str("elb", aws.ToString(lb.LoadBalancerName)).Msg("failed to get elb traffic")
+		return
+	}
+	if !found {
+		return
+	}
+
+	r.Attrs[attr] = strconv.FormatFloat(sum, 'f', -1, 64)
+	r.Attrs["idle"] = strconv.FormatBool(sum <= p.idle.ELBRequestThreshold)
+}
+
+// elbMetricDimension extracts the CloudWatch LoadBalancer dimension from an ELB ARN.
+// "arn:...:loadbalancer/app/my-alb/abc" -> "app/my-alb/abc"
+func elbMetricDimension(arn string) string {
+	const marker = ":loadbalancer/"
+	if i := strings.Index(arn, marker); i >= 0 {
+		return arn[i+len(marker):]
+	}
+	return arn
+}
+
+// enrichElastiCacheActivity records peak connections and cache hits, flagging
+// clusters with no hits and connections at or below the threshold as idle.
+// Memcached reports hits as GetHits, Redis/Valkey as CacheHits.
+func (p *Plugin) enrichElastiCacheActivity(ctx context.Context, r *resource.Resource, cluster ectypes.CacheCluster) {
+	hitsMetric := "CacheHits"
+	if aws.ToString(cluster.Engine) == "memcached" {
+		hitsMetric = "GetHits"
+	}
+	p.enrichCacheActivity(ctx, r, "AWS/ElastiCache", hitsMetric, cwtypes.Dimension{
+		Name:  aws.String("CacheClusterId"),
+		Value: cluster.CacheClusterId,
+	})
+}
+
+// enrichMemoryDBActivity is enrichElastiCacheActivity for MemoryDB, which
+// reports hits as KeyspaceHits.
+func (p *Plugin) enrichMemoryDBActivity(ctx context.Context, r *resource.Resource) {
+	p.enrichCacheActivity(ctx, r, "AWS/MemoryDB", "KeyspaceHits", cwtypes.Dimension{
+		Name:  aws.String("ClusterName"),
+		Value: aws.String(r.ID),
+	})
+}
+
+// enrichCacheActivity records peak CurrConnections and the sum of hitsMetric
+// in namespace, flagging clusters with no hits and connections at or below
+// the threshold as idle. Clusters younger than the window or without
+// datapoints for either metric are left without idle attrs.
+func (p *Plugin) enrichCacheActivity(ctx context.Context, r *resource.Resource, namespace, hitsMetric string, dim cwtypes.Dimension) {
+	if p.newerThanIdleWindow(r) {
+		return
+	}
+	dims := []cwtypes.Dimension{dim}
+
+	connections, found, err := p.maxMetric(ctx, namespace, "CurrConnections", dims)
+	if err != nil {
+		log.Warn().Err(err).Str("namespace", namespace).Str("cluster", r.ID).Msg("failed to get cache connections")
+		return
+	}
+	if !found {
+		return
+	}
+
+	hits, found, err := p.sumMetric(ctx, namespace, hitsMetric, dims)
+	if err != nil {
+		log.Warn().Err(err).Str("namespace", namespace).Str("cluster", r.ID).Msg("failed to get cache hits")
+		return
+	}
+	if !found {
+		return
+	}
+
+	r.Attrs["max_connections"] = strconv.FormatFloat(connections, 'f', -1, 64)
+	r.Attrs["cache_hits"] = strconv.FormatFloat(hits, 'f', -1, 64)
+	r.Attrs["idle"] = strconv.FormatBool(hits == 0 && connections <= p.idle.CacheConnectionThreshold)
+}
+
+// enrichRDSConnections records peak DatabaseConnections and flags instances
+// that had no connections over the idle window as idle. Instances younger
+// than the window or without datapoints are left without idle attrs.
+func (p *Plugin) enrichRDSConnections(ctx context.Context, r *resource.Resource) {
+	if p.newerThanIdleWindow(r) {
+		return
+	}
+	dims := []cwtypes.Dimension{{
+		Name:  aws.String("DBInstanceIdentifier"),
+		Value: aws.String(r.ID),
+	}}
+
+	connections, found, err := p.maxMetric(ctx, "AWS/RDS", "DatabaseConnections", dims)
+	if err != nil {
+		log.Warn().Err(err).Str("db", r.ID).Msg("failed to get rds connections")
+		return
+	}
+	if !found {
+		return
+	}
+
+	r.Attrs["max_connections"] = strconv.FormatFloat(connections, 'f', -1, 64)
+	r.Attrs["idle"] = strconv.FormatBool(connections == 0)
+}
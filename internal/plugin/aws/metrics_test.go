@@ -0,0 +1,385 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	ectypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/memorydb"
+	mdbtypes "github.com/aws/aws-sdk-go-v2/service/memorydb/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCloudWatchClient implements CloudWatchAPI for testing.
+type mockCloudWatchClient struct {
+	GetMetricStatisticsFunc func(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error)
+}
+
+func (m *mockCloudWatchClient) GetMetricStatistics(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+	return m.GetMetricStatisticsFunc(ctx, params, optFns...)
+}
+
+// datapoints returns hourly datapoints with the given sums.
+func datapoints(sums ...float64) []cwtypes.Datapoint {
+	dps := make([]cwtypes.Datapoint, 0, len(sums))
+	for _, s := range sums {
+		dps = append(dps, cwtypes.Datapoint{Sum: aws.Float64(s)})
+	}
+	return dps
+}
+
+func TestScanELB_IdleDetection(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	elbMock := &mockELBClient{
+		DescribeLoadBalancersFunc: func(_ context.Context, _ *elasticloadbalancingv2.DescribeLoadBalancersInput, _ ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+			return &elasticloadbalancingv2.DescribeLoadBalancersOutput{
+				LoadBalancers: []elbtypes.LoadBalancer{
+					{
+						LoadBalancerArn:  aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/quiet-alb/abc"),
+						LoadBalancerName: aws.String("quiet-alb"),
+						Type:             elbtypes.LoadBalancerTypeEnumApplication,
+					},
+					{
+						LoadBalancerArn:  aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/busy-nlb/def"),
+						LoadBalancerName: aws.String("busy-nlb"),
+						Type:             elbtypes.LoadBalancerTypeEnumNetwork,
+					},
+					{
+						LoadBalancerArn:  aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/silent-alb/ghi"),
+						LoadBalancerName: aws.String("silent-alb"),
+						Type:             elbtypes.LoadBalancerTypeEnumApplication,
+					},
+					{
+						LoadBalancerArn:  aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/new-alb/jkl"),
+						LoadBalancerName: aws.String("new-alb"),
+						Type:             elbtypes.LoadBalancerTypeEnumApplication,
+						CreatedTime:      aws.Time(now.Add(-10 * time.Minute)),
+					},
+				},
+			}, nil
+		},
+	}
+
+	var calls []string
+	cwMock := &mockCloudWatchClient{
+		GetMetricStatisticsFunc: func(_ context.Context, params *cloudwatch.GetMetricStatisticsInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+			calls = append(calls, aws.ToString(params.Namespace)+"/"+aws.ToString(params.MetricName))
+			switch aws.ToString(params.Dimensions[0].Value) {
+			case "app/quiet-alb/abc":
+				return &cloudwatch.GetMetricStatisticsOutput{Datapoints: datapoints(0, 0)}, nil
+			case "net/busy-nlb/def":
+				return &cloudwatch.GetMetricStatisticsOutput{Datapoints: datapoints(120, 80)}, nil
+			}
+			return &cloudwatch.GetMetricStatisticsOutput{}, nil
+		},
+	}
+
+	p := &Plugin{
+		region:           "us-east-1",
+		accountID:        "123456789012",
+		now:              func() time.Time { return now },
+		idle:             IdleConfig{Enabled: true, Window: 7 * 24 * time.Hour},
+		elbClient:        func() ELBAPI { return elbMock },
+		cloudwatchClient: func() CloudWatchAPI { return cwMock },
+	}
+	resources, err := p.scanELB(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 4)
+	assert.Equal(t, []string{"AWS/ApplicationELB/RequestCount", "AWS/NetworkELB/NewFlowCount", "AWS/ApplicationELB/RequestCount"}, calls,
+		"the new ALB is not queried")
+
+	quiet := resources[0]
+	assert.Equal(t, "0", quiet.Attrs["request_count"])
+	assert.Equal(t, "true", quiet.Attrs["idle"])
+
+	busy := resources[1]
+	assert.Equal(t, "200", busy.Attrs["new_flow_count"])
+	assert.Equal(t, "false", busy.Attrs["idle"])
+
+	for _, r := range resources[2:] {
+		assert.NotContains(t, r.Attrs, "request_count", r.Name)
+		assert.NotContains(t, r.Attrs, "idle", r.Name)
+	}
+}
+
+func TestScanELB_IdleThreshold(t *testing.T) {
+	elbMock := &mockELBClient{
+		DescribeLoadBalancersFunc: func(_ context.Context, _ *elasticloadbalancingv2.DescribeLoadBalancersInput, _ ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+			return &elasticloadbalancingv2.DescribeLoadBalancersOutput{
+				LoadBalancers: []elbtypes.LoadBalancer{{
+					LoadBalancerArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/health-checked/abc"),
+					Type:            elbtypes.LoadBalancerTypeEnumApplication,
+				}},
+			}, nil
+		},
+	}
+	cwMock := &mockCloudWatchClient{
+		GetMetricStatisticsFunc: func(_ context.Context, _ *cloudwatch.GetMetricStatisticsInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+			return &cloudwatch.GetMetricStatisticsOutput{Datapoints: datapoints(3, 2)}, nil
+		},
+	}
+
+	p := &Plugin{
+		region:           "us-east-1",
+		accountID:        "123456789012",
+		idle:             IdleConfig{Enabled: true, Window: 24 * time.Hour, ELBRequestThreshold: 10},
+		elbClient:        func() ELBAPI { return elbMock },
+		cloudwatchClient: func() CloudWatchAPI { return cwMock },
+	}
+	resources, err := p.scanELB(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "5", resources[0].Attrs["request_count"])
+	assert.Equal(t, "true", resources[0].Attrs["idle"])
+}
+
+func TestScanELB_IdleMetricError(t *testing.T) {
+	elbMock := &mockELBClient{
+		DescribeLoadBalancersFunc: func(_ context.Context, _ *elasticloadbalancingv2.DescribeLoadBalancersInput, _ ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+			return &elasticloadbalancingv2.DescribeLoadBalancersOutput{
+				LoadBalancers: []elbtypes.LoadBalancer{{
+					LoadBalancerArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-alb/abc"),
+					Type:            elbtypes.LoadBalancerTypeEnumApplication,
+				}},
+			}, nil
+		},
+	}
+	cwMock := &mockCloudWatchClient{
+		GetMetricStatisticsFunc: func(_ context.Context, _ *cloudwatch.GetMetricStatisticsInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+
+	p := &Plugin{
+		region:           "us-east-1",
+		accountID:        "123456789012",
+		idle:             IdleConfig{Enabled: true, Window: time.Hour},
+		elbClient:        func() ELBAPI { return elbMock },
+		cloudwatchClient: func() CloudWatchAPI { return cwMock },
+	}
+	resources, err := p.scanELB(context.Background())
+
+	// Metric failures must not fail the scan - the ELB is still reported
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.NotContains(t, resources[0].Attrs, "idle")
+}
+
+func TestElbMetricDimension(t *testing.T) {
+	assert.Equal(t, "app/my-alb/abc", elbMetricDimension("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-alb/abc"))
+	assert.Equal(t, "not-an-arn", elbMetricDimension("not-an-arn"))
+}
+
+func TestScanElastiCache_IdleDetection(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	ecMock := &mockElastiCacheClient{
+		DescribeCacheClustersFunc: func(_ context.Context, _ *elasticache.DescribeCacheClustersInput, _ ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
+			return &elasticache.DescribeCacheClustersOutput{
+				CacheClusters: []ectypes.CacheCluster{
+					{CacheClusterId: aws.String("idle-redis"), Engine: aws.String("redis")},
+					{CacheClusterId: aws.String("busy-memcached"), Engine: aws.String("memcached")},
+					{CacheClusterId: aws.String("unmeasured-redis"), Engine: aws.String("redis")},
+					{CacheClusterId: aws.String("new-redis"), Engine: aws.String("redis"), CacheClusterCreateTime: aws.Time(now.Add(-time.Hour))},
+				},
+			}, nil
+		},
+	}
+
+	var calls []string
+	cwMock := &mockCloudWatchClient{
+		GetMetricStatisticsFunc: func(_ context.Context, params *cloudwatch.GetMetricStatisticsInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+			cluster := aws.ToString(params.Dimensions[0].Value)
+			metric := aws.ToString(params.MetricName)
+			calls = append(calls, cluster+"/"+metric)
+
+			switch {
+			case cluster == "unmeasured-redis":
+				return &cloudwatch.GetMetricStatisticsOutput{}, nil
+			case metric == "CurrConnections" && cluster == "idle-redis":
+				return &cloudwatch.GetMetricStatisticsOutput{Datapoints: []cwtypes.Datapoint{{Maximum: aws.Float64(1)}, {Maximum: aws.Float64(2)}}}, nil
+			case metric == "CurrConnections":
+				return &cloudwatch.GetMetricStatisticsOutput{Datapoints: []cwtypes.Datapoint{{Maximum: aws.Float64(40)}, {Maximum: aws.Float64(85)}}}, nil
+			case cluster == "busy-memcached":
+				return &cloudwatch.GetMetricStatisticsOutput{Datapoints: datapoints(5000, 7000)}, nil
+			}
+			return &cloudwatch.GetMetricStatisticsOutput{Datapoints: datapoints(0, 0)}, nil
+		},
+	}
+
+	p := &Plugin{
+		region:            "us-east-1",
+		accountID:         "123456789012",
+		idle:              IdleConfig{Enabled: true, Window: 7 * 24 * time.Hour, CacheConnectionThreshold: 2},
+		elasticacheClient: func() ElastiCacheAPI { return ecMock },
+		cloudwatchClient:  func() CloudWatchAPI { return cwMock },
+		now:               func() time.Time { return now },
+	}
+	resources, err := p.scanElastiCache(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 4)
+	assert.Equal(t, []string{
+		"idle-redis/CurrConnections", "idle-redis/CacheHits",
+		"busy-memcached/CurrConnections", "busy-memcached/GetHits",
+		"unmeasured-redis/CurrConnections",
+	}, calls, "clusters younger than the window are not queried")
+
+	idle := resources[0]
+	assert.Equal(t, "2", idle.Attrs["max_connections"])
+	assert.Equal(t, "0", idle.Attrs["cache_hits"])
+	assert.Equal(t, "true", idle.Attrs["idle"])
+
+	busy := resources[1]
+	assert.Equal(t, "85", busy.Attrs["max_connections"])
+	assert.Equal(t, "12000", busy.Attrs["cache_hits"])
+	assert.Equal(t, "false", busy.Attrs["idle"])
+
+	for _, r := range resources[2:] {
+		assert.NotContains(t, r.Attrs, "idle", r.ID)
+		assert.NotContains(t, r.Attrs, "max_connections", r.ID)
+	}
+}
+
+func TestScanMemoryDB_IdleDetection(t *testing.T) {
+	mdbMock := &mockMemoryDBClient{
+		DescribeClustersFunc: func(_ context.Context, _ *memorydb.DescribeClustersInput, _ ...func(*memorydb.Options)) (*memorydb.DescribeClustersOutput, error) {
+			return &memorydb.DescribeClustersOutput{
+				Clusters: []mdbtypes.Cluster{
+					{Name: aws.String("idle-mdb"), Engine: aws.String("valkey")},
+					{Name: aws.String("busy-mdb"), Engine: aws.String("redis")},
+				},
+			}, nil
+		},
+	}
+
+	var calls []string
+	cwMock := &mockCloudWatchClient{
+		GetMetricStatisticsFunc: func(_ context.Context, params *cloudwatch.GetMetricStatisticsInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+			cluster := aws.ToString(params.Dimensions[0].Value)
+			metric := aws.ToString(params.MetricName)
+			calls = append(calls, aws.ToString(params.Namespace)+"/"+aws.ToString(params.Dimensions[0].Name)+"/"+cluster+"/"+metric)
+
+			switch {
+			case metric == "CurrConnections" && cluster == "idle-mdb":
+				return &cloudwatch.GetMetricStatisticsOutput{Datapoints: []cwtypes.Datapoint{{Maximum: aws.Float64(0)}}}, nil
+			case metric == "CurrConnections":
+				return &cloudwatch.GetMetricStatisticsOutput{Datapoints: []cwtypes.Datapoint{{Maximum: aws.Float64(12)}}}, nil
+			case cluster == "busy-mdb":
+				return &cloudwatch.GetMetricStatisticsOutput{Datapoints: datapoints(300, 200)}, nil
+			}
+			return &cloudwatch.GetMetricStatisticsOutput{Datapoints: datapoints(0)}, nil
+		},
+	}
+
+	p := &Plugin{
+		region:           "us-east-1",
+		accountID:        "123456789012",
+		idle:             IdleConfig{Enabled: true, Window: 7 * 24 * time.Hour},
+		memorydbClient:   func() MemoryDBAPI { return mdbMock },
+		cloudwatchClient: func() CloudWatchAPI { return cwMock },
+	}
+	resources, err := p.scanMemoryDB(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, []string{
+		"AWS/MemoryDB/ClusterName/idle-mdb/CurrConnections", "AWS/MemoryDB/ClusterName/idle-mdb/KeyspaceHits",
+		"AWS/MemoryDB/ClusterName/busy-mdb/CurrConnections", "AWS/MemoryDB/ClusterName/busy-mdb/KeyspaceHits",
+	}, calls)
+
+	assert.Equal(t, "true", resources[0].Attrs["idle"])
+	assert.Equal(t, "0", resources[0].Attrs["cache_hits"])
+	assert.Equal(t, "false", resources[1].Attrs["idle"])
+	assert.Equal(t, "500", resources[1].Attrs["cache_hits"])
+	assert.Equal(t, "12", resources[1].Attrs["max_connections"])
+}
+
+func TestScanElastiCache_IdleDisabled(t *testing.T) {
+	ecMock := &mockElastiCacheClient{
+		DescribeCacheClustersFunc: func(_ context.Context, _ *elasticache.DescribeCacheClustersInput, _ ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
+			return &elasticache.DescribeCacheClustersOutput{
+				CacheClusters: []ectypes.CacheCluster{{CacheClusterId: aws.String("redis-1")}},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", elasticacheClient: func() ElastiCacheAPI { return ecMock }}
+	resources, err := p.scanElastiCache(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.NotContains(t, resources[0].Attrs, "idle")
+}
+
+func TestScanRDS_IdleDetection(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	rdsMock := &mockRDSClient{
+		DescribeDBInstancesFunc: func(_ context.Context, _ *rds.DescribeDBInstancesInput, _ ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+			return &rds.DescribeDBInstancesOutput{
+				DBInstances: []rdstypes.DBInstance{
+					{DBInstanceIdentifier: aws.String("forgotten-db"), DBInstanceStatus: aws.String("available")},
+					{DBInstanceIdentifier: aws.String("app-db"), DBInstanceStatus: aws.String("available")},
+					{DBInstanceIdentifier: aws.String("unmeasured-db"), DBInstanceStatus: aws.String("available")},
+					{DBInstanceIdentifier: aws.String("new-db"), DBInstanceStatus: aws.String("available"), InstanceCreateTime: aws.Time(now.Add(-2 * time.Hour))},
+				},
+			}, nil
+		},
+	}
+
+	var queried []string
+	cwMock := &mockCloudWatchClient{
+		GetMetricStatisticsFunc: func(_ context.Context, params *cloudwatch.GetMetricStatisticsInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+			assert.Equal(t, "AWS/RDS", aws.ToString(params.Namespace))
+			assert.Equal(t, "DatabaseConnections", aws.ToString(params.MetricName))
+			db := aws.ToString(params.Dimensions[0].Value)
+			queried = append(queried, db)
+			switch db {
+			case "forgotten-db":
+				return &cloudwatch.GetMetricStatisticsOutput{Datapoints: []cwtypes.Datapoint{{Maximum: aws.Float64(0)}, {Maximum: aws.Float64(0)}}}, nil
+			case "app-db":
+				return &cloudwatch.GetMetricStatisticsOutput{Datapoints: []cwtypes.Datapoint{{Maximum: aws.Float64(14)}}}, nil
+			}
+			return &cloudwatch.GetMetricStatisticsOutput{}, nil
+		},
+	}
+
+	p := &Plugin{
+		region:           "us-east-1",
+		accountID:        "123456789012",
+		now:              func() time.Time { return now },
+		idle:             IdleConfig{Enabled: true, Window: 7 * 24 * time.Hour},
+		rdsClient:        func() RDSAPI { return rdsMock },
+		cloudwatchClient: func() CloudWatchAPI { return cwMock },
+	}
+	resources, err := p.scanRDS(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 4)
+	assert.Equal(t, []string{"forgotten-db", "app-db", "unmeasured-db"}, queried, "new-db is younger than the window")
+
+	forgotten := resources[0]
+	assert.Equal(t, "0", forgotten.Attrs["max_connections"])
+	assert.Equal(t, "true", forgotten.Attrs["idle"])
+
+	app := resources[1]
+	assert.Equal(t, "14", app.Attrs["max_connections"])
+	assert.Equal(t, "false", app.Attrs["idle"])
+
+	for _, r := range resources[2:] {
+		assert.NotContains(t, r.Attrs, "max_connections", r.ID)
+		assert.NotContains(t, r.Attrs, "idle", r.ID)
+	}
+}
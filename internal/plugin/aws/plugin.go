@@ -2,30 +2,43 @@
 package aws
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
 	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
 	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/elasticache"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/emr"
 	"github.com/aws/aws-sdk-go-v2/service/glue"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/kafka"
 	"github.com/aws/aws-sdk-go-v2/service/kinesis"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/memorydb"
 	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	"github.com/aws/aws-sdk-go-v2/service/ram"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	"github.com/aws/aws-sdk-go-v2/service/redshift"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
@@ -35,10 +48,14 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/workspaces"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/semaphore"
 
 	"github.com/yairfalse/elava/internal/filter"
+	"github.com/yairfalse/elava/pkg/cost"
 	"github.com/yairfalse/elava/pkg/resource"
 )
 
@@ -48,11 +65,22 @@ type Plugin struct {
 	accountID       string
 	maxConcurrency  int64
 	filter          *filter.Filter
+	wasteRules      *filter.Rules
+	waste           WasteConfig
 	scanGlobalTypes bool // true = scan global types (IAM, Route53, CloudFront, S3)
+	failFast        bool // true = abort the scan on the first scanner error
+	idle            IdleConfig
+	s3Enrich        bool // true = fetch versioning/encryption/public access/lifecycle per bucket
+	skipPartial     bool // true = drop resources marked partial_data
+	cost            CostConfig
+	costs           costCache
+	breaker         *breaker         // skips repeatedly failing scanners (nil = disabled)
+	now             func() time.Time // clock for timestamps and age checks (nil = time.Now)
 
 	// AWS clients - lazy initialized via sync.OnceValue for efficiency
 	// Only clients that are actually used get created
 	ec2Client            func() EC2API
+	tgwClient            func() TransitGatewayAPI
 	rdsClient            func() RDSAPI
 	elbClient            func() ELBAPI
 	s3Client             func() S3API
@@ -68,6 +96,7 @@ type Plugin struct {
 	snsClient            func() SNSAPI
 	cloudfrontClient     func() CloudFrontAPI
 	elasticacheClient    func() ElastiCacheAPI
+	memorydbClient       func() MemoryDBAPI
 	secretsmanagerClient func() SecretsManagerAPI
 	acmClient            func() ACMAPI
 	apigatewayClient     func() APIGatewayAPI
@@ -77,25 +106,142 @@ type Plugin struct {
 	glueClient           func() GlueAPI
 	opensearchClient     func() OpenSearchAPI
 	mskClient            func() MSKAPI
+	emrClient            func() EMRAPI
+	workspacesClient     func() WorkSpacesAPI
+	ramClient            func() RAMAPI
+	ecrClient            func() ECRAPI
+	cloudwatchClient     func() CloudWatchAPI
+	costExplorerClient   func() CostExplorerAPI
 }
 
 // Config holds AWS plugin configuration.
 type Config struct {
 	Region          string
+	Profile         string   // named profile from ~/.aws/config (empty = default chain)
+	AssumeRoles     []string // role ARNs assumed in order, each with the previous credentials
 	MaxConcurrency  int
 	Filter          *filter.Filter
 	ScanGlobalTypes bool // true = scan global types (set for first region only)
+	FailFast        bool // true = abort the scan on the first scanner error
+	Idle            IdleConfig
+	S3Enrich        bool // true = fetch versioning/encryption/public access/lifecycle per bucket
+	SkipPartial     bool // true = drop resources built from incomplete API responses
+	Cost            CostConfig
+	Retry           RetryConfig
+	Breaker         BreakerConfig
+	WasteRules      *filter.Rules // user-defined waste rules (nil = none)
+	Waste           WasteConfig
+}
+
+// WasteConfig overrides how long a resource may go unused or unchanged
+// before scanners flag it. Zero keeps the default.
+type WasteConfig struct {
+	SnapshotUnmanagedAge time.Duration // manual snapshots older than this are unmanaged (0 = 30 days)
+	AMIObsoleteAge       time.Duration // AMIs older than this are obsolete (0 = 90 days)
+	LambdaStaleAge       time.Duration // functions not modified within this are stale (0 = 30 days)
+	IAMRoleUnusedAge     time.Duration // roles not used within this are unused (0 = 90 days)
+	ECRStaleAge          time.Duration // repositories not pushed to within this are idle (0 = 90 days)
+	UntaggedAge          time.Duration // resources without an ownership tag after this are untagged (0 = 3 days)
+}
+
+func (c WasteConfig) snapshotUnmanagedAge() time.Duration {
+	return cmp.Or(c.SnapshotUnmanagedAge, defaultSnapshotUnmanagedAge)
+}
+
+func (c WasteConfig) amiObsoleteAge() time.Duration {
+	return cmp.Or(c.AMIObsoleteAge, defaultAMIObsoleteAge)
+}
+
+func (c WasteConfig) lambdaStaleAge() time.Duration {
+	return cmp.Or(c.LambdaStaleAge, defaultLambdaStaleAge)
+}
+
+func (c WasteConfig) iamRoleUnusedAge() time.Duration {
+	return cmp.Or(c.IAMRoleUnusedAge, defaultIAMRoleUnusedAge)
+}
+
+func (c WasteConfig) ecrStaleAge() time.Duration {
+	return cmp.Or(c.ECRStaleAge, defaultECRStaleAge)
+}
+
+func (c WasteConfig) untaggedAge() time.Duration {
+	return cmp.Or(c.UntaggedAge, defaultUntaggedAge)
+}
+
+// RetryConfig controls retries of throttled and transient AWS API errors.
+type RetryConfig struct {
+	MaxRetries int           // retries per API call (0 = no retries)
+	BaseDelay  time.Duration // backoff before the first retry, doubled per attempt (0 = 100ms)
+	MaxDelay   time.Duration // backoff cap (0 = 20s)
+}
+
+// Retry backoff defaults, matching the SDK's standard retryer cap.
+const (
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 20 * time.Second
+)
+
+// newRetryer builds a standard SDK retryer using the configured attempts and backoff.
+func newRetryer(cfg RetryConfig) aws.Retryer {
+	b := exponentialBackoff{base: cfg.BaseDelay, max: cfg.MaxDelay}
+	if b.base <= 0 {
+		b.base = defaultRetryBaseDelay
+	}
+	if b.max <= 0 {
+		b.max = defaultRetryMaxDelay
+	}
+	return retry.NewStandard(func(o *retry.StandardOptions) {
+		o.MaxAttempts = cfg.MaxRetries + 1
+		o.Backoff = b
+	})
+}
+
+// exponentialBackoff doubles the delay from base on every attempt, capped at
+// max, with equal jitter: the delay is uniformly drawn from [d/2, d].
+type exponentialBackoff struct {
+	base, max time.Duration
+}
+
+// BackoffDelay implements retry.BackoffDelayer. attempt starts at 1.
+func (b exponentialBackoff) BackoffDelay(attempt int, _ error) (time.Duration, error) {
+	d := b.base
+	for i := 1; i < attempt && d < b.max; i++ {
+		d *= 2
+	}
+	d = min(d, b.max)
+	half := d / 2
+	return half + rand.N(d-half+1), nil
+}
+
+// loadOptions builds the SDK config load options for the plugin config.
+func loadOptions(cfg Config) []func(*config.LoadOptions) error {
+	retryCfg := cfg.Retry
+	opts := []func(*config.LoadOptions) error{
+		config.WithRegion(cfg.Region),
+		config.WithRetryer(func() aws.Retryer { return newRetryer(retryCfg) }),
+	}
+	if cfg.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(cfg.Profile))
+	}
+	return opts
 }
 
 // New creates a new AWS plugin.
 func New(ctx context.Context, cfg Config) (*Plugin, error) {
-	awsCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(cfg.Region))
+	awsCfg, err := config.LoadDefaultConfig(ctx, loadOptions(cfg)...)
 	if err != nil {
 		return nil, fmt.Errorf("load aws config: %w", err)
 	}
 
-	// Get account ID using STS
-	accountID, err := getAccountID(ctx, awsCfg)
+	awsCfg, err = chainRoles(ctx, awsCfg, cfg.AssumeRoles, func(c aws.Config) stscreds.AssumeRoleAPIClient {
+		return sts.NewFromConfig(c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Get account ID using STS, falling back to EC2
+	accountID, err := getAccountID(ctx, sts.NewFromConfig(awsCfg), ec2.NewFromConfig(awsCfg))
 	if err != nil {
 		return nil, fmt.Errorf("get account id: %w", err)
 	}
@@ -110,8 +256,17 @@ func New(ctx context.Context, cfg Config) (*Plugin, error) {
 		accountID:            accountID,
 		maxConcurrency:       maxConcurrency,
 		filter:               cfg.Filter,
+		wasteRules:           cfg.WasteRules,
+		waste:                cfg.Waste,
 		scanGlobalTypes:      cfg.ScanGlobalTypes,
+		failFast:             cfg.FailFast,
+		idle:                 cfg.Idle,
+		s3Enrich:             cfg.S3Enrich,
+		skipPartial:          cfg.SkipPartial,
+		cost:                 cfg.Cost,
+		breaker:              newBreaker(cfg.Breaker),
 		ec2Client:            sync.OnceValue(func() EC2API { return ec2.NewFromConfig(awsCfg) }),
+		tgwClient:            sync.OnceValue(func() TransitGatewayAPI { return ec2.NewFromConfig(awsCfg) }),
 		rdsClient:            sync.OnceValue(func() RDSAPI { return rds.NewFromConfig(awsCfg) }),
 		elbClient:            sync.OnceValue(func() ELBAPI { return elasticloadbalancingv2.NewFromConfig(awsCfg) }),
 		s3Client:             sync.OnceValue(func() S3API { return s3.NewFromConfig(awsCfg) }),
@@ -127,6 +282,7 @@ func New(ctx context.Context, cfg Config) (*Plugin, error) {
 		snsClient:            sync.OnceValue(func() SNSAPI { return sns.NewFromConfig(awsCfg) }),
 		cloudfrontClient:     sync.OnceValue(func() CloudFrontAPI { return cloudfront.NewFromConfig(awsCfg) }),
 		elasticacheClient:    sync.OnceValue(func() ElastiCacheAPI { return elasticache.NewFromConfig(awsCfg) }),
+		memorydbClient:       sync.OnceValue(func() MemoryDBAPI { return memorydb.NewFromConfig(awsCfg) }),
 		secretsmanagerClient: sync.OnceValue(func() SecretsManagerAPI { return secretsmanager.NewFromConfig(awsCfg) }),
 		acmClient:            sync.OnceValue(func() ACMAPI { return acm.NewFromConfig(awsCfg) }),
 		apigatewayClient:     sync.OnceValue(func() APIGatewayAPI { return apigatewayv2.NewFromConfig(awsCfg) }),
@@ -136,16 +292,66 @@ func New(ctx context.Context, cfg Config) (*Plugin, error) {
 		glueClient:           sync.OnceValue(func() GlueAPI { return glue.NewFromConfig(awsCfg) }),
 		opensearchClient:     sync.OnceValue(func() OpenSearchAPI { return opensearch.NewFromConfig(awsCfg) }),
 		mskClient:            sync.OnceValue(func() MSKAPI { return kafka.NewFromConfig(awsCfg) }),
+		emrClient:            sync.OnceValue(func() EMRAPI { return emr.NewFromConfig(awsCfg) }),
+		workspacesClient:     sync.OnceValue(func() WorkSpacesAPI { return workspaces.NewFromConfig(awsCfg) }),
+		ramClient:            sync.OnceValue(func() RAMAPI { return ram.NewFromConfig(awsCfg) }),
+		ecrClient:            sync.OnceValue(func() ECRAPI { return ecr.NewFromConfig(awsCfg) }),
+		cloudwatchClient:     sync.OnceValue(func() CloudWatchAPI { return cloudwatch.NewFromConfig(awsCfg) }),
+		costExplorerClient:   sync.OnceValue(func() CostExplorerAPI { return costexplorer.NewFromConfig(awsCfg) }),
 	}, nil
 }
 
-func getAccountID(ctx context.Context, awsCfg aws.Config) (string, error) {
-	stsClient := sts.NewFromConfig(awsCfg)
-	output, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+// roleSessionName identifies elava in CloudTrail for assumed-role sessions.
+const roleSessionName = "elava"
+
+// chainRoles assumes each role in order, using the credentials of the
+// previous hop, and returns a config carrying the final credentials. Each hop
+// is resolved immediately so a failure names the role that could not be assumed.
+func chainRoles(ctx context.Context, awsCfg aws.Config, roles []string, newClient func(aws.Config) stscreds.AssumeRoleAPIClient) (aws.Config, error) {
+	for i, role := range roles {
+		provider := stscreds.NewAssumeRoleProvider(newClient(awsCfg), role, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = roleSessionName
+		})
+		awsCfg.Credentials = aws.NewCredentialsCache(provider)
+		if _, err := awsCfg.Credentials.Retrieve(ctx); err != nil {
+			return aws.Config{}, fmt.Errorf("assume role %d/%d %s: %w", i+1, len(roles), role, err)
+		}
+	}
+	return awsCfg, nil
+}
+
+// getAccountID resolves the account via STS GetCallerIdentity, which works
+// with any credential source (instance profile, container, web identity,
+// assumed role) without calling EC2. If STS fails, e.g. its endpoint is
+// blocked, the owner of a security group is used instead.
+func getAccountID(ctx context.Context, client STSAPI, fallback EC2API) (string, error) {
+	output, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err == nil {
+		return aws.ToString(output.Account), nil
+	}
+	stsErr := fmt.Errorf("get caller identity: %w", err)
+
+	account, err := ec2AccountID(ctx, fallback)
+	if err != nil {
+		return "", errors.Join(stsErr, err)
+	}
+	log.Debug().Err(stsErr).Msg("resolved account id from ec2 security group owner")
+	return account, nil
+}
+
+// ec2AccountID returns the owner of the first security group; every VPC has
+// a default group, so one exists in any region with EC2 enabled.
+func ec2AccountID(ctx context.Context, client EC2API) (string, error) {
+	output, err := client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{MaxResults: aws.Int32(5)})
 	if err != nil {
-		return "", fmt.Errorf("get caller identity: %w", err)
+		return "", fmt.Errorf("describe security groups: %w", err)
+	}
+	for _, sg := range output.SecurityGroups {
+		if owner := aws.ToString(sg.OwnerId); owner != "" {
+			return owner, nil
+		}
 	}
-	return aws.ToString(output.Account), nil
+	return "", errors.New("describe security groups: no security group to take the owner from")
 }
 
 // Name returns the plugin identifier.
@@ -164,22 +370,31 @@ func (p *Plugin) scanners() []scanner {
 		// Regional scanners
 		{"ec2", p.scanEC2, false},
 		{"rds", p.scanRDS, false},
+		{"rds_snapshot", p.scanRDSSnapshots, false},
 		{"elb", p.scanELB, false},
+		{"target_group", p.scanTargetGroups, false},
 		{"eks", p.scanEKS, false},
 		{"asg", p.scanASG, false},
 		{"lambda", p.scanLambda, false},
 		{"vpc", p.scanVPC, false},
 		{"subnet", p.scanSubnets, false},
+		{"vpc_endpoint", p.scanVPCEndpoints, false},
+		{"internet_gateway", p.scanInternetGateways, false},
+		{"route_table", p.scanRouteTables, false},
+		{"transit_gateway", p.scanTransitGateways, false},
 		{"security_group", p.scanSecurityGroups, false},
 		{"dynamodb", p.scanDynamoDB, false},
 		{"sqs", p.scanSQS, false},
 		{"ebs", p.scanEBSVolumes, false},
+		{"ebs_snapshot", p.scanEBSSnapshots, false},
+		{"ami", p.scanAMIs, false},
 		{"eip", p.scanElasticIPs, false},
 		{"nat_gateway", p.scanNATGateways, false},
 		{"ecs", p.scanECS, false},
 		{"cloudwatch_logs", p.scanCloudWatchLogs, false},
 		{"sns", p.scanSNS, false},
 		{"elasticache", p.scanElastiCache, false},
+		{"memorydb", p.scanMemoryDB, false},
 		{"secretsmanager", p.scanSecretsManager, false},
 		{"acm", p.scanACM, false},
 		{"apigateway", p.scanAPIGateway, false},
@@ -189,6 +404,10 @@ func (p *Plugin) scanners() []scanner {
 		{"glue", p.scanGlue, false},
 		{"opensearch", p.scanOpenSearch, false},
 		{"msk", p.scanMSK, false},
+		{"emr", p.scanEMR, false},
+		{"workspace", p.scanWorkSpaces, false},
+		{"ram_share", p.scanRAMShares, false},
+		{"ecr", p.scanECR, false},
 
 		// Global scanners - run only once per account
 		{"s3", p.scanS3, true},
@@ -200,16 +419,47 @@ func (p *Plugin) scanners() []scanner {
 
 // Scan scans all AWS resources and returns them in unified format.
 func (p *Plugin) Scan(ctx context.Context) ([]resource.Resource, error) {
+	return p.runScanners(ctx, p.scanners(), nil)
+}
+
+// ScanStream calls fn with each scanner's resources as soon as that scanner
+// and the ones started before it have finished, without keeping them, and
+// returns the total count. Calls to fn are serialized.
+func (p *Plugin) ScanStream(ctx context.Context, fn func([]resource.Resource)) (int, error) {
+	n := 0
+	_, err := p.runScanners(ctx, p.scanners(), func(batch []resource.Resource) {
+		n += len(batch)
+		fn(batch)
+	})
+	return n, err
+}
+
+// runScanners runs the given scanners concurrently and merges their results,
+// sorted by type then ID so consecutive scans diff stably.
+// Scanner errors are logged and skipped unless failFast is set, in which case
+// the first error cancels the remaining scanners and is returned. A cancelled
+// ctx always returns its error rather than partial results. If onBatch is
+// non-nil it receives each scanner's resources, sorted the same way, in the
+// order the scanners were started instead of them being collected, and no
+// resources are returned; with failFast or cancellation some resources may
+// be streamed before the error.
+func (p *Plugin) runScanners(parent context.Context, scanners []scanner, onBatch func([]resource.Resource)) ([]resource.Resource, error) {
 	var (
 		mu        sync.Mutex
 		resources []resource.Resource
 		wg        sync.WaitGroup
 		scanErr   error
+		batches   = &batchQueue{onBatch: onBatch}
 	)
 
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
 	sem := semaphore.NewWeighted(p.maxConcurrency)
+	span := trace.SpanFromContext(ctx)
+	costs := p.resourceCosts(ctx)
 
-	for _, s := range p.scanners() {
+	for _, s := range scanners {
 		// Skip global scanners if not designated as the global scanner region
 		if s.global && !p.scanGlobalTypes {
 			log.Debug().Str("scanner", s.name).Msg("skipped global scanner (not first region)")
@@ -222,20 +472,59 @@ func (p *Plugin) Scan(ctx context.Context) ([]resource.Resource, error) {
 			continue
 		}
 
+		// Skip scanner while its circuit breaker is open
+		if !p.breaker.allow(s.name, p.clock()) {
+			log.Debug().Str("scanner", s.name).Msg("skipped by open circuit breaker")
+			continue
+		}
+
 		if err := sem.Acquire(ctx, 1); err != nil {
-			scanErr = fmt.Errorf("acquire semaphore: %w", err)
+			mu.Lock()
+			if scanErr == nil {
+				scanErr = fmt.Errorf("acquire semaphore: %w", err)
+			}
+			mu.Unlock()
 			break
 		}
 		wg.Add(1)
+		seq := batches.add()
 		go func(s scanner) {
 			defer sem.Release(1)
 			defer wg.Done()
+			var result []resource.Resource
+			if onBatch != nil {
+				defer func() {
+					mu.Lock()
+					batches.done(seq, result)
+					mu.Unlock()
+				}()
+			}
+			span.AddEvent("scan.scanner.started", trace.WithAttributes(attribute.String("scanner", s.name)))
+			start := time.Now()
 			result, err := s.fn(ctx)
+			if ctx.Err() == nil && p.breaker.record(s.name, err, p.clock()) {
+				log.Warn().Err(err).Str("scanner", s.name).Dur("cooldown", p.breaker.cfg.Cooldown).Msg("scanner keeps failing, circuit breaker opened")
+			}
 			if err != nil {
+				result = nil // a failed scanner streams nothing
+				scannerFinished(span, s.name, 0, time.Since(start), err)
+				if p.failFast {
+					mu.Lock()
+					if scanErr == nil {
+						scanErr = fmt.Errorf("scanner %s: %w", s.name, err)
+					}
+					mu.Unlock()
+					cancel()
+					return
+				}
 				log.Warn().Err(err).Str("scanner", s.name).Msg("scan failed")
 				return
 			}
 
+			if p.skipPartial {
+				result = dropPartial(result)
+			}
+
 			// Filter resources by tags
 			if p.filter != nil {
 				originalCount := len(result)
@@ -245,20 +534,252 @@ func (p *Plugin) Scan(ctx context.Context) ([]resource.Resource, error) {
 				}
 			}
 
-			mu.Lock()
-			resources = append(resources, result...)
-			mu.Unlock()
+			p.markTTL(result)
+			markTagsUnknown(result)
+			markUntagged(result, p.clock(), p.waste.untaggedAge())
+			markEnvironmentConflicts(result)
+			applyCostEstimates(result)
+			p.wasteRules.Apply(result)
+			applyCosts(result, costs)
+			markScheduleCandidates(result, p.clock())
+			scannerFinished(span, s.name, len(result), time.Since(start), nil)
+
+			if onBatch == nil {
+				mu.Lock()
+				resources = append(resources, result...)
+				mu.Unlock()
+			} else {
+				sortResources(result)
+			}
 			log.Debug().Str("scanner", s.name).Int("count", len(result)).Msg("scan complete")
 		}(s)
 	}
 
 	wg.Wait()
+	if err := parent.Err(); err != nil {
+		return nil, err
+	}
+	if p.failFast && scanErr != nil {
+		return nil, scanErr
+	}
+	sortResources(resources)
 	return resources, scanErr
 }
 
+// sortResources orders resources by type then ID.
+func sortResources(resources []resource.Resource) {
+	slices.SortFunc(resources, func(a, b resource.Resource) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.ID, b.ID))
+	})
+}
+
+// batchQueue hands scanner batches to onBatch in the order the scanners were
+// started, so streamed output does not depend on which API answers first.
+// A finished batch waits only for scanners started before it.
+type batchQueue struct {
+	onBatch func([]resource.Resource)
+	started int
+	next    int
+	ready   map[int][]resource.Resource
+}
+
+// add reserves the next position in the queue.
+func (q *batchQueue) add() int {
+	q.started++
+	return q.started - 1
+}
+
+// done records the batch at seq, which is empty for a failed scanner, and
+// passes on every batch that is no longer waiting for an earlier one.
+func (q *batchQueue) done(seq int, batch []resource.Resource) {
+	if q.ready == nil {
+		q.ready = make(map[int][]resource.Resource)
+	}
+	q.ready[seq] = batch
+	for {
+		b, ok := q.ready[q.next]
+		if !ok {
+			return
+		}
+		delete(q.ready, q.next)
+		q.next++
+		if len(b) > 0 {
+			q.onBatch(b)
+		}
+	}
+}
+
+// scannerFinished records a scan.scanner.finished event on the scan span.
+func scannerFinished(span trace.Span, name string, count int, d time.Duration, err error) {
+	attrs := []attribute.KeyValue{
+		attribute.String("scanner", name),
+		attribute.Int("resources", count),
+		attribute.Int64("duration_ms", d.Milliseconds()),
+	}
+	if err != nil {
+		attrs = append(attrs, attribute.String("error", err.Error()))
+	}
+	span.AddEvent("scan.scanner.finished", trace.WithAttributes(attrs...))
+}
+
+// markTTL flags resources that declare an elava:ttl tag. Resources past
+// CreatedAt+TTL get overdue=true; an unparseable TTL is recorded as a
+// tag_violation instead.
+func (p *Plugin) markTTL(resources []resource.Resource) {
+	now := p.clock()
+	for i := range resources {
+		r := &resources[i]
+		ttl, ok, err := r.TTL()
+		if !ok {
+			continue
+		}
+		if r.Attrs == nil {
+			r.Attrs = make(map[string]string)
+		}
+		if err != nil {
+			r.Attrs["tag_violation"] = "invalid_ttl"
+			log.Debug().Err(err).Str("id", r.ID).Msg("invalid ttl tag")
+			continue
+		}
+		r.Attrs["ttl"] = ttl.String()
+		if !r.CreatedAt.IsZero() {
+			r.Attrs["expires_at"] = r.CreatedAt.Add(ttl).UTC().Format(time.RFC3339)
+		}
+		r.Attrs["overdue"] = strconv.FormatBool(r.Overdue(now))
+	}
+}
+
+// defaultUntaggedAge is how long a new resource may go without an ownership
+// tag before it is flagged.
+const defaultUntaggedAge = 3 * 24 * time.Hour
+
+// tagsNotScanned are the resource types whose scanners do not read tags,
+// since that needs a separate API call per resource or batch.
+var tagsNotScanned = map[string]bool{
+	"acm": true, "cloudfront": true, "cloudwatch_logs": true, "dynamodb": true,
+	"ecr": true, "ecs": true, "elasticache": true, "elb": true, "emr": true,
+	"glue_database": true, "kinesis": true, "lambda": true, "memorydb": true, "opensearch": true,
+	"route53": true, "s3": true, "sns": true, "sqs": true, "stepfunctions": true,
+	"target_group": true, "workspace": true,
+}
+
+// markTagsUnknown sets tags_unknown=true on resources of tagsNotScanned
+// types, so they are not mistaken for untagged ones.
+func markTagsUnknown(resources []resource.Resource) {
+	for i := range resources {
+		r := &resources[i]
+		if !tagsNotScanned[r.Type] {
+			continue
+		}
+		if r.Attrs == nil {
+			r.Attrs = make(map[string]string)
+		}
+		r.Attrs[resource.AttrTagsUnknown] = "true"
+	}
+}
+
+// markUntagged sets untagged=true, with untagged_days counted from creation,
+// on resources that still lack an owner, team or project tag after grace.
+// Resources of unknown age or with unknown tags are left untouched.
+func markUntagged(resources []resource.Resource, now time.Time, grace time.Duration) {
+	for i := range resources {
+		r := &resources[i]
+		if r.CreatedAt.IsZero() || r.TagsUnknown() {
+			continue
+		}
+		if r.Attrs == nil {
+			r.Attrs = make(map[string]string)
+		}
+		untagged := r.Untagged(now, grace)
+		r.Attrs["untagged"] = strconv.FormatBool(untagged)
+		if untagged {
+			r.Attrs["untagged_days"] = strconv.Itoa(int(now.Sub(r.CreatedAt).Hours() / 24))
+		}
+	}
+}
+
+// markEnvironmentConflicts flags resources whose environment tag disagrees
+// with the environment in their name, e.g. Environment=prod on "test-foo".
+// Resources missing either signal are left untouched.
+func markEnvironmentConflicts(resources []resource.Resource) {
+	for i := range resources {
+		r := &resources[i]
+		_, nameEnv, conflict, ok := r.EnvironmentConflict()
+		if !ok {
+			continue
+		}
+		if r.Attrs == nil {
+			r.Attrs = make(map[string]string)
+		}
+		r.Attrs["env_conflict"] = strconv.FormatBool(conflict)
+		if conflict {
+			r.Attrs["name_env"] = nameEnv
+		}
+	}
+}
+
+const (
+	// scheduleRunningAge is how long a non-prod instance can run without a
+	// stop before it is suggested for start/stop scheduling.
+	scheduleRunningAge = 7 * 24 * time.Hour
+	// scheduleOnHours is the weekly uptime of an office-hours schedule
+	// (12 hours on weekdays); the rest of the week's 168 hours is saved.
+	scheduleOnHours = 5 * 12
+)
+
+// markScheduleCandidates flags non-prod EC2 instances that have been running
+// for longer than scheduleRunningAge. EC2 resets LaunchTime (CreatedAt) on
+// every start, so its age is how long the instance has run without a stop.
+// Candidates get the share of hours an office-hours schedule would save and,
+// when the billed or estimated monthly cost is known, the estimated monthly
+// savings in USD.
+func markScheduleCandidates(resources []resource.Resource, now time.Time) {
+	for i := range resources {
+		r := &resources[i]
+		if r.Type != "ec2" || r.Status != "running" || r.CreatedAt.IsZero() {
+			continue
+		}
+		env := r.TagEnvironment()
+		if env == "" {
+			env = r.NameEnvironment()
+		}
+		if env == "" || env == "prod" {
+			continue
+		}
+		if r.Attrs == nil {
+			r.Attrs = make(map[string]string)
+		}
+
+		running := now.Sub(r.CreatedAt)
+		candidate := running > scheduleRunningAge
+		r.Attrs["running_days"] = strconv.Itoa(int(running.Hours() / 24))
+		r.Attrs["schedule_candidate"] = strconv.FormatBool(candidate)
+		if !candidate {
+			continue
+		}
+
+		saved := float64(7*24-scheduleOnHours) / (7 * 24)
+		r.Attrs["schedule_savings_pct"] = strconv.Itoa(int(saved * 100))
+		if monthly, ok := cost.MonthlyCost(*r); ok {
+			r.Attrs["schedule_savings"] = strconv.FormatFloat(monthly*saved, 'f', 2, 64)
+		}
+	}
+}
+
+// clock returns the current time from the plugin clock, defaulting to time.Now.
+func (p *Plugin) clock() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
+
+// statusUnknown is the status of resources whose API response omitted it.
+const statusUnknown = "unknown"
+
 // helper to create resource with common fields
 func (p *Plugin) newResource(id, typ, status, name string) resource.Resource {
-	return resource.Resource{
+	return withStatusFallback(resource.Resource{
 		ID:        id,
 		Type:      typ,
 		Provider:  "aws",
@@ -268,13 +789,13 @@ func (p *Plugin) newResource(id, typ, status, name string) resource.Resource {
 		Status:    status,
 		Labels:    make(map[string]string),
 		Attrs:     make(map[string]string),
-		ScannedAt: time.Now(),
-	}
+		ScannedAt: p.clock(),
+	})
 }
 
 // helper to create global resource (IAM, Route53, CloudFront)
 func (p *Plugin) newGlobalResource(id, typ, status, name string) resource.Resource {
-	return resource.Resource{
+	return withStatusFallback(resource.Resource{
 		ID:        id,
 		Type:      typ,
 		Provider:  "aws",
@@ -284,6 +805,32 @@ func (p *Plugin) newGlobalResource(id, typ, status, name string) resource.Resour
 		Status:    status,
 		Labels:    make(map[string]string),
 		Attrs:     make(map[string]string),
-		ScannedAt: time.Now(),
+		ScannedAt: p.clock(),
+	})
+}
+
+// withStatusFallback sets statusUnknown on r when the API returned no status.
+func withStatusFallback(r resource.Resource) resource.Resource {
+	if r.Status == "" {
+		r.Status = statusUnknown
+		markPartial(&r)
+	}
+	return r
+}
+
+// markPartial flags r as built from an API response missing fields elava
+// expects, so fallback values can be told apart from real ones.
+func markPartial(r *resource.Resource) {
+	r.Attrs["partial_data"] = "true"
+}
+
+// dropPartial removes resources marked partial_data, reusing the slice.
+func dropPartial(resources []resource.Resource) []resource.Resource {
+	kept := resources[:0]
+	for _, r := range resources {
+		if r.Attrs["partial_data"] != "true" {
+			kept = append(kept, r)
+		}
 	}
+	return kept
 }
@@ -1,12 +1,27 @@
 package aws
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/endpointcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/yairfalse/elava/internal/filter"
+	"github.com/yairfalse/elava/pkg/resource"
 )
 
 func TestNewResource(t *testing.T) {
@@ -29,6 +44,14 @@ func TestNewResource(t *testing.T) {
 	assert.WithinDuration(t, time.Now(), r.ScannedAt, time.Second)
 }
 
+func TestNewResource_Clock(t *testing.T) {
+	fixed := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	p := &Plugin{region: "us-east-1", now: func() time.Time { return fixed }}
+
+	assert.Equal(t, fixed, p.newResource("i-1", "ec2", "running", "").ScannedAt)
+	assert.Equal(t, fixed, p.newGlobalResource("role", "iam_role", "active", "").ScannedAt)
+}
+
 func TestNewResource_EmptyName(t *testing.T) {
 	p := &Plugin{
 		region:    "eu-west-1",
@@ -55,9 +78,11 @@ func TestScanners(t *testing.T) {
 		"vpc", "subnet", "security_group", "dynamodb", "sqs",
 		"ebs", "eip", "nat_gateway", "iam_role", "ecs",
 		"route53", "cloudwatch_logs", "sns", "cloudfront",
-		"elasticache", "secretsmanager", "acm", "apigateway",
+		"elasticache", "memorydb", "secretsmanager", "acm", "apigateway",
 		"kinesis", "redshift", "stepfunctions", "glue",
-		"opensearch", "msk",
+		"opensearch", "msk", "workspace", "target_group", "ram_share", "ecr",
+		"ebs_snapshot", "rds_snapshot", "ami", "vpc_endpoint",
+		"internet_gateway", "route_table", "transit_gateway", "emr",
 	}
 
 	// Verify we have all expected scanners
@@ -96,3 +121,581 @@ func TestPlugin_FilterField(t *testing.T) {
 	assert.False(t, p.filter.ShouldScanType("iam_role"))
 	assert.True(t, p.filter.ShouldScanType("ec2"))
 }
+
+func failingScanners() []scanner {
+	return []scanner{
+		{"ec2", func(context.Context) ([]resource.Resource, error) {
+			return []resource.Resource{{ID: "i-123", Type: "ec2"}}, nil
+		}, false},
+		{"rds", func(context.Context) ([]resource.Resource, error) {
+			return nil, errors.New("access denied")
+		}, false},
+	}
+}
+
+func TestRunScanners_BestEffort(t *testing.T) {
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", maxConcurrency: 5}
+
+	resources, err := p.runScanners(context.Background(), failingScanners(), nil)
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "i-123", resources[0].ID)
+}
+
+func TestRunScanners_ConcurrentAndSorted(t *testing.T) {
+	// Both scanners must be running at once to get past the barrier.
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	waitForPeer := func() { barrier.Done(); barrier.Wait() }
+
+	scanners := []scanner{
+		{"rds", func(context.Context) ([]resource.Resource, error) {
+			waitForPeer()
+			return []resource.Resource{{ID: "db-2", Type: "rds"}, {ID: "db-1", Type: "rds"}}, nil
+		}, false},
+		{"ec2", func(context.Context) ([]resource.Resource, error) {
+			waitForPeer()
+			return []resource.Resource{{ID: "i-2", Type: "ec2"}, {ID: "i-1", Type: "ec2"}}, nil
+		}, false},
+		{"s3", func(context.Context) ([]resource.Resource, error) {
+			return nil, errors.New("access denied")
+		}, false},
+	}
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", maxConcurrency: 3}
+
+	resources, err := p.runScanners(context.Background(), scanners, nil)
+
+	require.NoError(t, err, "a failing scanner does not abort best-effort scans")
+	ids := make([]string, len(resources))
+	for i, r := range resources {
+		ids[i] = r.ID
+	}
+	assert.Equal(t, []string{"i-1", "i-2", "db-1", "db-2"}, ids)
+}
+
+func TestRunScanners_FailFast(t *testing.T) {
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", maxConcurrency: 5, failFast: true}
+
+	resources, err := p.runScanners(context.Background(), failingScanners(), nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rds")
+	assert.Contains(t, err.Error(), "access denied")
+	assert.Nil(t, resources)
+}
+
+func TestRunScanners_SkipPartial(t *testing.T) {
+	scanners := []scanner{
+		{"ec2", func(context.Context) ([]resource.Resource, error) {
+			return []resource.Resource{
+				{ID: "i-1", Type: "ec2", Status: "running"},
+				{ID: "i-2", Type: "ec2", Status: "unknown", Attrs: map[string]string{"partial_data": "true"}},
+			}, nil
+		}, false},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", maxConcurrency: 5}
+	resources, err := p.runScanners(context.Background(), scanners, nil)
+	require.NoError(t, err)
+	assert.Len(t, resources, 2)
+
+	p.skipPartial = true
+	resources, err = p.runScanners(context.Background(), scanners, nil)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "i-1", resources[0].ID)
+}
+
+func TestRunScanners_WasteRules(t *testing.T) {
+	rules, err := filter.NewRules([]filter.Rule{
+		{Name: "large-dev-gp2", Type: "ebs", Attrs: map[string]string{"type": "gp2", "size_gb": ">500"}, Waste: "oversized", Confidence: 0.8},
+	}, resource.NewTypeAliases(nil))
+	require.NoError(t, err)
+
+	scanners := []scanner{
+		{"ebs", func(context.Context) ([]resource.Resource, error) {
+			return []resource.Resource{
+				{ID: "vol-1", Type: "ebs", Attrs: map[string]string{"type": "gp2", "size_gb": "1000"}},
+				{ID: "vol-2", Type: "ebs", Attrs: map[string]string{"type": "gp3", "size_gb": "1000"}},
+			}, nil
+		}, false},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", maxConcurrency: 5, wasteRules: rules}
+	resources, err := p.runScanners(context.Background(), scanners, nil)
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "oversized", resources[0].Attrs["waste"])
+	assert.NotContains(t, resources[1].Attrs, "waste")
+}
+
+func TestRunScanners_SpanEvents(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	ctx, span := tracer.Start(context.Background(), "scan.aws")
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", maxConcurrency: 1}
+	_, err := p.runScanners(ctx, failingScanners(), nil)
+	require.NoError(t, err)
+	span.End()
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 1)
+
+	finished := make(map[string]map[string]string)
+	started := 0
+	for _, ev := range ended[0].Events() {
+		attrs := make(map[string]string)
+		for _, kv := range ev.Attributes {
+			attrs[string(kv.Key)] = kv.Value.Emit()
+		}
+		switch ev.Name {
+		case "scan.scanner.started":
+			started++
+		case "scan.scanner.finished":
+			finished[attrs["scanner"]] = attrs
+		}
+	}
+
+	assert.Equal(t, 2, started)
+	require.Len(t, finished, 2)
+	assert.Equal(t, "1", finished["ec2"]["resources"])
+	assert.NotContains(t, finished["ec2"], "error")
+	assert.Contains(t, finished["ec2"], "duration_ms")
+	assert.Equal(t, "access denied", finished["rds"]["error"])
+}
+
+func applyLoadOptions(t *testing.T, cfg Config) config.LoadOptions {
+	t.Helper()
+	var opts config.LoadOptions
+	for _, fn := range loadOptions(cfg) {
+		require.NoError(t, fn(&opts))
+	}
+	return opts
+}
+
+func TestLoadOptions_Profile(t *testing.T) {
+	opts := applyLoadOptions(t, Config{Region: "eu-west-1", Profile: "prod-readonly"})
+
+	assert.Equal(t, "eu-west-1", opts.Region)
+	assert.Equal(t, "prod-readonly", opts.SharedConfigProfile)
+}
+
+func TestLoadOptions_NoProfile(t *testing.T) {
+	opts := applyLoadOptions(t, Config{Region: "us-east-1"})
+
+	assert.Equal(t, "us-east-1", opts.Region)
+	assert.Empty(t, opts.SharedConfigProfile)
+}
+
+func TestLoadOptions_Retryer(t *testing.T) {
+	opts := applyLoadOptions(t, Config{Region: "us-east-1", Retry: RetryConfig{MaxRetries: 5}})
+
+	require.NotNil(t, opts.Retryer)
+	assert.Equal(t, 6, opts.Retryer().MaxAttempts())
+}
+
+func TestNewRetryer_NoRetries(t *testing.T) {
+	assert.Equal(t, 1, newRetryer(RetryConfig{}).MaxAttempts())
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := exponentialBackoff{base: 100 * time.Millisecond, max: time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration // upper bound; the delay is jittered down to half
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{100, time.Second},
+	}
+	for _, tt := range tests {
+		d, err := b.BackoffDelay(tt.attempt, nil)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, d, tt.want/2, "attempt %d", tt.attempt)
+		assert.LessOrEqual(t, d, tt.want, "attempt %d", tt.attempt)
+	}
+}
+
+// mockSTSClient answers AssumeRole with credentials named after the role and
+// records the access key of the caller for each role.
+type mockSTSClient struct {
+	caller string
+	calls  map[string]string // role ARN -> caller access key
+	fail   string            // role ARN to reject
+}
+
+func (m *mockSTSClient) AssumeRole(_ context.Context, in *sts.AssumeRoleInput, _ ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	role := aws.ToString(in.RoleArn)
+	m.calls[role] = m.caller
+	if role == m.fail {
+		return nil, errors.New("access denied")
+	}
+	return &sts.AssumeRoleOutput{Credentials: &ststypes.Credentials{
+		AccessKeyId:     aws.String("key-" + role),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("token"),
+		Expiration:      aws.Time(time.Now().Add(time.Hour)),
+	}}, nil
+}
+
+func chainTestClients(t *testing.T, calls map[string]string, fail string) func(aws.Config) stscreds.AssumeRoleAPIClient {
+	return func(c aws.Config) stscreds.AssumeRoleAPIClient {
+		creds, err := c.Credentials.Retrieve(context.Background())
+		require.NoError(t, err)
+		return &mockSTSClient{caller: creds.AccessKeyID, calls: calls, fail: fail}
+	}
+}
+
+func TestChainRoles(t *testing.T) {
+	const (
+		jump   = "arn:aws:iam::111111111111:role/jump"
+		target = "arn:aws:iam::222222222222:role/elava"
+	)
+	base := aws.Config{Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "base", SecretAccessKey: "secret"}, nil
+	})}
+	calls := map[string]string{}
+
+	cfg, err := chainRoles(context.Background(), base, []string{jump, target}, chainTestClients(t, calls, ""))
+	require.NoError(t, err)
+
+	assert.Equal(t, "base", calls[jump], "first hop uses the base credentials")
+	assert.Equal(t, "key-"+jump, calls[target], "second hop uses the jump role credentials")
+
+	creds, err := cfg.Credentials.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "key-"+target, creds.AccessKeyID)
+}
+
+func TestChainRoles_HopFailure(t *testing.T) {
+	const (
+		jump   = "arn:aws:iam::111111111111:role/jump"
+		target = "arn:aws:iam::222222222222:role/elava"
+	)
+	base := aws.Config{Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "base", SecretAccessKey: "secret"}, nil
+	})}
+
+	_, err := chainRoles(context.Background(), base, []string{jump, target}, chainTestClients(t, map[string]string{}, target))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "assume role 2/2")
+	assert.Contains(t, err.Error(), target)
+}
+
+func TestChainRoles_None(t *testing.T) {
+	base := aws.Config{Region: "us-east-1"}
+	cfg, err := chainRoles(context.Background(), base, nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, base, cfg)
+}
+
+type mockIdentityClient struct {
+	account string
+	err     error
+}
+
+func (m *mockIdentityClient) GetCallerIdentity(_ context.Context, _ *sts.GetCallerIdentityInput, _ ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &sts.GetCallerIdentityOutput{Account: aws.String(m.account)}, nil
+}
+
+// securityGroupOwner returns an EC2 client whose security groups belong to
+// owner, or that fails with err.
+func securityGroupOwner(owner string, err error) *mockEC2Client {
+	return &mockEC2Client{describeSecurityGroupsFunc: func(context.Context, *ec2.DescribeSecurityGroupsInput, ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+		if err != nil {
+			return nil, err
+		}
+		return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: []ec2types.SecurityGroup{{OwnerId: aws.String(owner)}}}, nil
+	}}
+}
+
+func TestGetAccountID(t *testing.T) {
+	id, err := getAccountID(context.Background(), &mockIdentityClient{account: "123456789012"}, securityGroupOwner("999999999999", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, "123456789012", id, "STS wins over EC2")
+}
+
+func TestGetAccountID_FallsBackToEC2(t *testing.T) {
+	id, err := getAccountID(context.Background(), &mockIdentityClient{err: errors.New("endpoint blocked")}, securityGroupOwner("123456789012", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, "123456789012", id)
+}
+
+func TestGetAccountID_Error(t *testing.T) {
+	_, err := getAccountID(context.Background(), &mockIdentityClient{err: errors.New("expired token")}, securityGroupOwner("", errors.New("unauthorized")))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "get caller identity")
+	assert.Contains(t, err.Error(), "describe security groups")
+}
+
+// isolateAWSEnv clears ambient AWS configuration so only the test's env
+// variables select the credential provider.
+func isolateAWSEnv(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, k := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_PROFILE",
+		"AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_ARN", "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "AWS_CONTAINER_CREDENTIALS_FULL_URI"} {
+		t.Setenv(k, "")
+	}
+	t.Setenv("AWS_CONFIG_FILE", dir+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", dir+"/credentials")
+}
+
+func TestLoadDefaultConfig_WebIdentity(t *testing.T) {
+	isolateAWSEnv(t)
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", t.TempDir()+"/token")
+	t.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/elava")
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), loadOptions(Config{Region: "us-east-1"})...)
+
+	require.NoError(t, err)
+	assert.True(t, aws.IsCredentialsProvider(cfg.Credentials, (*stscreds.WebIdentityRoleProvider)(nil)))
+}
+
+func TestLoadDefaultConfig_ContainerCredentials(t *testing.T) {
+	isolateAWSEnv(t)
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "/v2/credentials/abc")
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), loadOptions(Config{Region: "us-east-1"})...)
+
+	require.NoError(t, err)
+	assert.True(t, aws.IsCredentialsProvider(cfg.Credentials, (*endpointcreds.Provider)(nil)))
+}
+
+func TestMarkEnvironmentConflicts(t *testing.T) {
+	resources := []resource.Resource{
+		{ID: "i-1", Name: "test-foo", Labels: map[string]string{"Environment": "prod"}, Attrs: map[string]string{}},
+		{ID: "i-2", Name: "prod-api", Labels: map[string]string{"Environment": "prod"}, Attrs: map[string]string{}},
+		{ID: "i-3", Name: "api", Labels: map[string]string{"Environment": "prod"}, Attrs: map[string]string{}},
+	}
+
+	markEnvironmentConflicts(resources)
+
+	assert.Equal(t, "true", resources[0].Attrs["env_conflict"])
+	assert.Equal(t, "test", resources[0].Attrs["name_env"])
+	assert.Equal(t, "false", resources[1].Attrs["env_conflict"])
+	assert.NotContains(t, resources[1].Attrs, "name_env")
+	assert.NotContains(t, resources[2].Attrs, "env_conflict")
+}
+
+func TestMarkUntagged(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	resources := []resource.Resource{
+		{ID: "i-old", CreatedAt: now.AddDate(0, 0, -5)},
+		{ID: "i-new", CreatedAt: now.Add(-time.Hour)},
+		{ID: "i-owned", CreatedAt: now.AddDate(0, 0, -5), Labels: map[string]string{"Owner": "alice"}},
+		{ID: "i-unknown-age"},
+	}
+
+	markUntagged(resources, now, defaultUntaggedAge)
+
+	assert.Equal(t, "true", resources[0].Attrs["untagged"])
+	assert.Equal(t, "5", resources[0].Attrs["untagged_days"])
+	assert.Equal(t, "false", resources[1].Attrs["untagged"])
+	assert.NotContains(t, resources[1].Attrs, "untagged_days")
+	assert.Equal(t, "false", resources[2].Attrs["untagged"])
+	assert.Nil(t, resources[3].Attrs)
+}
+
+func TestMarkUntagged_TagsNotScanned(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(-1, 0, 0)
+	resources := []resource.Resource{
+		// Tagged in AWS, but the ELB and S3 scanners do not read tags
+		{ID: "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/1", Type: "elb", CreatedAt: old},
+		{ID: "logs-bucket", Type: "s3", CreatedAt: old},
+		{ID: "i-old", Type: "ec2", CreatedAt: old},
+	}
+
+	markTagsUnknown(resources)
+	markUntagged(resources, now, defaultUntaggedAge)
+
+	for _, r := range resources[:2] {
+		assert.Equal(t, "true", r.Attrs[resource.AttrTagsUnknown], r.Type)
+		assert.NotContains(t, r.Attrs, "untagged", r.Type)
+	}
+	assert.NotContains(t, resources[2].Attrs, resource.AttrTagsUnknown)
+	assert.Equal(t, "true", resources[2].Attrs["untagged"])
+}
+
+func TestMarkTTL(t *testing.T) {
+	created := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := created.Add(96 * time.Hour)
+	p := &Plugin{now: func() time.Time { return now }}
+
+	resources := []resource.Resource{
+		{ID: "i-overdue", CreatedAt: created, Labels: map[string]string{resource.TTLLabel: "72h"}, Attrs: map[string]string{}},
+		{ID: "i-fresh", CreatedAt: created, Labels: map[string]string{resource.TTLLabel: "168h"}, Attrs: map[string]string{}},
+		{ID: "i-bad", CreatedAt: created, Labels: map[string]string{resource.TTLLabel: "3 days"}},
+		{ID: "i-none", CreatedAt: created, Labels: map[string]string{}, Attrs: map[string]string{}},
+	}
+
+	p.markTTL(resources)
+
+	assert.Equal(t, "true", resources[0].Attrs["overdue"])
+	assert.Equal(t, "72h0m0s", resources[0].Attrs["ttl"])
+	assert.Equal(t, "2024-03-04T00:00:00Z", resources[0].Attrs["expires_at"])
+	assert.Equal(t, "false", resources[1].Attrs["overdue"])
+	assert.Equal(t, "invalid_ttl", resources[2].Attrs["tag_violation"])
+	assert.NotContains(t, resources[2].Attrs, "overdue")
+	assert.Empty(t, resources[3].Attrs)
+}
+
+func TestMarkScheduleCandidates(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	dev := map[string]string{"env": "dev"}
+	resources := []resource.Resource{
+		// running without a stop for 30 days
+		{ID: "i-always-on", Type: "ec2", Status: "running", Labels: dev, CreatedAt: now.Add(-30 * 24 * time.Hour),
+			Attrs: map[string]string{"monthly_cost": "100"}},
+		// stopped overnight, so LaunchTime is recent
+		{ID: "i-scheduled", Type: "ec2", Status: "running", Labels: dev, CreatedAt: now.Add(-10 * time.Hour)},
+		{ID: "i-prod", Type: "ec2", Status: "running", Labels: map[string]string{"env": "prod"}, CreatedAt: now.Add(-30 * 24 * time.Hour)},
+		{ID: "i-untagged", Type: "ec2", Status: "running", CreatedAt: now.Add(-30 * 24 * time.Hour)},
+		{ID: "i-test-by-name", Name: "test-runner", Type: "ec2", Status: "running", CreatedAt: now.Add(-30 * 24 * time.Hour)},
+		{ID: "i-stopped", Type: "ec2", Status: "stopped", Labels: dev, CreatedAt: now.Add(-30 * 24 * time.Hour)},
+		// no billed cost, only the list-price estimate
+		{ID: "i-estimated", Type: "ec2", Status: "running", Labels: dev, CreatedAt: now.Add(-30 * 24 * time.Hour),
+			Attrs: map[string]string{"monthly_cost_estimate": "60.74"}},
+	}
+
+	markScheduleCandidates(resources, now)
+
+	alwaysOn := resources[0].Attrs
+	assert.Equal(t, "true", alwaysOn["schedule_candidate"])
+	assert.Equal(t, "30", alwaysOn["running_days"])
+	assert.Equal(t, "64", alwaysOn["schedule_savings_pct"])
+	assert.Equal(t, "64.29", alwaysOn["schedule_savings"])
+
+	assert.Equal(t, "false", resources[1].Attrs["schedule_candidate"])
+	assert.NotContains(t, resources[1].Attrs, "schedule_savings_pct")
+	assert.Nil(t, resources[2].Attrs, "prod is never scheduled")
+	assert.Nil(t, resources[3].Attrs, "unknown environment")
+	assert.Equal(t, "true", resources[4].Attrs["schedule_candidate"])
+	assert.NotContains(t, resources[4].Attrs, "schedule_savings", "no cost data")
+	assert.Nil(t, resources[5].Attrs)
+	assert.Equal(t, "39.05", resources[6].Attrs["schedule_savings"], "falls back to the estimate")
+}
+
+func TestRunScanners_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	scanners := []scanner{
+		{"ec2", func(ctx context.Context) ([]resource.Resource, error) {
+			cancel()
+			return []resource.Resource{{ID: "i-123", Type: "ec2"}}, nil
+		}, false},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", maxConcurrency: 5}
+	resources, err := p.runScanners(ctx, scanners, nil)
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, resources)
+}
+
+func TestRunScanners_StreamsBatches(t *testing.T) {
+	release := make(chan struct{})
+	scanners := []scanner{
+		{"ec2", func(context.Context) ([]resource.Resource, error) {
+			return []resource.Resource{{ID: "i-123", Type: "ec2"}}, nil
+		}, false},
+		{"rds", func(context.Context) ([]resource.Resource, error) {
+			<-release
+			return []resource.Resource{{ID: "db-1", Type: "rds"}}, nil
+		}, false},
+	}
+
+	var (
+		mu       sync.Mutex
+		streamed []string
+	)
+	onBatch := func(batch []resource.Resource) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, r := range batch {
+			streamed = append(streamed, r.ID)
+		}
+		if batch[0].ID == "i-123" {
+			close(release) // the slow scanner only finishes after ec2 was streamed
+		}
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", maxConcurrency: 5}
+	resources, err := p.runScanners(context.Background(), scanners, onBatch)
+
+	require.NoError(t, err)
+	assert.Nil(t, resources, "streamed resources are not collected")
+	assert.Equal(t, []string{"i-123", "db-1"}, streamed)
+}
+
+func TestRunScanners_IncludeExcludeTypes(t *testing.T) {
+	var mu sync.Mutex
+	ran := make(map[string]bool)
+	track := func(name string) func(context.Context) ([]resource.Resource, error) {
+		return func(context.Context) ([]resource.Resource, error) {
+			mu.Lock()
+			ran[name] = true
+			mu.Unlock()
+			return nil, nil
+		}
+	}
+	scanners := []scanner{
+		{"ec2", track("ec2"), false},
+		{"rds", track("rds"), false},
+		{"s3", track("s3"), true},
+		{"iam_role", track("iam_role"), true},
+	}
+
+	f := filter.New([]string{"iam_role"}, nil, nil).WithIncludeTypes([]string{"ec2", "s3", "iam_role"})
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", maxConcurrency: 5, scanGlobalTypes: true, filter: f}
+
+	_, err := p.runScanners(context.Background(), scanners, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{"ec2": true, "s3": true}, ran)
+}
+
+func TestRunScanners_StreamsInScannerOrder(t *testing.T) {
+	release := make(chan struct{})
+	scanners := []scanner{
+		{"ec2", func(context.Context) ([]resource.Resource, error) {
+			<-release
+			return []resource.Resource{{ID: "i-2", Type: "ec2"}, {ID: "i-1", Type: "ec2"}}, nil
+		}, false},
+		{"ebs", func(context.Context) ([]resource.Resource, error) {
+			defer close(release) // ec2 only finishes after ebs
+			return []resource.Resource{{ID: "vol-1", Type: "ebs"}}, nil
+		}, false},
+		{"rds", func(context.Context) ([]resource.Resource, error) {
+			return nil, errors.New("AccessDenied")
+		}, false},
+		{"s3", func(context.Context) ([]resource.Resource, error) {
+			return []resource.Resource{{ID: "bucket", Type: "s3"}}, nil
+		}, false},
+	}
+
+	var streamed []string
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", maxConcurrency: 5}
+	_, err := p.runScanners(context.Background(), scanners, func(batch []resource.Resource) {
+		for _, r := range batch {
+			streamed = append(streamed, r.ID)
+		}
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"i-1", "i-2", "vol-1", "bucket"}, streamed)
+}
@@ -2,9 +2,15 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
@@ -21,6 +27,8 @@ import (
 	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
@@ -29,6 +37,8 @@ import (
 	ectypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/emr"
+	emrtypes "github.com/aws/aws-sdk-go-v2/service/emr/types"
 	"github.com/aws/aws-sdk-go-v2/service/glue"
 	gluetypes "github.com/aws/aws-sdk-go-v2/service/glue/types"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
@@ -39,8 +49,12 @@ import (
 	kinesistypes "github.com/aws/aws-sdk-go-v2/service/kinesis/types"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/memorydb"
+	mdbtypes "github.com/aws/aws-sdk-go-v2/service/memorydb/types"
 	"github.com/aws/aws-sdk-go-v2/service/opensearch"
 	ostypes "github.com/aws/aws-sdk-go-v2/service/opensearch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ram"
+	ramtypes "github.com/aws/aws-sdk-go-v2/service/ram/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/aws/aws-sdk-go-v2/service/redshift"
@@ -48,6 +62,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	r53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	smtypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 	"github.com/aws/aws-sdk-go-v2/service/sfn"
@@ -55,6 +70,10 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/aws-sdk-go-v2/service/workspaces"
+	wstypes "github.com/aws/aws-sdk-go-v2/service/workspaces/types"
+	"github.com/aws/smithy-go"
 	"github.com/rs/zerolog/log"
 
 	"github.com/yairfalse/elava/pkg/resource"
@@ -87,13 +106,20 @@ func (p *Plugin) scanEC2(ctx context.Context) ([]resource.Resource, error) {
 }
 
 func (p *Plugin) convertEC2Instance(instance ec2types.Instance) resource.Resource {
-	r := p.newResource(aws.ToString(instance.InstanceId), "ec2", string(instance.State.Name), extractNameTag(instance.Tags))
+	var state string
+	if instance.State != nil {
+		state = string(instance.State.Name)
+	}
+	r := p.newResource(aws.ToString(instance.InstanceId), "ec2", state, extractNameTag(instance.Tags))
+	r.CreatedAt = aws.ToTime(instance.LaunchTime)
 	for _, tag := range instance.Tags {
 		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
 	}
 	r.Attrs["instance_type"] = string(instance.InstanceType)
 	if instance.Placement != nil {
 		r.Attrs["az"] = aws.ToString(instance.Placement.AvailabilityZone)
+	} else {
+		markPartial(&r)
 	}
 	r.Attrs["vpc_id"] = aws.ToString(instance.VpcId)
 	r.Attrs["subnet_id"] = aws.ToString(instance.SubnetId)
@@ -116,7 +142,40 @@ func (p *Plugin) scanRDS(ctx context.Context) ([]resource.Resource, error) {
 		}
 
 		for _, instance := range output.DBInstances {
-			resources = append(resources, p.convertRDSInstance(instance))
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			r := p.convertRDSInstance(instance)
+			if p.idle.Enabled {
+				p.enrichRDSConnections(ctx, &r)
+			}
+			resources = append(resources, r)
+		}
+
+		if output.Marker == nil {
+			break
+		}
+		marker = output.Marker
+	}
+
+	return resources, nil
+}
+
+// scanRDSSnapshots scans RDS DB snapshots of every type. Automated and AWS
+// Backup snapshots expire on their own; manual ones are checked by
+// markUnmanagedSnapshots.
+func (p *Plugin) scanRDSSnapshots(ctx context.Context) ([]resource.Resource, error) {
+	var resources []resource.Resource
+	var marker *string
+
+	for {
+		output, err := p.rdsClient().DescribeDBSnapshots(ctx, &rds.DescribeDBSnapshotsInput{Marker: marker})
+		if err != nil {
+			return nil, fmt.Errorf("describe db snapshots: %w", err)
+		}
+
+		for _, snap := range output.DBSnapshots {
+			resources = append(resources, p.convertRDSSnapshot(snap))
 		}
 
 		if output.Marker == nil {
@@ -125,11 +184,29 @@ func (p *Plugin) scanRDS(ctx context.Context) ([]resource.Resource, error) {
 		marker = output.Marker
 	}
 
+	markUnmanagedSnapshots(resources, p.clock(), p.waste.snapshotUnmanagedAge())
 	return resources, nil
 }
 
+func (p *Plugin) convertRDSSnapshot(snap rdstypes.DBSnapshot) resource.Resource {
+	id := aws.ToString(snap.DBSnapshotIdentifier)
+	r := p.newResource(id, "rds_snapshot", aws.ToString(snap.Status), id)
+	r.CreatedAt = aws.ToTime(snap.SnapshotCreateTime)
+	for _, tag := range snap.TagList {
+		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	snapshotType := aws.ToString(snap.SnapshotType)
+	r.Attrs["source"] = aws.ToString(snap.DBInstanceIdentifier)
+	r.Attrs["engine"] = aws.ToString(snap.Engine)
+	r.Attrs["size_gb"] = strconv.Itoa(int(aws.ToInt32(snap.AllocatedStorage)))
+	r.Attrs["snapshot_type"] = snapshotType
+	r.Attrs["automated"] = strconv.FormatBool(snapshotType != "manual")
+	return r
+}
+
 func (p *Plugin) convertRDSInstance(instance rdstypes.DBInstance) resource.Resource {
 	r := p.newResource(aws.ToString(instance.DBInstanceIdentifier), "rds", aws.ToString(instance.DBInstanceStatus), aws.ToString(instance.DBInstanceIdentifier))
+	r.CreatedAt = aws.ToTime(instance.InstanceCreateTime)
 	for _, tag := range instance.TagList {
 		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
 	}
@@ -157,7 +234,14 @@ func (p *Plugin) scanELB(ctx context.Context) ([]resource.Resource, error) {
 		}
 
 		for _, lb := range output.LoadBalancers {
-			resources = append(resources, p.convertELB(lb))
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			r := p.convertELB(lb)
+			if p.idle.Enabled {
+				p.enrichELBTraffic(ctx, &r, lb)
+			}
+			resources = append(resources, r)
 		}
 
 		if output.NextMarker == nil {
@@ -170,11 +254,12 @@ func (p *Plugin) scanELB(ctx context.Context) ([]resource.Resource, error) {
 }
 
 func (p *Plugin) convertELB(lb elbtypes.LoadBalancer) resource.Resource {
-	status := "unknown"
+	var status string
 	if lb.State != nil {
 		status = string(lb.State.Code)
 	}
 	r := p.newResource(aws.ToString(lb.LoadBalancerArn), "elb", status, aws.ToString(lb.LoadBalancerName))
+	r.CreatedAt = aws.ToTime(lb.CreatedTime)
 	r.Attrs["type"] = string(lb.Type)
 	r.Attrs["scheme"] = string(lb.Scheme)
 	r.Attrs["vpc_id"] = aws.ToString(lb.VpcId)
@@ -182,6 +267,47 @@ func (p *Plugin) convertELB(lb elbtypes.LoadBalancer) resource.Resource {
 	return r
 }
 
+// scanTargetGroups scans ELBv2 target groups. A target group with no load
+// balancer attached receives no traffic and is marked orphaned.
+func (p *Plugin) scanTargetGroups(ctx context.Context) ([]resource.Resource, error) {
+	var resources []resource.Resource
+	var marker *string
+
+	for {
+		output, err := p.elbClient().DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{Marker: marker})
+		if err != nil {
+			return nil, fmt.Errorf("describe target groups: %w", err)
+		}
+
+		for _, tg := range output.TargetGroups {
+			resources = append(resources, p.convertTargetGroup(tg))
+		}
+
+		if output.NextMarker == nil {
+			break
+		}
+		marker = output.NextMarker
+	}
+
+	return resources, nil
+}
+
+func (p *Plugin) convertTargetGroup(tg elbtypes.TargetGroup) resource.Resource {
+	orphaned := len(tg.LoadBalancerArns) == 0
+	status := "attached"
+	if orphaned {
+		status = "orphaned"
+	}
+	r := p.newResource(aws.ToString(tg.TargetGroupArn), "target_group", status, aws.ToString(tg.TargetGroupName))
+	r.Attrs["protocol"] = string(tg.Protocol)
+	r.Attrs["port"] = strconv.Itoa(int(aws.ToInt32(tg.Port)))
+	r.Attrs["target_type"] = string(tg.TargetType)
+	r.Attrs["vpc_id"] = aws.ToString(tg.VpcId)
+	r.Attrs["load_balancer_count"] = strconv.Itoa(len(tg.LoadBalancerArns))
+	r.Attrs["orphaned"] = strconv.FormatBool(orphaned)
+	return r
+}
+
 // scanS3 scans S3 buckets (no pagination needed).
 // S3 is a global service, but buckets exist in specific regions.
 func (p *Plugin) scanS3(ctx context.Context) ([]resource.Resource, error) {
@@ -192,16 +318,23 @@ func (p *Plugin) scanS3(ctx context.Context) ([]resource.Resource, error) {
 
 	var resources []resource.Resource
 	for _, bucket := range output.Buckets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		bucketName := aws.ToString(bucket.Name)
 
 		// Get actual bucket region
 		region := p.getBucketRegion(ctx, bucketName)
 
 		r := p.newResource(bucketName, "s3", "active", bucketName)
+		r.CreatedAt = aws.ToTime(bucket.CreationDate)
 		r.Region = region // Override with actual bucket region
 		if bucket.CreationDate != nil {
 			r.Attrs["created"] = bucket.CreationDate.Format("2006-01-02")
 		}
+		if p.s3Enrich && region != "unknown" {
+			p.enrichBucket(ctx, &r)
+		}
 		resources = append(resources, r)
 	}
 
@@ -226,6 +359,84 @@ func (p *Plugin) getBucketRegion(ctx context.Context, bucketName string) string
 	return string(locOutput.LocationConstraint)
 }
 
+// enrichBucket adds versioning, default encryption, public access block and
+// lifecycle attributes. Buckets are enriched one at a time to keep the extra
+// per-bucket calls from throttling. Calls go to the bucket's own region, so
+// r.Region must be known. Missing configuration is recorded as
+// "none"/"false"/"0"; other errors leave the attribute unset.
+func (p *Plugin) enrichBucket(ctx context.Context, r *resource.Resource) {
+	bucket := aws.String(r.ID)
+	client := p.s3Client()
+	inRegion := func(o *s3.Options) { o.Region = r.Region }
+
+	if out, err := client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: bucket}, inRegion); err != nil {
+		log.Warn().Err(err).Str("bucket", r.ID).Msg("failed to get bucket versioning")
+	} else {
+		r.Attrs["versioning"] = strings.ToLower(string(out.Status))
+		if out.Status == "" {
+			r.Attrs["versioning"] = "disabled"
+		}
+	}
+
+	out, err := client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{Bucket: bucket}, inRegion)
+	switch {
+	case isAPIError(err, "ServerSideEncryptionConfigurationNotFoundError"):
+		r.Attrs["encryption"] = "none"
+	case err != nil:
+		log.Warn().Err(err).Str("bucket", r.ID).Msg("failed to get bucket encryption")
+	default:
+		r.Attrs["encryption"] = bucketEncryption(out.ServerSideEncryptionConfiguration)
+	}
+
+	pab, err := client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{Bucket: bucket}, inRegion)
+	switch {
+	case isAPIError(err, "NoSuchPublicAccessBlockConfiguration"):
+		r.Attrs["public_access_blocked"] = "false"
+	case err != nil:
+		log.Warn().Err(err).Str("bucket", r.ID).Msg("failed to get public access block")
+	default:
+		r.Attrs["public_access_blocked"] = strconv.FormatBool(publicAccessBlocked(pab.PublicAccessBlockConfiguration))
+	}
+
+	lc, err := client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: bucket}, inRegion)
+	switch {
+	case isAPIError(err, "NoSuchLifecycleConfiguration"):
+		r.Attrs["lifecycle_rules"] = "0"
+	case err != nil:
+		log.Warn().Err(err).Str("bucket", r.ID).Msg("failed to get bucket lifecycle")
+	default:
+		r.Attrs["lifecycle_rules"] = strconv.Itoa(len(lc.Rules))
+	}
+}
+
+// bucketEncryption returns the default SSE algorithm, or "none" if unset.
+func bucketEncryption(cfg *s3types.ServerSideEncryptionConfiguration) string {
+	if cfg == nil {
+		return "none"
+	}
+	for _, rule := range cfg.Rules {
+		if rule.ApplyServerSideEncryptionByDefault != nil {
+			return string(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm)
+		}
+	}
+	return "none"
+}
+
+// publicAccessBlocked reports whether all four public access block settings are on.
+func publicAccessBlocked(cfg *s3types.PublicAccessBlockConfiguration) bool {
+	return cfg != nil &&
+		aws.ToBool(cfg.BlockPublicAcls) &&
+		aws.ToBool(cfg.IgnorePublicAcls) &&
+		aws.ToBool(cfg.BlockPublicPolicy) &&
+		aws.ToBool(cfg.RestrictPublicBuckets)
+}
+
+// isAPIError reports whether err is an AWS API error with the given code.
+func isAPIError(err error, code string) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
+
 // scanEKS scans EKS clusters.
 func (p *Plugin) scanEKS(ctx context.Context) ([]resource.Resource, error) {
 	var resources []resource.Resource
@@ -238,8 +449,11 @@ func (p *Plugin) scanEKS(ctx context.Context) ([]resource.Resource, error) {
 		}
 
 		for _, clusterName := range listOutput.Clusters {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
 			descOutput, err := p.eksClient().DescribeCluster(ctx, &eks.DescribeClusterInput{Name: aws.String(clusterName)})
-			if err != nil {
+			if err != nil || descOutput.Cluster == nil {
 				continue
 			}
 			resources = append(resources, p.convertEKSCluster(descOutput.Cluster))
@@ -256,6 +470,7 @@ func (p *Plugin) scanEKS(ctx context.Context) ([]resource.Resource, error) {
 
 func (p *Plugin) convertEKSCluster(cluster *ekstypes.Cluster) resource.Resource {
 	r := p.newResource(aws.ToString(cluster.Arn), "eks", string(cluster.Status), aws.ToString(cluster.Name))
+	r.CreatedAt = aws.ToTime(cluster.CreatedAt)
 	for k, v := range cluster.Tags {
 		r.Labels[k] = v
 	}
@@ -294,6 +509,7 @@ func (p *Plugin) convertASG(asg asgtypes.AutoScalingGroup) resource.Resource {
 		status = "stopped"
 	}
 	r := p.newResource(aws.ToString(asg.AutoScalingGroupARN), "asg", status, aws.ToString(asg.AutoScalingGroupName))
+	r.CreatedAt = aws.ToTime(asg.CreatedTime)
 	for _, tag := range asg.Tags {
 		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
 	}
@@ -328,11 +544,31 @@ func (p *Plugin) scanLambda(ctx context.Context) ([]resource.Resource, error) {
 	return resources, nil
 }
 
+// defaultLambdaStaleAge is how long a function can go without a code or
+// configuration change before it is flagged stale.
+const defaultLambdaStaleAge = 30 * 24 * time.Hour
+
+// lambdaTimeLayout is the format of FunctionConfiguration.LastModified.
+const lambdaTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// convertLambda builds a lambda resource. ListFunctions does not return
+// State (only GetFunction does), so a function without one is Active
+// rather than partial.
 func (p *Plugin) convertLambda(fn lambdatypes.FunctionConfiguration) resource.Resource {
-	r := p.newResource(aws.ToString(fn.FunctionArn), "lambda", string(fn.State), aws.ToString(fn.FunctionName))
+	state := fn.State
+	if state == "" {
+		state = lambdatypes.StateActive
+	}
+	r := p.newResource(aws.ToString(fn.FunctionArn), "lambda", string(state), aws.ToString(fn.FunctionName))
 	r.Attrs["runtime"] = string(fn.Runtime)
 	r.Attrs["memory_mb"] = strconv.Itoa(int(aws.ToInt32(fn.MemorySize)))
 	r.Attrs["timeout_sec"] = strconv.Itoa(int(aws.ToInt32(fn.Timeout)))
+	if modified, err := time.Parse(lambdaTimeLayout, aws.ToString(fn.LastModified)); err == nil {
+		since := p.clock().Sub(modified)
+		r.Attrs["last_modified"] = modified.Format("2006-01-02")
+		r.Attrs["days_since_modified"] = strconv.Itoa(int(since.Hours() / 24))
+		r.Attrs["stale"] = strconv.FormatBool(since > p.waste.lambdaStaleAge())
+	}
 	return r
 }
 
@@ -406,6 +642,212 @@ func (p *Plugin) convertSubnet(subnet ec2types.Subnet) resource.Resource {
 	return r
 }
 
+// scanVPCEndpoints scans interface, gateway and load balancer VPC endpoints.
+// Interface endpoints are billed hourly per AZ whether or not they are used.
+func (p *Plugin) scanVPCEndpoints(ctx context.Context) ([]resource.Resource, error) {
+	var resources []resource.Resource
+	var nextToken *string
+
+	for {
+		output, err := p.ec2Client().DescribeVpcEndpoints(ctx, &ec2.DescribeVpcEndpointsInput{NextToken: nextToken})
+		if err != nil {
+			return nil, fmt.Errorf("describe vpc endpoints: %w", err)
+		}
+
+		for _, ep := range output.VpcEndpoints {
+			resources = append(resources, p.convertVPCEndpoint(ep))
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	return resources, nil
+}
+
+func (p *Plugin) convertVPCEndpoint(ep ec2types.VpcEndpoint) resource.Resource {
+	r := p.newResource(aws.ToString(ep.VpcEndpointId), "vpc_endpoint", strings.ToLower(string(ep.State)), extractNameTag(ep.Tags))
+	r.CreatedAt = aws.ToTime(ep.CreationTimestamp)
+	for _, tag := range ep.Tags {
+		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	r.Attrs["service_name"] = aws.ToString(ep.ServiceName)
+	r.Attrs["vpc_id"] = aws.ToString(ep.VpcId)
+	r.Attrs["endpoint_type"] = string(ep.VpcEndpointType)
+	return r
+}
+
+// scanInternetGateways scans Internet Gateways. A gateway left behind by a
+// VPC teardown has no attachment.
+func (p *Plugin) scanInternetGateways(ctx context.Context) ([]resource.Resource, error) {
+	var resources []resource.Resource
+	var nextToken *string
+
+	for {
+		output, err := p.ec2Client().DescribeInternetGateways(ctx, &ec2.DescribeInternetGatewaysInput{NextToken: nextToken})
+		if err != nil {
+			return nil, fmt.Errorf("describe internet gateways: %w", err)
+		}
+
+		for _, igw := range output.InternetGateways {
+			resources = append(resources, p.convertInternetGateway(igw))
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	return resources, nil
+}
+
+func (p *Plugin) convertInternetGateway(igw ec2types.InternetGateway) resource.Resource {
+	attached := len(igw.Attachments) > 0
+	status := "detached"
+	if attached {
+		status = "attached"
+	}
+	r := p.newResource(aws.ToString(igw.InternetGatewayId), "internet_gateway", status, extractNameTag(igw.Tags))
+	for _, tag := range igw.Tags {
+		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	r.Attrs["attached"] = strconv.FormatBool(attached)
+	if attached {
+		r.Attrs["vpc_id"] = aws.ToString(igw.Attachments[0].VpcId)
+	}
+	return r
+}
+
+// scanRouteTables scans VPC route tables.
+func (p *Plugin) scanRouteTables(ctx context.Context) ([]resource.Resource, error) {
+	var resources []resource.Resource
+	var nextToken *string
+
+	for {
+		output, err := p.ec2Client().DescribeRouteTables(ctx, &ec2.DescribeRouteTablesInput{NextToken: nextToken})
+		if err != nil {
+			return nil, fmt.Errorf("describe route tables: %w", err)
+		}
+
+		for _, rt := range output.RouteTables {
+			resources = append(resources, p.convertRouteTable(rt))
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	return resources, nil
+}
+
+// convertRouteTable records whether the table is its VPC's main table and
+// how many subnets use it explicitly. A non-main table with no associations
+// routes nothing.
+func (p *Plugin) convertRouteTable(rt ec2types.RouteTable) resource.Resource {
+	main := false
+	subnets := 0
+	for _, assoc := range rt.Associations {
+		if aws.ToBool(assoc.Main) {
+			main = true
+		}
+		if assoc.SubnetId != nil {
+			subnets++
+		}
+	}
+	r := p.newResource(aws.ToString(rt.RouteTableId), "route_table", "active", extractNameTag(rt.Tags))
+	for _, tag := range rt.Tags {
+		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	r.Attrs["vpc_id"] = aws.ToString(rt.VpcId)
+	r.Attrs["route_count"] = strconv.Itoa(len(rt.Routes))
+	r.Attrs["main"] = strconv.FormatBool(main)
+	r.Attrs["subnet_count"] = strconv.Itoa(subnets)
+	return r
+}
+
+// scanTransitGateways scans Transit Gateways with their attachment count.
+// Gateways are billed hourly, so one with no attachments is pure waste.
+func (p *Plugin) scanTransitGateways(ctx context.Context) ([]resource.Resource, error) {
+	var resources []resource.Resource
+	var nextToken *string
+
+	for {
+		output, err := p.tgwClient().DescribeTransitGateways(ctx, &ec2.DescribeTransitGatewaysInput{NextToken: nextToken})
+		if err != nil {
+			return nil, fmt.Errorf("describe transit gateways: %w", err)
+		}
+
+		for _, tgw := range output.TransitGateways {
+			resources = append(resources, p.convertTransitGateway(tgw))
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	if len(resources) == 0 {
+		return resources, nil
+	}
+	counts, err := p.getTransitGatewayAttachmentCounts(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to count transit gateway attachments")
+		return resources, nil
+	}
+	for i := range resources {
+		resources[i].Attrs["attachment_count"] = strconv.Itoa(counts[resources[i].ID])
+	}
+	return resources, nil
+}
+
+func (p *Plugin) convertTransitGateway(tgw ec2types.TransitGateway) resource.Resource {
+	r := p.newResource(aws.ToString(tgw.TransitGatewayId), "transit_gateway", string(tgw.State), extractNameTag(tgw.Tags))
+	r.CreatedAt = aws.ToTime(tgw.CreationTime)
+	for _, tag := range tgw.Tags {
+		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	if tgw.Options != nil && tgw.Options.AmazonSideAsn != nil {
+		r.Attrs["amazon_side_asn"] = strconv.FormatInt(*tgw.Options.AmazonSideAsn, 10)
+	}
+	return r
+}
+
+// getTransitGatewayAttachmentCounts counts live attachments per transit
+// gateway ID in one paginated call. Deleted attachments stay listed for a
+// while and are skipped.
+func (p *Plugin) getTransitGatewayAttachmentCounts(ctx context.Context) (map[string]int, error) {
+	counts := make(map[string]int)
+	var nextToken *string
+
+	for {
+		output, err := p.tgwClient().DescribeTransitGatewayAttachments(ctx, &ec2.DescribeTransitGatewayAttachmentsInput{NextToken: nextToken})
+		if err != nil {
+			return nil, fmt.Errorf("describe transit gateway attachments: %w", err)
+		}
+
+		for _, a := range output.TransitGatewayAttachments {
+			switch a.State {
+			case ec2types.TransitGatewayAttachmentStateDeleted, ec2types.TransitGatewayAttachmentStateDeleting:
+				continue
+			}
+			counts[aws.ToString(a.TransitGatewayId)]++
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	return counts, nil
+}
+
 // scanSecurityGroups scans security groups.
 func (p *Plugin) scanSecurityGroups(ctx context.Context) ([]resource.Resource, error) {
 	var resources []resource.Resource
@@ -439,9 +881,27 @@ func (p *Plugin) convertSecurityGroup(sg ec2types.SecurityGroup) resource.Resour
 	r.Attrs["description"] = aws.ToString(sg.Description)
 	r.Attrs["inbound_rules"] = strconv.Itoa(len(sg.IpPermissions))
 	r.Attrs["outbound_rules"] = strconv.Itoa(len(sg.IpPermissionsEgress))
+	r.Attrs["has_wide_open"] = strconv.FormatBool(hasWideOpenRule(sg.IpPermissions))
 	return r
 }
 
+// hasWideOpenRule reports whether any rule allows 0.0.0.0/0 or ::/0.
+func hasWideOpenRule(perms []ec2types.IpPermission) bool {
+	for _, perm := range perms {
+		for _, ip := range perm.IpRanges {
+			if aws.ToString(ip.CidrIp) == "0.0.0.0/0" {
+				return true
+			}
+		}
+		for _, ip := range perm.Ipv6Ranges {
+			if aws.ToString(ip.CidrIpv6) == "::/0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // scanDynamoDB scans DynamoDB tables.
 func (p *Plugin) scanDynamoDB(ctx context.Context) ([]resource.Resource, error) {
 	var resources []resource.Resource
@@ -454,8 +914,11 @@ func (p *Plugin) scanDynamoDB(ctx context.Context) ([]resource.Resource, error)
 		}
 
 		for _, tableName := range output.TableNames {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
 			desc, err := p.dynamodbClient().DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
-			if err != nil {
+			if err != nil || desc.Table == nil {
 				continue
 			}
 			resources = append(resources, p.convertDynamoDBTable(desc.Table))
@@ -472,6 +935,7 @@ func (p *Plugin) scanDynamoDB(ctx context.Context) ([]resource.Resource, error)
 
 func (p *Plugin) convertDynamoDBTable(table *ddbtypes.TableDescription) resource.Resource {
 	r := p.newResource(aws.ToString(table.TableArn), "dynamodb", string(table.TableStatus), aws.ToString(table.TableName))
+	r.CreatedAt = aws.ToTime(table.CreationDateTime)
 	r.Attrs["items"] = strconv.FormatInt(aws.ToInt64(table.ItemCount), 10)
 	r.Attrs["size_bytes"] = strconv.FormatInt(aws.ToInt64(table.TableSizeBytes), 10)
 	if table.BillingModeSummary != nil {
@@ -503,9 +967,76 @@ func (p *Plugin) scanSQS(ctx context.Context) ([]resource.Resource, error) {
 		nextToken = output.NextToken
 	}
 
+	if err := p.enrichSQSAttributes(ctx, resources); err != nil {
+		return nil, err
+	}
 	return resources, nil
 }
 
+// enrichSQSAttributes sets messages_available, messages_in_flight and is_dlq
+// from each queue's attributes. A queue is a DLQ when its name ends in -dlq
+// or another queue's redrive policy targets it. Queues whose attributes
+// cannot be read are left without depth attrs. The only error returned is
+// ctx.Err().
+func (p *Plugin) enrichSQSAttributes(ctx context.Context, resources []resource.Resource) error {
+	arns := make([]string, len(resources))
+	dlqTargets := make(map[string]bool)
+
+	for i := range resources {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r := &resources[i]
+		output, err := p.sqsClient().GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+			QueueUrl: aws.String(r.ID),
+			AttributeNames: []sqstypes.QueueAttributeName{
+				sqstypes.QueueAttributeNameQueueArn,
+				sqstypes.QueueAttributeNameApproximateNumberOfMessages,
+				sqstypes.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
+				sqstypes.QueueAttributeNameRedrivePolicy,
+			},
+		})
+		if err != nil {
+			log.Warn().Err(err).Str("queue", r.Name).Msg("failed to get queue attributes")
+			continue
+		}
+
+		attrs := output.Attributes
+		arns[i] = attrs[string(sqstypes.QueueAttributeNameQueueArn)]
+		if v, ok := attrs[string(sqstypes.QueueAttributeNameApproximateNumberOfMessages)]; ok {
+			r.Attrs["messages_available"] = v
+		}
+		if v, ok := attrs[string(sqstypes.QueueAttributeNameApproximateNumberOfMessagesNotVisible)]; ok {
+			r.Attrs["messages_in_flight"] = v
+		}
+		if target := redriveTarget(attrs[string(sqstypes.QueueAttributeNameRedrivePolicy)]); target != "" {
+			dlqTargets[target] = true
+		}
+	}
+
+	for i := range resources {
+		r := &resources[i]
+		isDLQ := strings.HasSuffix(r.Name, "-dlq") || (arns[i] != "" && dlqTargets[arns[i]])
+		r.Attrs["is_dlq"] = strconv.FormatBool(isDLQ)
+	}
+	return nil
+}
+
+// redriveTarget returns the dead-letter queue ARN from a RedrivePolicy
+// attribute, or "" when there is none.
+func redriveTarget(policy string) string {
+	if policy == "" {
+		return ""
+	}
+	var rp struct {
+		DeadLetterTargetArn string `json:"deadLetterTargetArn"`
+	}
+	if err := json.Unmarshal([]byte(policy), &rp); err != nil {
+		return ""
+	}
+	return rp.DeadLetterTargetArn
+}
+
 // scanEBSVolumes scans EBS volumes.
 func (p *Plugin) scanEBSVolumes(ctx context.Context) ([]resource.Resource, error) {
 	var resources []resource.Resource
@@ -532,6 +1063,7 @@ func (p *Plugin) scanEBSVolumes(ctx context.Context) ([]resource.Resource, error
 
 func (p *Plugin) convertEBSVolume(vol ec2types.Volume) resource.Resource {
 	r := p.newResource(aws.ToString(vol.VolumeId), "ebs", string(vol.State), extractNameTag(vol.Tags))
+	r.CreatedAt = aws.ToTime(vol.CreateTime)
 	for _, tag := range vol.Tags {
 		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
 	}
@@ -540,6 +1072,212 @@ func (p *Plugin) convertEBSVolume(vol ec2types.Volume) resource.Resource {
 	r.Attrs["az"] = aws.ToString(vol.AvailabilityZone)
 	r.Attrs["encrypted"] = strconv.FormatBool(aws.ToBool(vol.Encrypted))
 	r.Attrs["attached"] = strconv.FormatBool(len(vol.Attachments) > 0)
+	if vol.Iops != nil {
+		r.Attrs["iops"] = strconv.Itoa(int(aws.ToInt32(vol.Iops)))
+	}
+	if vol.Throughput != nil {
+		r.Attrs["throughput"] = strconv.Itoa(int(aws.ToInt32(vol.Throughput)))
+	}
+	excess := ebsExcessIOPS(vol)
+	r.Attrs["iops_overprovisioned"] = strconv.FormatBool(excess > 0)
+	if excess > 0 {
+		r.Attrs["iops_excess"] = strconv.Itoa(excess)
+	}
+	r.Attrs["gp3_candidate"] = strconv.FormatBool(vol.VolumeType == ec2types.VolumeTypeGp2)
+	return r
+}
+
+const (
+	// gp3BaselineIOPS is included with every gp3 volume at no extra cost.
+	gp3BaselineIOPS = 3000
+	// ioIOPSPerGBBaseline is the provisioned IOPS per GiB above which an
+	// io1/io2 volume is considered over-provisioned for its size.
+	ioIOPSPerGBBaseline = 10
+)
+
+// ebsExcessIOPS returns how many IOPS an io1/io2 volume provisions above
+// what its size warrants (and above the free gp3 baseline), or 0.
+func ebsExcessIOPS(vol ec2types.Volume) int {
+	if vol.VolumeType != ec2types.VolumeTypeIo1 && vol.VolumeType != ec2types.VolumeTypeIo2 {
+		return 0
+	}
+	baseline := max(gp3BaselineIOPS, int(aws.ToInt32(vol.Size))*ioIOPSPerGBBaseline)
+	return max(int(aws.ToInt32(vol.Iops))-baseline, 0)
+}
+
+const (
+	// defaultSnapshotUnmanagedAge is how old a snapshot outside any
+	// retention schedule must be before it is flagged.
+	defaultSnapshotUnmanagedAge = 30 * 24 * time.Hour
+	// snapshotMinSeries is how many evenly spaced snapshots of one source it
+	// takes to recognize a schedule, e.g. a cron job or external backup tool.
+	snapshotMinSeries = 3
+	// snapshotCadenceTolerance is how far a gap may deviate from the typical
+	// interval, as a fraction of it, and still count as on schedule.
+	snapshotCadenceTolerance = 0.25
+)
+
+// scanEBSSnapshots scans EBS snapshots owned by the account.
+func (p *Plugin) scanEBSSnapshots(ctx context.Context) ([]resource.Resource, error) {
+	var resources []resource.Resource
+	var nextToken *string
+
+	for {
+		output, err := p.ec2Client().DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{OwnerIds: []string{"self"}, NextToken: nextToken})
+		if err != nil {
+			return nil, fmt.Errorf("describe snapshots: %w", err)
+		}
+
+		for _, snap := range output.Snapshots {
+			resources = append(resources, p.convertEBSSnapshot(snap))
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	markUnmanagedSnapshots(resources, p.clock(), p.waste.snapshotUnmanagedAge())
+	return resources, nil
+}
+
+func (p *Plugin) convertEBSSnapshot(snap ec2types.Snapshot) resource.Resource {
+	r := p.newResource(aws.ToString(snap.SnapshotId), "ebs_snapshot", string(snap.State), extractNameTag(snap.Tags))
+	r.CreatedAt = aws.ToTime(snap.StartTime)
+	automated := false
+	for _, tag := range snap.Tags {
+		key := aws.ToString(tag.Key)
+		r.Labels[key] = aws.ToString(tag.Value)
+		// Data Lifecycle Manager and AWS Backup tag every snapshot they create
+		if strings.HasPrefix(key, "aws:dlm:") || strings.HasPrefix(key, "aws:backup:") {
+			automated = true
+		}
+	}
+	r.Attrs["source"] = aws.ToString(snap.VolumeId)
+	r.Attrs["volume_id"] = aws.ToString(snap.VolumeId)
+	r.Attrs["size_gb"] = strconv.Itoa(int(aws.ToInt32(snap.VolumeSize)))
+	r.Attrs["encrypted"] = strconv.FormatBool(aws.ToBool(snap.Encrypted))
+	if snap.StartTime != nil {
+		r.Attrs["started"] = snap.StartTime.Format("2006-01-02")
+	}
+	if snap.Description != nil {
+		r.Attrs["description"] = aws.ToString(snap.Description)
+	}
+	r.Attrs["automated"] = strconv.FormatBool(automated)
+	return r
+}
+
+// markUnmanagedSnapshots sets unmanaged=true on likely-manual snapshots that
+// no retention policy will delete. A snapshot is covered if a lifecycle
+// service created it (automated=true) or it belongs to a live, evenly spaced
+// series of snapshots of the same source. Anything else older than maxAge
+// is flagged.
+func markUnmanagedSnapshots(resources []resource.Resource, now time.Time, maxAge time.Duration) {
+	bySource := make(map[string][]*resource.Resource)
+	for i := range resources {
+		r := &resources[i]
+		r.Attrs["unmanaged"] = "false"
+		if r.Attrs["automated"] != "true" {
+			bySource[r.Attrs["source"]] = append(bySource[r.Attrs["source"]], r)
+		}
+	}
+
+	for _, snaps := range bySource {
+		scheduled := snapshotSeries(snaps, now)
+		for i, r := range snaps {
+			if !scheduled[i] && now.Sub(r.CreatedAt) > maxAge {
+				r.Attrs["unmanaged"] = "true"
+			}
+		}
+	}
+}
+
+// snapshotSeries sorts snaps by creation time and reports which of them are
+// spaced at the source's typical interval. The series only counts if it has
+// at least snapshotMinSeries members and is still running: its newest
+// snapshot is no more than two intervals old.
+func snapshotSeries(snaps []*resource.Resource, now time.Time) []bool {
+	scheduled := make([]bool, len(snaps))
+	if len(snaps) < snapshotMinSeries {
+		return scheduled
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].CreatedAt.Before(snaps[j].CreatedAt) })
+
+	gaps := make([]time.Duration, len(snaps)-1)
+	for i := range gaps {
+		gaps[i] = snaps[i+1].CreatedAt.Sub(snaps[i].CreatedAt)
+	}
+	sorted := slices.Clone(gaps)
+	slices.Sort(sorted)
+	typical := sorted[(len(sorted)-1)/2]
+	if typical <= 0 {
+		return scheduled
+	}
+
+	members, newest := 0, time.Time{}
+	for i, gap := range gaps {
+		if math.Abs(float64(gap-typical)) > snapshotCadenceTolerance*float64(typical) {
+			continue
+		}
+		for _, j := range []int{i, i + 1} {
+			if !scheduled[j] {
+				scheduled[j] = true
+				members++
+				newest = snaps[j].CreatedAt
+			}
+		}
+	}
+	if members < snapshotMinSeries || now.Sub(newest) > 2*typical {
+		return make([]bool, len(snaps))
+	}
+	return scheduled
+}
+
+// scanAMIs scans AMIs owned by the account. Each AMI keeps one EBS snapshot
+// per block device mapping alive, so old AMIs hide snapshot cost.
+func (p *Plugin) scanAMIs(ctx context.Context) ([]resource.Resource, error) {
+	var resources []resource.Resource
+	var nextToken *string
+
+	for {
+		output, err := p.ec2Client().DescribeImages(ctx, &ec2.DescribeImagesInput{Owners: []string{"self"}, NextToken: nextToken})
+		if err != nil {
+			return nil, fmt.Errorf("describe images: %w", err)
+		}
+
+		for _, image := range output.Images {
+			resources = append(resources, p.convertAMI(image))
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	return resources, nil
+}
+
+// defaultAMIObsoleteAge is how old a self-owned AMI must be before it is
+// flagged obsolete.
+const defaultAMIObsoleteAge = 90 * 24 * time.Hour
+
+func (p *Plugin) convertAMI(image ec2types.Image) resource.Resource {
+	r := p.newResource(aws.ToString(image.ImageId), "ami", string(image.State), aws.ToString(image.Name))
+	for _, tag := range image.Tags {
+		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	r.Attrs["architecture"] = string(image.Architecture)
+	r.Attrs["root_device_type"] = string(image.RootDeviceType)
+	r.Attrs["public"] = strconv.FormatBool(aws.ToBool(image.Public))
+	r.Attrs["snapshot_count"] = strconv.Itoa(len(image.BlockDeviceMappings))
+	if created, err := time.Parse(time.RFC3339, aws.ToString(image.CreationDate)); err == nil {
+		age := p.clock().Sub(created)
+		r.CreatedAt = created
+		r.Attrs["age_days"] = strconv.Itoa(int(age.Hours() / 24))
+		r.Attrs["obsolete"] = strconv.FormatBool(age > p.waste.amiObsoleteAge())
+	}
 	return r
 }
 
@@ -555,9 +1293,25 @@ func (p *Plugin) scanElasticIPs(ctx context.Context) ([]resource.Resource, error
 		resources = append(resources, p.convertElasticIP(addr))
 	}
 
+	markUnattachedEIPs(resources)
 	return resources, nil
 }
 
+// markUnattachedEIPs records on each unattached Elastic IP how many the
+// region has, so they can be released together. `elava cleanup` totals
+// their savings under the unattached category.
+func markUnattachedEIPs(resources []resource.Resource) {
+	var idx []int
+	for i, r := range resources {
+		if r.Status == "unattached" {
+			idx = append(idx, i)
+		}
+	}
+	for _, i := range idx {
+		resources[i].Attrs["unattached_count"] = strconv.Itoa(len(idx))
+	}
+}
+
 func (p *Plugin) convertElasticIP(addr ec2types.Address) resource.Resource {
 	status := "unattached"
 	if addr.AssociationId != nil {
@@ -594,23 +1348,64 @@ func (p *Plugin) scanNATGateways(ctx context.Context) ([]resource.Resource, erro
 		nextToken = output.NextToken
 	}
 
+	markRedundantNATGateways(resources)
 	return resources, nil
 }
 
+// markRedundantNATGateways flags extra NAT gateways sharing a subnet. One NAT
+// per subnet and connectivity type serves all traffic, so the oldest is kept
+// and the rest are marked redundant. Deleted/failed gateways and gateways
+// without a subnet are ignored.
+func markRedundantNATGateways(resources []resource.Resource) {
+	groups := make(map[string][]int)
+	for i, r := range resources {
+		if r.Status != string(ec2types.NatGatewayStateAvailable) && r.Status != string(ec2types.NatGatewayStatePending) {
+			continue
+		}
+		subnet := r.Attrs["subnet_id"]
+		if subnet == "" {
+			continue
+		}
+		key := subnet + "/" + r.Attrs["connectivity_type"]
+		groups[key] = append(groups[key], i)
+	}
+
+	for _, idx := range groups {
+		slices.SortStableFunc(idx, func(a, b int) int {
+			return resources[a].CreatedAt.Compare(resources[b].CreatedAt)
+		})
+		for n, i := range idx {
+			resources[i].Attrs["subnet_nat_count"] = strconv.Itoa(len(idx))
+			resources[i].Attrs["redundant"] = strconv.FormatBool(n > 0)
+		}
+	}
+}
+
 func (p *Plugin) convertNATGateway(nat ec2types.NatGateway) resource.Resource {
 	r := p.newResource(aws.ToString(nat.NatGatewayId), "nat_gateway", string(nat.State), extractNameTag(nat.Tags))
+	r.CreatedAt = aws.ToTime(nat.CreateTime)
 	for _, tag := range nat.Tags {
 		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
 	}
 	r.Attrs["vpc_id"] = aws.ToString(nat.VpcId)
 	r.Attrs["subnet_id"] = aws.ToString(nat.SubnetId)
+	r.Attrs["connectivity_type"] = string(nat.ConnectivityType)
 	if len(nat.NatGatewayAddresses) > 0 {
 		r.Attrs["public_ip"] = aws.ToString(nat.NatGatewayAddresses[0].PublicIp)
 	}
 	return r
 }
 
-// scanIAMRoles scans IAM roles.
+// defaultIAMRoleUnusedAge is how long a role can go without being assumed
+// before it is considered a cleanup candidate.
+const defaultIAMRoleUnusedAge = 90 * 24 * time.Hour
+
+// serviceLinkedRolePath is the path of roles created and managed by AWS services.
+const serviceLinkedRolePath = "/aws-service-role/"
+
+// scanIAMRoles scans IAM roles. Roles other than service-linked ones are
+// enriched with their last use; unused=true marks roles not used within
+// the unused age (default 90 days), or never used and older than that.
 func (p *Plugin) scanIAMRoles(ctx context.Context) ([]resource.Resource, error) {
 	var resources []resource.Resource
 	var marker *string
@@ -622,7 +1417,14 @@ func (p *Plugin) scanIAMRoles(ctx context.Context) ([]resource.Resource, error)
 		}
 
 		for _, role := range output.Roles {
-			resources = append(resources, p.convertIAMRole(role))
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			r := p.convertIAMRole(role)
+			if r.Attrs["service_linked"] != "true" {
+				p.enrichIAMRole(ctx, &r, aws.ToString(role.RoleName))
+			}
+			resources = append(resources, r)
 		}
 
 		if !output.IsTruncated {
@@ -634,13 +1436,53 @@ func (p *Plugin) scanIAMRoles(ctx context.Context) ([]resource.Resource, error)
 	return resources, nil
 }
 
-func (p *Plugin) convertIAMRole(role iamtypes.Role) resource.Resource {
-	r := p.newGlobalResource(aws.ToString(role.Arn), "iam_role", "active", aws.ToString(role.RoleName))
-	r.Attrs["path"] = aws.ToString(role.Path)
-	if role.Description != nil {
-		r.Attrs["description"] = aws.ToString(role.Description)
+func (p *Plugin) convertIAMRole(role iamtypes.Role) resource.Resource {
+	r := p.newGlobalResource(aws.ToString(role.Arn), "iam_role", "active", aws.ToString(role.RoleName))
+	r.CreatedAt = aws.ToTime(role.CreateDate)
+	r.Attrs["path"] = aws.ToString(role.Path)
+	if role.Description != nil {
+		r.Attrs["description"] = aws.ToString(role.Description)
+	}
+	r.Attrs["service_linked"] = strconv.FormatBool(strings.HasPrefix(aws.ToString(role.Path), serviceLinkedRolePath))
+	r.Attrs[resource.AttrTagsUnknown] = "true" // until enrichIAMRole reads them
+	return r
+}
+
+// enrichIAMRole records the tags, role_last_used and unused from GetRole,
+// since ListRoles omits tags and last-used data. On error the attributes are
+// left unset and the tags unknown.
+func (p *Plugin) enrichIAMRole(ctx context.Context, r *resource.Resource, name string) {
+	output, err := p.iamClient().GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(name)})
+	if err != nil {
+		log.Warn().Err(err).Str("role", name).Msg("failed to get iam role")
+		return
+	}
+	if output.Role == nil {
+		return
+	}
+	for _, tag := range output.Role.Tags {
+		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	delete(r.Attrs, resource.AttrTagsUnknown)
+
+	var lastUsed time.Time
+	if used := output.Role.RoleLastUsed; used != nil {
+		lastUsed = aws.ToTime(used.LastUsedDate)
+		if used.Region != nil {
+			r.Attrs["last_used_region"] = aws.ToString(used.Region)
+		}
+	}
+
+	// A never-used role is measured from its creation, so new roles get the
+	// same grace period as used ones.
+	since := lastUsed
+	if lastUsed.IsZero() {
+		r.Attrs["role_last_used"] = "never"
+		since = r.CreatedAt
+	} else {
+		r.Attrs["role_last_used"] = lastUsed.Format("2006-01-02")
 	}
-	return r
+	r.Attrs["unused"] = strconv.FormatBool(p.clock().Sub(since) > p.waste.iamRoleUnusedAge())
 }
 
 // scanECS scans ECS clusters.
@@ -669,6 +1511,9 @@ func (p *Plugin) scanECS(ctx context.Context) ([]resource.Resource, error) {
 	var resources []resource.Resource
 	const batchSize = 100
 	for i := 0; i < len(clusterArns); i += batchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		end := i + batchSize
 		if end > len(clusterArns) {
 			end = len(clusterArns)
@@ -844,7 +1689,14 @@ func (p *Plugin) scanElastiCache(ctx context.Context) ([]resource.Resource, erro
 		}
 
 		for _, cluster := range output.CacheClusters {
-			resources = append(resources, p.convertElastiCacheCluster(cluster))
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			r := p.convertElastiCacheCluster(cluster)
+			if p.idle.Enabled {
+				p.enrichElastiCacheActivity(ctx, &r, cluster)
+			}
+			resources = append(resources, r)
 		}
 
 		if output.Marker == nil {
@@ -858,6 +1710,7 @@ func (p *Plugin) scanElastiCache(ctx context.Context) ([]resource.Resource, erro
 
 func (p *Plugin) convertElastiCacheCluster(cluster ectypes.CacheCluster) resource.Resource {
 	r := p.newResource(aws.ToString(cluster.CacheClusterId), "elasticache", aws.ToString(cluster.CacheClusterStatus), aws.ToString(cluster.CacheClusterId))
+	r.CreatedAt = aws.ToTime(cluster.CacheClusterCreateTime)
 	r.Attrs["engine"] = aws.ToString(cluster.Engine)
 	r.Attrs["engine_version"] = aws.ToString(cluster.EngineVersion)
 	r.Attrs["node_type"] = aws.ToString(cluster.CacheNodeType)
@@ -865,6 +1718,65 @@ func (p *Plugin) convertElastiCacheCluster(cluster ectypes.CacheCluster) resourc
 	return r
 }
 
+// scanMemoryDB scans MemoryDB clusters.
+func (p *Plugin) scanMemoryDB(ctx context.Context) ([]resource.Resource, error) {
+	var resources []resource.Resource
+	var nextToken *string
+
+	for {
+		output, err := p.memorydbClient().DescribeClusters(ctx, &memorydb.DescribeClustersInput{
+			NextToken:        nextToken,
+			ShowShardDetails: aws.Bool(true), // node create times
+		})
+		if err != nil {
+			return nil, fmt.Errorf("describe memorydb clusters: %w", err)
+		}
+
+		for _, cluster := range output.Clusters {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			r := p.convertMemoryDBCluster(cluster)
+			if p.idle.Enabled {
+				p.enrichMemoryDBActivity(ctx, &r)
+			}
+			resources = append(resources, r)
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	return resources, nil
+}
+
+// convertMemoryDBCluster converts a MemoryDB cluster. MemoryDB reports no
+// cluster creation time, so CreatedAt is that of its oldest node.
+func (p *Plugin) convertMemoryDBCluster(cluster mdbtypes.Cluster) resource.Resource {
+	name := aws.ToString(cluster.Name)
+	r := p.newResource(name, "memorydb", aws.ToString(cluster.Status), name)
+
+	nodes := 0
+	for _, shard := range cluster.Shards {
+		for _, node := range shard.Nodes {
+			nodes++
+			created := aws.ToTime(node.CreateTime)
+			if !created.IsZero() && (r.CreatedAt.IsZero() || created.Before(r.CreatedAt)) {
+				r.CreatedAt = created
+			}
+		}
+	}
+
+	r.Attrs["engine"] = aws.ToString(cluster.Engine)
+	r.Attrs["engine_version"] = aws.ToString(cluster.EngineVersion)
+	r.Attrs["node_type"] = aws.ToString(cluster.NodeType)
+	r.Attrs["num_shards"] = strconv.Itoa(int(aws.ToInt32(cluster.NumberOfShards)))
+	r.Attrs["num_nodes"] = strconv.Itoa(nodes)
+	return r
+}
+
 // scanSecretsManager scans Secrets Manager secrets.
 func (p *Plugin) scanSecretsManager(ctx context.Context) ([]resource.Resource, error) {
 	var resources []resource.Resource
@@ -891,6 +1803,10 @@ func (p *Plugin) scanSecretsManager(ctx context.Context) ([]resource.Resource, e
 
 func (p *Plugin) convertSecret(secret smtypes.SecretListEntry) resource.Resource {
 	r := p.newResource(aws.ToString(secret.ARN), "secretsmanager", "active", aws.ToString(secret.Name))
+	r.CreatedAt = aws.ToTime(secret.CreatedDate)
+	for _, tag := range secret.Tags {
+		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
 	if secret.Description != nil {
 		r.Attrs["description"] = aws.ToString(secret.Description)
 	}
@@ -952,6 +1868,7 @@ func (p *Plugin) scanACM(ctx context.Context) ([]resource.Resource, error) {
 
 func (p *Plugin) convertACMCert(cert acmtypes.CertificateSummary) resource.Resource {
 	r := p.newResource(aws.ToString(cert.CertificateArn), "acm", string(cert.Status), aws.ToString(cert.DomainName))
+	r.CreatedAt = aws.ToTime(cert.CreatedAt)
 	r.Attrs["type"] = string(cert.Type)
 	return r
 }
@@ -982,6 +1899,10 @@ func (p *Plugin) scanAPIGateway(ctx context.Context) ([]resource.Resource, error
 
 func (p *Plugin) convertAPIGateway(api apigwtypes.Api) resource.Resource {
 	r := p.newResource(aws.ToString(api.ApiId), "apigateway", "active", aws.ToString(api.Name))
+	r.CreatedAt = aws.ToTime(api.CreatedDate)
+	for k, v := range api.Tags {
+		r.Labels[k] = v
+	}
 	r.Attrs["protocol"] = string(api.ProtocolType)
 	if api.ApiEndpoint != nil {
 		r.Attrs["endpoint"] = aws.ToString(api.ApiEndpoint)
@@ -1015,6 +1936,7 @@ func (p *Plugin) scanKinesis(ctx context.Context) ([]resource.Resource, error) {
 
 func (p *Plugin) convertKinesisStream(stream kinesistypes.StreamSummary) resource.Resource {
 	r := p.newResource(aws.ToString(stream.StreamARN), "kinesis", string(stream.StreamStatus), aws.ToString(stream.StreamName))
+	r.CreatedAt = aws.ToTime(stream.StreamCreationTimestamp)
 	return r
 }
 
@@ -1044,6 +1966,10 @@ func (p *Plugin) scanRedshift(ctx context.Context) ([]resource.Resource, error)
 
 func (p *Plugin) convertRedshiftCluster(cluster redshifttypes.Cluster) resource.Resource {
 	r := p.newResource(aws.ToString(cluster.ClusterIdentifier), "redshift", aws.ToString(cluster.ClusterStatus), aws.ToString(cluster.ClusterIdentifier))
+	r.CreatedAt = aws.ToTime(cluster.ClusterCreateTime)
+	for _, tag := range cluster.Tags {
+		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
 	r.Attrs["node_type"] = aws.ToString(cluster.NodeType)
 	r.Attrs["node_count"] = strconv.Itoa(int(aws.ToInt32(cluster.NumberOfNodes)))
 	if cluster.DBName != nil {
@@ -1078,6 +2004,7 @@ func (p *Plugin) scanStepFunctions(ctx context.Context) ([]resource.Resource, er
 
 func (p *Plugin) convertStateMachine(sm sfntypes.StateMachineListItem) resource.Resource {
 	r := p.newResource(aws.ToString(sm.StateMachineArn), "stepfunctions", "active", aws.ToString(sm.Name))
+	r.CreatedAt = aws.ToTime(sm.CreationDate)
 	r.Attrs["type"] = string(sm.Type)
 	return r
 }
@@ -1108,6 +2035,7 @@ func (p *Plugin) scanGlue(ctx context.Context) ([]resource.Resource, error) {
 
 func (p *Plugin) convertGlueDatabase(db gluetypes.Database) resource.Resource {
 	r := p.newResource(aws.ToString(db.Name), "glue_database", "active", aws.ToString(db.Name))
+	r.CreatedAt = aws.ToTime(db.CreateTime)
 	if db.Description != nil {
 		r.Attrs["description"] = aws.ToString(db.Description)
 	}
@@ -1123,10 +2051,13 @@ func (p *Plugin) scanOpenSearch(ctx context.Context) ([]resource.Resource, error
 
 	var resources []resource.Resource
 	for _, domainInfo := range output.DomainNames {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		descOutput, err := p.opensearchClient().DescribeDomain(ctx, &opensearch.DescribeDomainInput{
 			DomainName: domainInfo.DomainName,
 		})
-		if err != nil {
+		if err != nil || descOutput.DomainStatus == nil {
 			continue
 		}
 		resources = append(resources, p.convertOpenSearchDomain(descOutput.DomainStatus))
@@ -1185,6 +2116,7 @@ func (p *Plugin) scanMSK(ctx context.Context) ([]resource.Resource, error) {
 
 func (p *Plugin) convertMSKCluster(cluster kafkatypes.Cluster) resource.Resource {
 	r := p.newResource(aws.ToString(cluster.ClusterArn), "msk", string(cluster.State), aws.ToString(cluster.ClusterName))
+	r.CreatedAt = aws.ToTime(cluster.CreationTime)
 	for k, v := range cluster.Tags {
 		r.Labels[k] = v
 	}
@@ -1205,3 +2137,398 @@ func (p *Plugin) convertMSKCluster(cluster kafkatypes.Cluster) resource.Resource
 	}
 	return r
 }
+
+// scanEMR scans running and waiting EMR clusters. A WAITING cluster with no
+// pending or running steps is billed while doing nothing and is marked idle.
+func (p *Plugin) scanEMR(ctx context.Context) ([]resource.Resource, error) {
+	var resources []resource.Resource
+	var marker *string
+
+	for {
+		output, err := p.emrClient().ListClusters(ctx, &emr.ListClustersInput{
+			ClusterStates: []emrtypes.ClusterState{emrtypes.ClusterStateRunning, emrtypes.ClusterStateWaiting},
+			Marker:        marker,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list clusters: %w", err)
+		}
+
+		for _, cluster := range output.Clusters {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			r := p.convertEMRCluster(cluster)
+			p.enrichEMRSteps(ctx, &r)
+			resources = append(resources, r)
+		}
+
+		if output.Marker == nil {
+			break
+		}
+		marker = output.Marker
+	}
+
+	return resources, nil
+}
+
+func (p *Plugin) convertEMRCluster(cluster emrtypes.ClusterSummary) resource.Resource {
+	var state string
+	if cluster.Status != nil {
+		state = string(cluster.Status.State)
+	}
+	r := p.newResource(aws.ToString(cluster.Id), "emr", state, aws.ToString(cluster.Name))
+	if cluster.Status != nil && cluster.Status.Timeline != nil {
+		r.CreatedAt = aws.ToTime(cluster.Status.Timeline.CreationDateTime)
+	}
+	r.Attrs["state"] = state
+	r.Attrs["normalized_instance_hours"] = strconv.Itoa(int(aws.ToInt32(cluster.NormalizedInstanceHours)))
+	return r
+}
+
+// enrichEMRSteps sets active_steps to the number of pending and running
+// steps, and idle for WAITING clusters without any.
+func (p *Plugin) enrichEMRSteps(ctx context.Context, r *resource.Resource) {
+	active := 0
+	var marker *string
+	for {
+		output, err := p.emrClient().ListSteps(ctx, &emr.ListStepsInput{
+			ClusterId:  aws.String(r.ID),
+			StepStates: []emrtypes.StepState{emrtypes.StepStatePending, emrtypes.StepStateRunning},
+			Marker:     marker,
+		})
+		if err != nil {
+			log.Warn().Err(err).Str("cluster", r.ID).Msg("failed to list emr steps")
+			return
+		}
+		active += len(output.Steps)
+
+		if output.Marker == nil {
+			break
+		}
+		marker = output.Marker
+	}
+
+	r.Attrs["active_steps"] = strconv.Itoa(active)
+	r.Attrs["idle"] = strconv.FormatBool(r.Status == string(emrtypes.ClusterStateWaiting) && active == 0)
+}
+
+// scanWorkSpaces scans WorkSpaces virtual desktops.
+func (p *Plugin) scanWorkSpaces(ctx context.Context) ([]resource.Resource, error) {
+	var desktops []wstypes.Workspace
+	var nextToken *string
+
+	for {
+		output, err := p.workspacesClient().DescribeWorkspaces(ctx, &workspaces.DescribeWorkspacesInput{NextToken: nextToken})
+		if err != nil {
+			return nil, fmt.Errorf("describe workspaces: %w", err)
+		}
+		desktops = append(desktops, output.Workspaces...)
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	if len(desktops) == 0 {
+		return nil, nil
+	}
+
+	connections := p.getWorkSpacesConnections(ctx)
+
+	resources := make([]resource.Resource, 0, len(desktops))
+	for _, ws := range desktops {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		r := p.convertWorkSpace(ws)
+		if conn, ok := connections[aws.ToString(ws.WorkspaceId)]; ok {
+			p.applyWorkSpaceConnection(&r, conn)
+		}
+		resources = append(resources, r)
+	}
+
+	return resources, nil
+}
+
+func (p *Plugin) convertWorkSpace(ws wstypes.Workspace) resource.Resource {
+	r := p.newResource(aws.ToString(ws.WorkspaceId), "workspace", string(ws.State), aws.ToString(ws.WorkspaceName))
+	r.Attrs["bundle_id"] = aws.ToString(ws.BundleId)
+	r.Attrs["directory_id"] = aws.ToString(ws.DirectoryId)
+	r.Attrs["user"] = aws.ToString(ws.UserName)
+	if ws.WorkspaceProperties != nil {
+		r.Attrs["running_mode"] = string(ws.WorkspaceProperties.RunningMode)
+		r.Attrs["compute_type"] = string(ws.WorkspaceProperties.ComputeTypeName)
+	}
+	return r
+}
+
+// getWorkSpacesConnections fetches connection status for all WorkSpaces, keyed by ID.
+// Returns an empty map on error - connection data is an enrichment, not required.
+func (p *Plugin) getWorkSpacesConnections(ctx context.Context) map[string]wstypes.WorkspaceConnectionStatus {
+	connections := make(map[string]wstypes.WorkspaceConnectionStatus)
+	var nextToken *string
+
+	for {
+		output, err := p.workspacesClient().DescribeWorkspacesConnectionStatus(ctx, &workspaces.DescribeWorkspacesConnectionStatusInput{NextToken: nextToken})
+		if err != nil {
+			log.Warn().Err(err).Msg("failed to get workspaces connection status")
+			return connections
+		}
+		for _, conn := range output.WorkspacesConnectionStatus {
+			connections[aws.ToString(conn.WorkspaceId)] = conn
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	return connections
+}
+
+// applyWorkSpaceConnection records connection attrs and, with idle detection
+// enabled, flags ALWAYS_ON desktops that nobody connected to within the idle
+// window. AUTO_STOP desktops stop billing hourly when unused, so they are
+// never flagged.
+func (p *Plugin) applyWorkSpaceConnection(r *resource.Resource, conn wstypes.WorkspaceConnectionStatus) {
+	r.Attrs["connection_state"] = string(conn.ConnectionState)
+
+	lastConnected := conn.LastKnownUserConnectionTimestamp
+	if lastConnected != nil {
+		r.Attrs["last_connected"] = lastConnected.Format("2006-01-02")
+	}
+
+	if !p.idle.Enabled {
+		return
+	}
+	if r.Attrs["running_mode"] != string(wstypes.RunningModeAlwaysOn) {
+		r.Attrs["idle"] = "false"
+		return
+	}
+	idle := lastConnected == nil || p.clock().Sub(*lastConnected) > p.idle.Window
+	r.Attrs["idle"] = strconv.FormatBool(idle)
+}
+
+// scanRAMShares scans Resource Access Manager shares owned by this account
+// and shares received from other accounts. Owned shares are enriched with
+// resource and principal counts; external=true marks shares reaching
+// principals outside the organization.
+func (p *Plugin) scanRAMShares(ctx context.Context) ([]resource.Resource, error) {
+	owned, err := p.getResourceShares(ctx, ramtypes.ResourceOwnerSelf)
+	if err != nil {
+		return nil, err
+	}
+	received, err := p.getResourceShares(ctx, ramtypes.ResourceOwnerOtherAccounts)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]resource.Resource, 0, len(owned)+len(received))
+	if len(owned) > 0 {
+		resourceCounts := p.getRAMResourceCounts(ctx)
+		principals := p.getRAMPrincipals(ctx)
+		for _, share := range owned {
+			r := p.convertRAMShare(share, "outgoing")
+			arn := aws.ToString(share.ResourceShareArn)
+			if resourceCounts != nil {
+				r.Attrs["resource_count"] = strconv.Itoa(resourceCounts[arn])
+			}
+			if principals != nil {
+				applyRAMPrincipals(&r, principals[arn])
+			}
+			resources = append(resources, r)
+		}
+	}
+	for _, share := range received {
+		resources = append(resources, p.convertRAMShare(share, "incoming"))
+	}
+
+	return resources, nil
+}
+
+// getResourceShares lists active and pending shares for the given owner.
+func (p *Plugin) getResourceShares(ctx context.Context, owner ramtypes.ResourceOwner) ([]ramtypes.ResourceShare, error) {
+	var shares []ramtypes.ResourceShare
+	var nextToken *string
+
+	for {
+		output, err := p.ramClient().GetResourceShares(ctx, &ram.GetResourceSharesInput{ResourceOwner: owner, NextToken: nextToken})
+		if err != nil {
+			return nil, fmt.Errorf("get resource shares (%s): %w", owner, err)
+		}
+		for _, share := range output.ResourceShares {
+			if share.Status == ramtypes.ResourceShareStatusDeleted || share.Status == ramtypes.ResourceShareStatusDeleting {
+				continue
+			}
+			shares = append(shares, share)
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	return shares, nil
+}
+
+// getRAMResourceCounts counts shared resources per owned share ARN.
+// Returns nil on error - counts are an enrichment, not required.
+func (p *Plugin) getRAMResourceCounts(ctx context.Context) map[string]int {
+	counts := make(map[string]int)
+	var nextToken *string
+
+	for {
+		output, err := p.ramClient().ListResources(ctx, &ram.ListResourcesInput{ResourceOwner: ramtypes.ResourceOwnerSelf, NextToken: nextToken})
+		if err != nil {
+			log.Warn().Err(err).Msg("failed to list ram shared resources")
+			return nil
+		}
+		for _, res := range output.Resources {
+			counts[aws.ToString(res.ResourceShareArn)]++
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	return counts
+}
+
+// getRAMPrincipals groups principals of owned shares by share ARN.
+// Returns nil on error - principals are an enrichment, not required.
+func (p *Plugin) getRAMPrincipals(ctx context.Context) map[string][]ramtypes.Principal {
+	principals := make(map[string][]ramtypes.Principal)
+	var nextToken *string
+
+	for {
+		output, err := p.ramClient().ListPrincipals(ctx, &ram.ListPrincipalsInput{ResourceOwner: ramtypes.ResourceOwnerSelf, NextToken: nextToken})
+		if err != nil {
+			log.Warn().Err(err).Msg("failed to list ram principals")
+			return nil
+		}
+		for _, pr := range output.Principals {
+			arn := aws.ToString(pr.ResourceShareArn)
+			principals[arn] = append(principals[arn], pr)
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	return principals
+}
+
+// applyRAMPrincipals records the principal count and whether any principal
+// is outside the organization.
+func applyRAMPrincipals(r *resource.Resource, principals []ramtypes.Principal) {
+	external := false
+	for _, pr := range principals {
+		if aws.ToBool(pr.External) {
+			external = true
+			break
+		}
+	}
+	r.Attrs["principal_count"] = strconv.Itoa(len(principals))
+	r.Attrs["external"] = strconv.FormatBool(external)
+}
+
+func (p *Plugin) convertRAMShare(share ramtypes.ResourceShare, direction string) resource.Resource {
+	r := p.newResource(aws.ToString(share.ResourceShareArn), "ram_share", string(share.Status), aws.ToString(share.Name))
+	r.CreatedAt = aws.ToTime(share.CreationTime)
+	for _, tag := range share.Tags {
+		r.Labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	r.Attrs["direction"] = direction
+	r.Attrs["owner_account"] = aws.ToString(share.OwningAccountId)
+	r.Attrs["allow_external_principals"] = strconv.FormatBool(aws.ToBool(share.AllowExternalPrincipals))
+	return r
+}
+
+// defaultECRStaleAge is how long a repository can go without a push before
+// it is considered abandoned.
+const defaultECRStaleAge = 90 * 24 * time.Hour
+
+// scanECR scans ECR repositories. Each repository is enriched with its image
+// count and latest push; idle=true marks repositories that are empty or have
+// not been pushed to within the stale age (default 90 days).
+func (p *Plugin) scanECR(ctx context.Context) ([]resource.Resource, error) {
+	var resources []resource.Resource
+	var nextToken *string
+
+	for {
+		output, err := p.ecrClient().DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{NextToken: nextToken})
+		if err != nil {
+			return nil, fmt.Errorf("describe repositories: %w", err)
+		}
+
+		for _, repo := range output.Repositories {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			r := p.convertECRRepository(repo)
+			p.enrichECRRepository(ctx, &r, aws.ToString(repo.RepositoryName))
+			resources = append(resources, r)
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	return resources, nil
+}
+
+func (p *Plugin) convertECRRepository(repo ecrtypes.Repository) resource.Resource {
+	r := p.newResource(aws.ToString(repo.RepositoryArn), "ecr", "active", aws.ToString(repo.RepositoryName))
+	r.CreatedAt = aws.ToTime(repo.CreatedAt)
+	r.Attrs["uri"] = aws.ToString(repo.RepositoryUri)
+	r.Attrs["tag_mutability"] = string(repo.ImageTagMutability)
+	if repo.ImageScanningConfiguration != nil {
+		r.Attrs["scan_on_push"] = strconv.FormatBool(repo.ImageScanningConfiguration.ScanOnPush)
+	}
+	return r
+}
+
+// enrichECRRepository records image_count, last_pushed and idle. On error the
+// attributes are left unset - image data is an enrichment, not required.
+func (p *Plugin) enrichECRRepository(ctx context.Context, r *resource.Resource, name string) {
+	var (
+		count      int
+		lastPushed time.Time
+		nextToken  *string
+	)
+
+	for {
+		output, err := p.ecrClient().DescribeImages(ctx, &ecr.DescribeImagesInput{RepositoryName: aws.String(name), NextToken: nextToken})
+		if err != nil {
+			log.Warn().Err(err).Str("repository", name).Msg("failed to describe ecr images")
+			return
+		}
+		for _, img := range output.ImageDetails {
+			count++
+			if pushed := aws.ToTime(img.ImagePushedAt); pushed.After(lastPushed) {
+				lastPushed = pushed
+			}
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		nextToken = output.NextToken
+	}
+
+	r.Attrs["image_count"] = strconv.Itoa(count)
+	if !lastPushed.IsZero() {
+		r.Attrs["last_pushed"] = lastPushed.Format("2006-01-02")
+	}
+	idle := count == 0 || p.clock().Sub(lastPushed) > p.waste.ecrStaleAge()
+	r.Attrs["idle"] = strconv.FormatBool(idle)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
@@ -21,6 +23,8 @@ import (
 	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
@@ -29,14 +33,22 @@ import (
 	ectypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/emr"
+	emrtypes "github.com/aws/aws-sdk-go-v2/service/emr/types"
 	"github.com/aws/aws-sdk-go-v2/service/glue"
 	gluetypes "github.com/aws/aws-sdk-go-v2/service/glue/types"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/kafka"
+	kafkatypes "github.com/aws/aws-sdk-go-v2/service/kafka/types"
 	"github.com/aws/aws-sdk-go-v2/service/kinesis"
 	kinesistypes "github.com/aws/aws-sdk-go-v2/service/kinesis/types"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/memorydb"
+	mdbtypes "github.com/aws/aws-sdk-go-v2/service/memorydb/types"
+	"github.com/aws/aws-sdk-go-v2/service/ram"
+	ramtypes "github.com/aws/aws-sdk-go-v2/service/ram/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/aws/aws-sdk-go-v2/service/redshift"
@@ -52,8 +64,13 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/workspaces"
+	wstypes "github.com/aws/aws-sdk-go-v2/service/workspaces/types"
+	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
 )
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -62,12 +79,17 @@ import (
 
 type mockRDSClient struct {
 	DescribeDBInstancesFunc func(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+	DescribeDBSnapshotsFunc func(ctx context.Context, params *rds.DescribeDBSnapshotsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBSnapshotsOutput, error)
 }
 
 func (m *mockRDSClient) DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
 	return m.DescribeDBInstancesFunc(ctx, params, optFns...)
 }
 
+func (m *mockRDSClient) DescribeDBSnapshots(ctx context.Context, params *rds.DescribeDBSnapshotsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBSnapshotsOutput, error) {
+	return m.DescribeDBSnapshotsFunc(ctx, params, optFns...)
+}
+
 func TestScanRDS(t *testing.T) {
 	mock := &mockRDSClient{
 		DescribeDBInstancesFunc: func(_ context.Context, _ *rds.DescribeDBInstancesInput, _ ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
@@ -102,6 +124,34 @@ func TestScanRDS(t *testing.T) {
 	assert.Equal(t, "db.t3.micro", r.Attrs["instance_class"])
 }
 
+func TestScanRDS_MissingFields(t *testing.T) {
+	mock := &mockRDSClient{
+		DescribeDBInstancesFunc: func(_ context.Context, _ *rds.DescribeDBInstancesInput, _ ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+			return &rds.DescribeDBInstancesOutput{
+				DBInstances: []rdstypes.DBInstance{
+					{DBInstanceIdentifier: aws.String("new-db"), DBInstanceStatus: aws.String("creating")},
+					{DBInstanceIdentifier: aws.String("odd-db")},
+				},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", rdsClient: func() RDSAPI { return mock }}
+	resources, err := p.scanRDS(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	creating := resources[0]
+	assert.Equal(t, "creating", creating.Status)
+	assert.NotContains(t, creating.Attrs, "endpoint")
+	assert.NotContains(t, creating.Attrs, "partial_data")
+
+	noStatus := resources[1]
+	assert.Equal(t, "unknown", noStatus.Status)
+	assert.Equal(t, "true", noStatus.Attrs["partial_data"])
+}
+
 func TestScanRDS_Error(t *testing.T) {
 	mock := &mockRDSClient{
 		DescribeDBInstancesFunc: func(_ context.Context, _ *rds.DescribeDBInstancesInput, _ ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
@@ -121,8 +171,12 @@ func TestScanRDS_Error(t *testing.T) {
 // ══════════════════════════════════════════════════════════════════════════════
 
 type mockS3Client struct {
-	ListBucketsFunc       func(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
-	GetBucketLocationFunc func(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error)
+	ListBucketsFunc                     func(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	GetBucketLocationFunc               func(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error)
+	GetBucketVersioningFunc             func(ctx context.Context, params *s3.GetBucketVersioningInput, optFns ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error)
+	GetBucketEncryptionFunc             func(ctx context.Context, params *s3.GetBucketEncryptionInput, optFns ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error)
+	GetPublicAccessBlockFunc            func(ctx context.Context, params *s3.GetPublicAccessBlockInput, optFns ...func(*s3.Options)) (*s3.GetPublicAccessBlockOutput, error)
+	GetBucketLifecycleConfigurationFunc func(ctx context.Context, params *s3.GetBucketLifecycleConfigurationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error)
 }
 
 func (m *mockS3Client) ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
@@ -137,6 +191,22 @@ func (m *mockS3Client) GetBucketLocation(ctx context.Context, params *s3.GetBuck
 	return &s3.GetBucketLocationOutput{}, nil
 }
 
+func (m *mockS3Client) GetBucketVersioning(ctx context.Context, params *s3.GetBucketVersioningInput, optFns ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+	return m.GetBucketVersioningFunc(ctx, params, optFns...)
+}
+
+func (m *mockS3Client) GetBucketEncryption(ctx context.Context, params *s3.GetBucketEncryptionInput, optFns ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+	return m.GetBucketEncryptionFunc(ctx, params, optFns...)
+}
+
+func (m *mockS3Client) GetPublicAccessBlock(ctx context.Context, params *s3.GetPublicAccessBlockInput, optFns ...func(*s3.Options)) (*s3.GetPublicAccessBlockOutput, error) {
+	return m.GetPublicAccessBlockFunc(ctx, params, optFns...)
+}
+
+func (m *mockS3Client) GetBucketLifecycleConfiguration(ctx context.Context, params *s3.GetBucketLifecycleConfigurationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+	return m.GetBucketLifecycleConfigurationFunc(ctx, params, optFns...)
+}
+
 func TestScanS3(t *testing.T) {
 	mock := &mockS3Client{
 		ListBucketsFunc: func(_ context.Context, _ *s3.ListBucketsInput, _ ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
@@ -158,6 +228,149 @@ func TestScanS3(t *testing.T) {
 	assert.Equal(t, "my-bucket-1", resources[0].ID)
 	assert.Equal(t, "s3", resources[0].Type)
 	assert.Equal(t, "active", resources[0].Status)
+	assert.NotContains(t, resources[0].Attrs, "encryption")
+}
+
+func TestScanS3_Enrich(t *testing.T) {
+	mock := &mockS3Client{
+		ListBucketsFunc: func(_ context.Context, _ *s3.ListBucketsInput, _ ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+			return &s3.ListBucketsOutput{
+				Buckets: []s3types.Bucket{{Name: aws.String("private")}, {Name: aws.String("public")}},
+			}, nil
+		},
+		GetBucketVersioningFunc: func(_ context.Context, in *s3.GetBucketVersioningInput, _ ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+			if aws.ToString(in.Bucket) == "private" {
+				return &s3.GetBucketVersioningOutput{Status: s3types.BucketVersioningStatusEnabled}, nil
+			}
+			return &s3.GetBucketVersioningOutput{}, nil
+		},
+		GetBucketEncryptionFunc: func(_ context.Context, in *s3.GetBucketEncryptionInput, _ ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+			if aws.ToString(in.Bucket) == "private" {
+				return &s3.GetBucketEncryptionOutput{
+					ServerSideEncryptionConfiguration: &s3types.ServerSideEncryptionConfiguration{
+						Rules: []s3types.ServerSideEncryptionRule{
+							{ApplyServerSideEncryptionByDefault: &s3types.ServerSideEncryptionByDefault{SSEAlgorithm: s3types.ServerSideEncryptionAwsKms}},
+						},
+					},
+				}, nil
+			}
+			return nil, &smithy.GenericAPIError{Code: "ServerSideEncryptionConfigurationNotFoundError"}
+		},
+		GetPublicAccessBlockFunc: func(_ context.Context, in *s3.GetPublicAccessBlockInput, _ ...func(*s3.Options)) (*s3.GetPublicAccessBlockOutput, error) {
+			if aws.ToString(in.Bucket) == "private" {
+				return &s3.GetPublicAccessBlockOutput{
+					PublicAccessBlockConfiguration: &s3types.PublicAccessBlockConfiguration{
+						BlockPublicAcls:       aws.Bool(true),
+						IgnorePublicAcls:      aws.Bool(true),
+						BlockPublicPolicy:     aws.Bool(true),
+						RestrictPublicBuckets: aws.Bool(true),
+					},
+				}, nil
+			}
+			return nil, &smithy.GenericAPIError{Code: "NoSuchPublicAccessBlockConfiguration"}
+		},
+		GetBucketLifecycleConfigurationFunc: func(_ context.Context, in *s3.GetBucketLifecycleConfigurationInput, _ ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+			if aws.ToString(in.Bucket) == "private" {
+				return &s3.GetBucketLifecycleConfigurationOutput{Rules: []s3types.LifecycleRule{{ID: aws.String("expire-logs")}}}, nil
+			}
+			return nil, &smithy.GenericAPIError{Code: "NoSuchLifecycleConfiguration"}
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", s3Enrich: true, s3Client: func() S3API { return mock }}
+	resources, err := p.scanS3(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	private := resources[0].Attrs
+	assert.Equal(t, "enabled", private["versioning"])
+	assert.Equal(t, "aws:kms", private["encryption"])
+	assert.Equal(t, "true", private["public_access_blocked"])
+	assert.Equal(t, "1", private["lifecycle_rules"])
+
+	public := resources[1].Attrs
+	assert.Equal(t, "disabled", public["versioning"])
+	assert.Equal(t, "none", public["encryption"])
+	assert.Equal(t, "false", public["public_access_blocked"])
+	assert.Equal(t, "0", public["lifecycle_rules"])
+}
+
+func TestScanS3_EnrichAccessDenied(t *testing.T) {
+	denied := &smithy.GenericAPIError{Code: "AccessDenied"}
+	mock := &mockS3Client{
+		ListBucketsFunc: func(_ context.Context, _ *s3.ListBucketsInput, _ ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+			return &s3.ListBucketsOutput{Buckets: []s3types.Bucket{{Name: aws.String("locked")}}}, nil
+		},
+		GetBucketVersioningFunc: func(context.Context, *s3.GetBucketVersioningInput, ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+			return nil, denied
+		},
+		GetBucketEncryptionFunc: func(context.Context, *s3.GetBucketEncryptionInput, ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+			return nil, denied
+		},
+		GetPublicAccessBlockFunc: func(context.Context, *s3.GetPublicAccessBlockInput, ...func(*s3.Options)) (*s3.GetPublicAccessBlockOutput, error) {
+			return nil, denied
+		},
+		GetBucketLifecycleConfigurationFunc: func(context.Context, *s3.GetBucketLifecycleConfigurationInput, ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+			return nil, denied
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", s3Enrich: true, s3Client: func() S3API { return mock }}
+	resources, err := p.scanS3(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	for _, key := range []string{"versioning", "encryption", "public_access_blocked", "lifecycle_rules"} {
+		assert.NotContains(t, resources[0].Attrs, key)
+	}
+}
+
+func TestScanS3_EnrichInBucketRegion(t *testing.T) {
+	var regions []string
+	record := func(optFns []func(*s3.Options)) {
+		var o s3.Options
+		for _, fn := range optFns {
+			fn(&o)
+		}
+		regions = append(regions, o.Region)
+	}
+	mock := &mockS3Client{
+		ListBucketsFunc: func(_ context.Context, _ *s3.ListBucketsInput, _ ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+			return &s3.ListBucketsOutput{Buckets: []s3types.Bucket{{Name: aws.String("eu-logs")}, {Name: aws.String("lost")}}}, nil
+		},
+		GetBucketLocationFunc: func(_ context.Context, in *s3.GetBucketLocationInput, _ ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+			if aws.ToString(in.Bucket) == "lost" {
+				return nil, &smithy.GenericAPIError{Code: "AccessDenied"}
+			}
+			return &s3.GetBucketLocationOutput{LocationConstraint: s3types.BucketLocationConstraintEuWest1}, nil
+		},
+		GetBucketVersioningFunc: func(_ context.Context, _ *s3.GetBucketVersioningInput, optFns ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+			record(optFns)
+			return &s3.GetBucketVersioningOutput{}, nil
+		},
+		GetBucketEncryptionFunc: func(_ context.Context, _ *s3.GetBucketEncryptionInput, optFns ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+			record(optFns)
+			return &s3.GetBucketEncryptionOutput{}, nil
+		},
+		GetPublicAccessBlockFunc: func(_ context.Context, _ *s3.GetPublicAccessBlockInput, optFns ...func(*s3.Options)) (*s3.GetPublicAccessBlockOutput, error) {
+			record(optFns)
+			return &s3.GetPublicAccessBlockOutput{}, nil
+		},
+		GetBucketLifecycleConfigurationFunc: func(_ context.Context, _ *s3.GetBucketLifecycleConfigurationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+			record(optFns)
+			return &s3.GetBucketLifecycleConfigurationOutput{}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", s3Enrich: true, s3Client: func() S3API { return mock }}
+	resources, err := p.scanS3(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, []string{"eu-west-1", "eu-west-1", "eu-west-1", "eu-west-1"}, regions, "only eu-logs is enriched, in its own region")
+	assert.Equal(t, "unknown", resources[1].Region)
+	assert.NotContains(t, resources[1].Attrs, "versioning")
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -210,6 +423,45 @@ func TestScanEKS(t *testing.T) {
 	assert.Equal(t, "prod", r.Labels["env"])
 }
 
+func TestScanEKS_NilCluster(t *testing.T) {
+	mock := &mockEKSClient{
+		ListClustersFunc: func(_ context.Context, _ *eks.ListClustersInput, _ ...func(*eks.Options)) (*eks.ListClustersOutput, error) {
+			return &eks.ListClustersOutput{Clusters: []string{"gone"}}, nil
+		},
+		DescribeClusterFunc: func(_ context.Context, _ *eks.DescribeClusterInput, _ ...func(*eks.Options)) (*eks.DescribeClusterOutput, error) {
+			return &eks.DescribeClusterOutput{}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", eksClient: func() EKSAPI { return mock }}
+	resources, err := p.scanEKS(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, resources)
+}
+
+func TestScanEKS_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	described := 0
+	mock := &mockEKSClient{
+		ListClustersFunc: func(_ context.Context, _ *eks.ListClustersInput, _ ...func(*eks.Options)) (*eks.ListClustersOutput, error) {
+			cancel() // scan cancelled while the list is being processed
+			return &eks.ListClustersOutput{Clusters: []string{"a", "b", "c"}}, nil
+		},
+		DescribeClusterFunc: func(_ context.Context, _ *eks.DescribeClusterInput, _ ...func(*eks.Options)) (*eks.DescribeClusterOutput, error) {
+			described++
+			return nil, errors.New("unexpected describe")
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", eksClient: func() EKSAPI { return mock }}
+	resources, err := p.scanEKS(ctx)
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, resources)
+	assert.Zero(t, described)
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // VPC Tests
 // ══════════════════════════════════════════════════════════════════════════════
@@ -380,6 +632,65 @@ func TestScanLambda(t *testing.T) {
 	assert.Equal(t, "128", r.Attrs["memory_mb"])
 }
 
+func TestScanLambda_NoState(t *testing.T) {
+	mock := &mockLambdaClient{
+		ListFunctionsFunc: func(_ context.Context, _ *lambda.ListFunctionsInput, _ ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error) {
+			// ListFunctions leaves State out, as the real API does
+			return &lambda.ListFunctionsOutput{
+				Functions: []lambdatypes.FunctionConfiguration{{
+					FunctionName: aws.String("my-function"),
+					FunctionArn:  aws.String("arn:aws:lambda:us-east-1:123456789012:function:my-function"),
+				}},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", lambdaClient: func() LambdaAPI { return mock }}
+	resources, err := p.scanLambda(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "Active", resources[0].Status)
+	assert.NotContains(t, resources[0].Attrs, "partial_data")
+	assert.Len(t, dropPartial(resources), 1, "kept with skip_partial")
+}
+
+func TestScanLambda_Stale(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	mock := &mockLambdaClient{
+		ListFunctionsFunc: func(_ context.Context, _ *lambda.ListFunctionsInput, _ ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error) {
+			return &lambda.ListFunctionsOutput{
+				Functions: []lambdatypes.FunctionConfiguration{{
+					FunctionName: aws.String("report-job"),
+					FunctionArn:  aws.String("arn:aws:lambda:us-east-1:123456789012:function:report-job"),
+					LastModified: aws.String("2024-05-01T09:30:00.000+0000"),
+				}},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name  string
+		waste WasteConfig
+		want  string
+	}{
+		{"default 30 days", WasteConfig{}, "true"},
+		{"90 days", WasteConfig{LambdaStaleAge: 90 * 24 * time.Hour}, "false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{region: "us-east-1", accountID: "123456789012", now: func() time.Time { return now }, waste: tt.waste, lambdaClient: func() LambdaAPI { return mock }}
+			resources, err := p.scanLambda(context.Background())
+
+			require.NoError(t, err)
+			require.Len(t, resources, 1)
+			assert.Equal(t, "2024-05-01", resources[0].Attrs["last_modified"])
+			assert.Equal(t, "44", resources[0].Attrs["days_since_modified"])
+			assert.Equal(t, tt.want, resources[0].Attrs["stale"])
+		})
+	}
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // ASG Tests
 // ══════════════════════════════════════════════════════════════════════════════
@@ -455,13 +766,21 @@ func TestScanASG_Stopped(t *testing.T) {
 // ══════════════════════════════════════════════════════════════════════════════
 
 type mockSQSClient struct {
-	ListQueuesFunc func(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error)
+	ListQueuesFunc         func(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error)
+	GetQueueAttributesFunc func(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
 }
 
 func (m *mockSQSClient) ListQueues(ctx context.Context, params *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
 	return m.ListQueuesFunc(ctx, params, optFns...)
 }
 
+func (m *mockSQSClient) GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	if m.GetQueueAttributesFunc == nil {
+		return &sqs.GetQueueAttributesOutput{}, nil
+	}
+	return m.GetQueueAttributesFunc(ctx, params, optFns...)
+}
+
 func TestScanSQS(t *testing.T) {
 	mock := &mockSQSClient{
 		ListQueuesFunc: func(_ context.Context, _ *sqs.ListQueuesInput, _ ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
@@ -484,6 +803,76 @@ func TestScanSQS(t *testing.T) {
 	assert.Equal(t, "sqs", resources[0].Type)
 	assert.Equal(t, "active", resources[0].Status)
 	assert.Equal(t, "orders-dlq", resources[1].Name)
+	assert.Equal(t, "false", resources[0].Attrs["is_dlq"])
+	assert.Equal(t, "true", resources[1].Attrs["is_dlq"])
+}
+
+func TestScanSQS_QueueAttributes(t *testing.T) {
+	const base = "https://sqs.us-east-1.amazonaws.com/123456789012/"
+	attrs := map[string]map[string]string{
+		base + "orders": {
+			"QueueArn":                              "arn:aws:sqs:us-east-1:123456789012:orders",
+			"ApproximateNumberOfMessages":           "1000",
+			"ApproximateNumberOfMessagesNotVisible": "12",
+			"RedrivePolicy":                         `{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:orders-failed","maxReceiveCount":5}`,
+		},
+		base + "orders-failed": {
+			"QueueArn":                              "arn:aws:sqs:us-east-1:123456789012:orders-failed",
+			"ApproximateNumberOfMessages":           "3",
+			"ApproximateNumberOfMessagesNotVisible": "0",
+		},
+	}
+	mock := &mockSQSClient{
+		ListQueuesFunc: func(_ context.Context, _ *sqs.ListQueuesInput, _ ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+			return &sqs.ListQueuesOutput{QueueUrls: []string{base + "orders", base + "orders-failed", base + "broken"}}, nil
+		},
+		GetQueueAttributesFunc: func(_ context.Context, in *sqs.GetQueueAttributesInput, _ ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+			a, ok := attrs[aws.ToString(in.QueueUrl)]
+			if !ok {
+				return nil, errors.New("access denied")
+			}
+			return &sqs.GetQueueAttributesOutput{Attributes: a}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", sqsClient: func() SQSAPI { return mock }}
+	resources, err := p.scanSQS(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	orders := resources[0]
+	assert.Equal(t, "1000", orders.Attrs["messages_available"])
+	assert.Equal(t, "12", orders.Attrs["messages_in_flight"])
+	assert.Equal(t, "false", orders.Attrs["is_dlq"])
+
+	assert.Equal(t, "true", resources[1].Attrs["is_dlq"], "redrive target is a DLQ")
+
+	broken := resources[2]
+	assert.NotContains(t, broken.Attrs, "messages_available")
+	assert.Equal(t, "false", broken.Attrs["is_dlq"])
+}
+
+func TestScanSQS_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fetched := 0
+	mock := &mockSQSClient{
+		ListQueuesFunc: func(_ context.Context, _ *sqs.ListQueuesInput, _ ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+			return &sqs.ListQueuesOutput{QueueUrls: []string{"q-a", "q-b", "q-c"}}, nil
+		},
+		GetQueueAttributesFunc: func(_ context.Context, _ *sqs.GetQueueAttributesInput, _ ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+			fetched++
+			cancel() // scan cancelled while queues are being enriched
+			return &sqs.GetQueueAttributesOutput{}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", sqsClient: func() SQSAPI { return mock }}
+	resources, err := p.scanSQS(ctx)
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, resources)
+	assert.Equal(t, 1, fetched)
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -492,12 +881,17 @@ func TestScanSQS(t *testing.T) {
 
 type mockELBClient struct {
 	DescribeLoadBalancersFunc func(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error)
+	DescribeTargetGroupsFunc  func(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error)
 }
 
 func (m *mockELBClient) DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
 	return m.DescribeLoadBalancersFunc(ctx, params, optFns...)
 }
 
+func (m *mockELBClient) DescribeTargetGroups(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error) {
+	return m.DescribeTargetGroupsFunc(ctx, params, optFns...)
+}
+
 func TestScanELB(t *testing.T) {
 	mock := &mockELBClient{
 		DescribeLoadBalancersFunc: func(_ context.Context, _ *elasticloadbalancingv2.DescribeLoadBalancersInput, _ ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
@@ -531,18 +925,91 @@ func TestScanELB(t *testing.T) {
 	assert.Equal(t, "internet-facing", r.Attrs["scheme"])
 }
 
+func TestScanELB_NilState(t *testing.T) {
+	mock := &mockELBClient{
+		DescribeLoadBalancersFunc: func(_ context.Context, _ *elasticloadbalancingv2.DescribeLoadBalancersInput, _ ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+			return &elasticloadbalancingv2.DescribeLoadBalancersOutput{
+				LoadBalancers: []elbtypes.LoadBalancer{{LoadBalancerName: aws.String("my-nlb")}},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", elbClient: func() ELBAPI { return mock }}
+	resources, err := p.scanELB(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "unknown", resources[0].Status)
+	assert.Equal(t, "true", resources[0].Attrs["partial_data"])
+}
+
+func TestScanTargetGroups(t *testing.T) {
+	mock := &mockELBClient{
+		DescribeTargetGroupsFunc: func(_ context.Context, _ *elasticloadbalancingv2.DescribeTargetGroupsInput, _ ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error) {
+			return &elasticloadbalancingv2.DescribeTargetGroupsOutput{
+				TargetGroups: []elbtypes.TargetGroup{
+					{
+						TargetGroupArn:   aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/web/abc"),
+						TargetGroupName:  aws.String("web"),
+						Protocol:         elbtypes.ProtocolEnumHttp,
+						Port:             aws.Int32(80),
+						TargetType:       elbtypes.TargetTypeEnumInstance,
+						VpcId:            aws.String("vpc-123"),
+						LoadBalancerArns: []string{"arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-alb/abc"},
+					},
+					{
+						TargetGroupArn:  aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/old/def"),
+						TargetGroupName: aws.String("old"),
+						Protocol:        elbtypes.ProtocolEnumTcp,
+						Port:            aws.Int32(443),
+						TargetType:      elbtypes.TargetTypeEnumIp,
+					},
+				},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", elbClient: func() ELBAPI { return mock }}
+	resources, err := p.scanTargetGroups(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	attached := resources[0]
+	assert.Equal(t, "target_group", attached.Type)
+	assert.Equal(t, "web", attached.Name)
+	assert.Equal(t, "attached", attached.Status)
+	assert.Equal(t, "false", attached.Attrs["orphaned"])
+	assert.Equal(t, "1", attached.Attrs["load_balancer_count"])
+	assert.Equal(t, "HTTP", attached.Attrs["protocol"])
+	assert.Equal(t, "80", attached.Attrs["port"])
+
+	orphaned := resources[1]
+	assert.Equal(t, "orphaned", orphaned.Status)
+	assert.Equal(t, "true", orphaned.Attrs["orphaned"])
+	assert.Equal(t, "0", orphaned.Attrs["load_balancer_count"])
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // IAM Role Tests
 // ══════════════════════════════════════════════════════════════════════════════
 
 type mockIAMClient struct {
 	ListRolesFunc func(ctx context.Context, params *iam.ListRolesInput, optFns ...func(*iam.Options)) (*iam.ListRolesOutput, error)
+	GetRoleFunc   func(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error)
 }
 
 func (m *mockIAMClient) ListRoles(ctx context.Context, params *iam.ListRolesInput, optFns ...func(*iam.Options)) (*iam.ListRolesOutput, error) {
 	return m.ListRolesFunc(ctx, params, optFns...)
 }
 
+func (m *mockIAMClient) GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+	if m.GetRoleFunc != nil {
+		return m.GetRoleFunc(ctx, params, optFns...)
+	}
+	return &iam.GetRoleOutput{}, nil
+}
+
 func TestScanIAMRoles(t *testing.T) {
 	mock := &mockIAMClient{
 		ListRolesFunc: func(_ context.Context, _ *iam.ListRolesInput, _ ...func(*iam.Options)) (*iam.ListRolesOutput, error) {
@@ -572,6 +1039,96 @@ func TestScanIAMRoles(t *testing.T) {
 	assert.Equal(t, "MyRole", r.Name)
 	assert.Equal(t, "/", r.Attrs["path"])
 	assert.Equal(t, "My test role", r.Attrs["description"])
+	assert.Equal(t, "false", r.Attrs["service_linked"])
+}
+
+func TestScanIAMRoles_LastUsed(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	role := func(name, path string, created time.Time) iamtypes.Role {
+		return iamtypes.Role{
+			RoleName:   aws.String(name),
+			Arn:        aws.String("arn:aws:iam::123456789012:role" + path + name),
+			Path:       aws.String(path),
+			CreateDate: aws.Time(created),
+		}
+	}
+	var fetched []string
+	mock := &mockIAMClient{
+		ListRolesFunc: func(_ context.Context, _ *iam.ListRolesInput, _ ...func(*iam.Options)) (*iam.ListRolesOutput, error) {
+			return &iam.ListRolesOutput{Roles: []iamtypes.Role{
+				role("never-used", "/", now.AddDate(-1, 0, 0)),
+				role("new", "/", now.AddDate(0, 0, -3)),
+				role("recent", "/", now.AddDate(-2, 0, 0)),
+				role("stale", "/", now.AddDate(-2, 0, 0)),
+				role("AWSServiceRoleForECS", "/aws-service-role/ecs.amazonaws.com/", now.AddDate(-3, 0, 0)),
+			}}, nil
+		},
+		GetRoleFunc: func(_ context.Context, in *iam.GetRoleInput, _ ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+			name := aws.ToString(in.RoleName)
+			fetched = append(fetched, name)
+			out := &iam.GetRoleOutput{Role: &iamtypes.Role{RoleName: in.RoleName}}
+			switch name {
+			case "never-used":
+				out.Role.Tags = []iamtypes.Tag{{Key: aws.String("team"), Value: aws.String("platform")}}
+			case "recent":
+				out.Role.RoleLastUsed = &iamtypes.RoleLastUsed{LastUsedDate: aws.Time(now.AddDate(0, 0, -5)), Region: aws.String("eu-west-1")}
+			case "stale":
+				out.Role.RoleLastUsed = &iamtypes.RoleLastUsed{LastUsedDate: aws.Time(now.AddDate(0, -6, 0)), Region: aws.String("us-east-1")}
+			}
+			return out, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", now: func() time.Time { return now }, iamClient: func() IAMAPI { return mock }}
+	resources, err := p.scanIAMRoles(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 5)
+	byName := make(map[string]resource.Resource)
+	for _, r := range resources {
+		byName[r.Name] = r
+	}
+
+	assert.Equal(t, "never", byName["never-used"].Attrs["role_last_used"])
+	assert.Equal(t, "true", byName["never-used"].Attrs["unused"])
+	assert.Equal(t, "platform", byName["never-used"].Labels["team"], "tags come from GetRole")
+	assert.False(t, byName["never-used"].TagsUnknown())
+
+	assert.Equal(t, "never", byName["new"].Attrs["role_last_used"])
+	assert.Equal(t, "false", byName["new"].Attrs["unused"], "new roles get a grace period")
+
+	assert.Equal(t, "2024-05-27", byName["recent"].Attrs["role_last_used"])
+	assert.Equal(t, "eu-west-1", byName["recent"].Attrs["last_used_region"])
+	assert.Equal(t, "false", byName["recent"].Attrs["unused"])
+
+	assert.Equal(t, "2023-12-01", byName["stale"].Attrs["role_last_used"])
+	assert.Equal(t, "true", byName["stale"].Attrs["unused"])
+
+	slr := byName["AWSServiceRoleForECS"]
+	assert.Equal(t, "true", slr.Attrs["service_linked"])
+	assert.NotContains(t, slr.Attrs, "unused")
+	assert.True(t, slr.TagsUnknown(), "not fetched, so tags not read")
+	assert.NotContains(t, fetched, "AWSServiceRoleForECS")
+}
+
+func TestScanIAMRoles_GetRoleError(t *testing.T) {
+	mock := &mockIAMClient{
+		ListRolesFunc: func(_ context.Context, _ *iam.ListRolesInput, _ ...func(*iam.Options)) (*iam.ListRolesOutput, error) {
+			return &iam.ListRolesOutput{Roles: []iamtypes.Role{{RoleName: aws.String("MyRole"), Path: aws.String("/")}}}, nil
+		},
+		GetRoleFunc: func(_ context.Context, _ *iam.GetRoleInput, _ ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", iamClient: func() IAMAPI { return mock }}
+	resources, err := p.scanIAMRoles(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.NotContains(t, resources[0].Attrs, "role_last_used")
+	assert.NotContains(t, resources[0].Attrs, "unused")
+	assert.True(t, resources[0].TagsUnknown())
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -808,39 +1365,243 @@ func TestScanSubnets(t *testing.T) {
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
-// Security Group Tests
+// VPC Endpoint Tests
 // ══════════════════════════════════════════════════════════════════════════════
 
-func TestScanSecurityGroups(t *testing.T) {
+func TestScanVPCEndpoints(t *testing.T) {
 	mock := &mockEC2Client{}
-	mock.describeSecurityGroupsFunc = func(_ context.Context, _ *ec2.DescribeSecurityGroupsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
-		return &ec2.DescribeSecurityGroupsOutput{
-			SecurityGroups: []ec2types.SecurityGroup{
+	mock.describeVpcEndpointsFunc = func(_ context.Context, _ *ec2.DescribeVpcEndpointsInput, _ ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error) {
+		return &ec2.DescribeVpcEndpointsOutput{
+			VpcEndpoints: []ec2types.VpcEndpoint{
 				{
-					GroupId:             aws.String("sg-123"),
-					GroupName:           aws.String("web-sg"),
-					VpcId:               aws.String("vpc-456"),
-					Description:         aws.String("Web server security group"),
-					IpPermissions:       []ec2types.IpPermission{{}, {}},
-					IpPermissionsEgress: []ec2types.IpPermission{{}},
+					VpcEndpointId:   aws.String("vpce-123"),
+					VpcEndpointType: ec2types.VpcEndpointTypeInterface,
+					ServiceName:     aws.String("com.amazonaws.us-east-1.ecr.api"),
+					VpcId:           aws.String("vpc-456"),
+					State:           ec2types.StateAvailable,
+					Tags:            []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String("ecr-api")}},
 				},
 			},
 		}, nil
 	}
 
 	p := &Plugin{region: "us-east-1", accountID: "123456789012", ec2Client: func() EC2API { return mock }}
-	resources, err := p.scanSecurityGroups(context.Background())
+	resources, err := p.scanVPCEndpoints(context.Background())
 
 	require.NoError(t, err)
 	require.Len(t, resources, 1)
 
 	r := resources[0]
-	assert.Equal(t, "sg-123", r.ID)
-	assert.Equal(t, "security_group", r.Type)
-	assert.Equal(t, "active", r.Status)
+	assert.Equal(t, "vpce-123", r.ID)
+	assert.Equal(t, "vpc_endpoint", r.Type)
+	assert.Equal(t, "available", r.Status)
+	assert.Equal(t, "ecr-api", r.Name)
+	assert.Equal(t, "com.amazonaws.us-east-1.ecr.api", r.Attrs["service_name"])
+	assert.Equal(t, "Interface", r.Attrs["endpoint_type"])
+	assert.Equal(t, "vpc-456", r.Attrs["vpc_id"])
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// Internet Gateway / Route Table Tests
+// ══════════════════════════════════════════════════════════════════════════════
+
+func TestScanInternetGateways(t *testing.T) {
+	mock := &mockEC2Client{}
+	mock.describeInternetGatewaysFunc = func(_ context.Context, _ *ec2.DescribeInternetGatewaysInput, _ ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+		return &ec2.DescribeInternetGatewaysOutput{
+			InternetGateways: []ec2types.InternetGateway{
+				{
+					InternetGatewayId: aws.String("igw-attached"),
+					Attachments:       []ec2types.InternetGatewayAttachment{{VpcId: aws.String("vpc-1"), State: ec2types.AttachmentStatusAttached}},
+					Tags:              []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String("main-igw")}},
+				},
+				{InternetGatewayId: aws.String("igw-orphan")},
+			},
+		}, nil
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", ec2Client: func() EC2API { return mock }}
+	resources, err := p.scanInternetGateways(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	attached := resources[0]
+	assert.Equal(t, "internet_gateway", attached.Type)
+	assert.Equal(t, "main-igw", attached.Name)
+	assert.Equal(t, "attached", attached.Status)
+	assert.Equal(t, "true", attached.Attrs["attached"])
+	assert.Equal(t, "vpc-1", attached.Attrs["vpc_id"])
+
+	orphan := resources[1]
+	assert.Equal(t, "detached", orphan.Status)
+	assert.Equal(t, "false", orphan.Attrs["attached"])
+	assert.NotContains(t, orphan.Attrs, "vpc_id")
+}
+
+func TestScanRouteTables(t *testing.T) {
+	mock := &mockEC2Client{}
+	mock.describeRouteTablesFunc = func(_ context.Context, _ *ec2.DescribeRouteTablesInput, _ ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error) {
+		return &ec2.DescribeRouteTablesOutput{
+			RouteTables: []ec2types.RouteTable{
+				{
+					RouteTableId: aws.String("rtb-main"),
+					VpcId:        aws.String("vpc-1"),
+					Routes:       []ec2types.Route{{DestinationCidrBlock: aws.String("10.0.0.0/16")}, {DestinationCidrBlock: aws.String("0.0.0.0/0")}},
+					Associations: []ec2types.RouteTableAssociation{{Main: aws.Bool(true)}},
+				},
+				{
+					RouteTableId: aws.String("rtb-private"),
+					VpcId:        aws.String("vpc-1"),
+					Routes:       []ec2types.Route{{DestinationCidrBlock: aws.String("10.0.0.0/16")}},
+					Associations: []ec2types.RouteTableAssociation{{Main: aws.Bool(false), SubnetId: aws.String("subnet-1")}},
+				},
+			},
+		}, nil
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", ec2Client: func() EC2API { return mock }}
+	resources, err := p.scanRouteTables(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	main := resources[0]
+	assert.Equal(t, "route_table", main.Type)
+	assert.Equal(t, "true", main.Attrs["main"])
+	assert.Equal(t, "2", main.Attrs["route_count"])
+	assert.Equal(t, "0", main.Attrs["subnet_count"])
+
+	private := resources[1]
+	assert.Equal(t, "false", private.Attrs["main"])
+	assert.Equal(t, "1", private.Attrs["route_count"])
+	assert.Equal(t, "1", private.Attrs["subnet_count"])
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// Transit Gateway Tests
+// ══════════════════════════════════════════════════════════════════════════════
+
+type mockTransitGatewayClient struct {
+	DescribeTransitGatewaysFunc           func(ctx context.Context, params *ec2.DescribeTransitGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeTransitGatewaysOutput, error)
+	DescribeTransitGatewayAttachmentsFunc func(ctx context.Context, params *ec2.DescribeTransitGatewayAttachmentsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeTransitGatewayAttachmentsOutput, error)
+}
+
+func (m *mockTransitGatewayClient) DescribeTransitGateways(ctx context.Context, params *ec2.DescribeTransitGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeTransitGatewaysOutput, error) {
+	return m.DescribeTransitGatewaysFunc(ctx, params, optFns...)
+}
+
+func (m *mockTransitGatewayClient) DescribeTransitGatewayAttachments(ctx context.Context, params *ec2.DescribeTransitGatewayAttachmentsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeTransitGatewayAttachmentsOutput, error) {
+	return m.DescribeTransitGatewayAttachmentsFunc(ctx, params, optFns...)
+}
+
+func TestScanTransitGateways(t *testing.T) {
+	mock := &mockTransitGatewayClient{
+		DescribeTransitGatewaysFunc: func(_ context.Context, _ *ec2.DescribeTransitGatewaysInput, _ ...func(*ec2.Options)) (*ec2.DescribeTransitGatewaysOutput, error) {
+			return &ec2.DescribeTransitGatewaysOutput{
+				TransitGateways: []ec2types.TransitGateway{
+					{
+						TransitGatewayId: aws.String("tgw-hub"),
+						State:            ec2types.TransitGatewayStateAvailable,
+						Options:          &ec2types.TransitGatewayOptions{AmazonSideAsn: aws.Int64(64512)},
+						Tags:             []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String("hub")}},
+					},
+					{TransitGatewayId: aws.String("tgw-old"), State: ec2types.TransitGatewayStateAvailable},
+				},
+			}, nil
+		},
+		DescribeTransitGatewayAttachmentsFunc: func(_ context.Context, _ *ec2.DescribeTransitGatewayAttachmentsInput, _ ...func(*ec2.Options)) (*ec2.DescribeTransitGatewayAttachmentsOutput, error) {
+			return &ec2.DescribeTransitGatewayAttachmentsOutput{
+				TransitGatewayAttachments: []ec2types.TransitGatewayAttachment{
+					{TransitGatewayId: aws.String("tgw-hub"), State: ec2types.TransitGatewayAttachmentStateAvailable},
+					{TransitGatewayId: aws.String("tgw-hub"), State: ec2types.TransitGatewayAttachmentStateAvailable},
+					{TransitGatewayId: aws.String("tgw-hub"), State: ec2types.TransitGatewayAttachmentStatePending},
+					{TransitGatewayId: aws.String("tgw-old"), State: ec2types.TransitGatewayAttachmentStateDeleted},
+				},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", tgwClient: func() TransitGatewayAPI { return mock }}
+	resources, err := p.scanTransitGateways(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	hub := resources[0]
+	assert.Equal(t, "transit_gateway", hub.Type)
+	assert.Equal(t, "available", hub.Status)
+	assert.Equal(t, "hub", hub.Name)
+	assert.Equal(t, "64512", hub.Attrs["amazon_side_asn"])
+	assert.Equal(t, "3", hub.Attrs["attachment_count"])
+
+	assert.Equal(t, "0", resources[1].Attrs["attachment_count"], "deleted attachments are not counted")
+}
+
+func TestScanTransitGateways_AttachmentsError(t *testing.T) {
+	mock := &mockTransitGatewayClient{
+		DescribeTransitGatewaysFunc: func(_ context.Context, _ *ec2.DescribeTransitGatewaysInput, _ ...func(*ec2.Options)) (*ec2.DescribeTransitGatewaysOutput, error) {
+			return &ec2.DescribeTransitGatewaysOutput{
+				TransitGateways: []ec2types.TransitGateway{{TransitGatewayId: aws.String("tgw-hub"), State: ec2types.TransitGatewayStateAvailable}},
+			}, nil
+		},
+		DescribeTransitGatewayAttachmentsFunc: func(_ context.Context, _ *ec2.DescribeTransitGatewayAttachmentsInput, _ ...func(*ec2.Options)) (*ec2.DescribeTransitGatewayAttachmentsOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", tgwClient: func() TransitGatewayAPI { return mock }}
+	resources, err := p.scanTransitGateways(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.NotContains(t, resources[0].Attrs, "attachment_count")
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// Security Group Tests
+// ══════════════════════════════════════════════════════════════════════════════
+
+func TestScanSecurityGroups(t *testing.T) {
+	mock := &mockEC2Client{}
+	mock.describeSecurityGroupsFunc = func(_ context.Context, _ *ec2.DescribeSecurityGroupsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+		return &ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []ec2types.SecurityGroup{
+				{
+					GroupId:             aws.String("sg-123"),
+					GroupName:           aws.String("web-sg"),
+					VpcId:               aws.String("vpc-456"),
+					Description:         aws.String("Web server security group"),
+					IpPermissions:       []ec2types.IpPermission{{}, {}},
+					IpPermissionsEgress: []ec2types.IpPermission{{}},
+				},
+				{
+					GroupId:   aws.String("sg-open"),
+					GroupName: aws.String("ssh-anywhere"),
+					IpPermissions: []ec2types.IpPermission{
+						{IpRanges: []ec2types.IpRange{{CidrIp: aws.String("10.0.0.0/8")}}},
+						{Ipv6Ranges: []ec2types.Ipv6Range{{CidrIpv6: aws.String("::/0")}}},
+					},
+				},
+			},
+		}, nil
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", ec2Client: func() EC2API { return mock }}
+	resources, err := p.scanSecurityGroups(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	r := resources[0]
+	assert.Equal(t, "sg-123", r.ID)
+	assert.Equal(t, "security_group", r.Type)
+	assert.Equal(t, "active", r.Status)
 	assert.Equal(t, "web-sg", r.Name)
 	assert.Equal(t, "2", r.Attrs["inbound_rules"])
 	assert.Equal(t, "1", r.Attrs["outbound_rules"])
+	assert.Equal(t, "false", r.Attrs["has_wide_open"])
+	assert.Equal(t, "true", resources[1].Attrs["has_wide_open"])
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -883,6 +1644,46 @@ func TestScanEBSVolumes(t *testing.T) {
 	assert.Equal(t, "true", r.Attrs["attached"])
 }
 
+func TestScanEBSVolumes_ProvisionedIOPS(t *testing.T) {
+	mock := &mockEC2Client{}
+	mock.describeVolumesFunc = func(_ context.Context, _ *ec2.DescribeVolumesInput, _ ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+		return &ec2.DescribeVolumesOutput{
+			Volumes: []ec2types.Volume{
+				{VolumeId: aws.String("vol-io1"), Size: aws.Int32(100), VolumeType: ec2types.VolumeTypeIo1, Iops: aws.Int32(5000)},
+				{VolumeId: aws.String("vol-gp3"), Size: aws.Int32(500), VolumeType: ec2types.VolumeTypeGp3, Iops: aws.Int32(3000), Throughput: aws.Int32(125)},
+				{VolumeId: aws.String("vol-io2"), Size: aws.Int32(1000), VolumeType: ec2types.VolumeTypeIo2, Iops: aws.Int32(8000)},
+				{VolumeId: aws.String("vol-gp2"), Size: aws.Int32(50), VolumeType: ec2types.VolumeTypeGp2, Iops: aws.Int32(150)},
+			},
+		}, nil
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", ec2Client: func() EC2API { return mock }}
+	resources, err := p.scanEBSVolumes(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 4)
+
+	io1 := resources[0]
+	assert.Equal(t, "5000", io1.Attrs["iops"])
+	assert.Equal(t, "true", io1.Attrs["iops_overprovisioned"])
+	assert.Equal(t, "2000", io1.Attrs["iops_excess"], "above the 3000 IOPS baseline")
+	assert.Equal(t, "false", io1.Attrs["gp3_candidate"])
+
+	gp3 := resources[1]
+	assert.Equal(t, "3000", gp3.Attrs["iops"])
+	assert.Equal(t, "125", gp3.Attrs["throughput"])
+	assert.Equal(t, "false", gp3.Attrs["iops_overprovisioned"])
+	assert.Equal(t, "false", gp3.Attrs["gp3_candidate"])
+
+	io2 := resources[2]
+	assert.Equal(t, "false", io2.Attrs["iops_overprovisioned"], "8000 IOPS is within 10/GiB for 1000 GiB")
+	assert.NotContains(t, io2.Attrs, "iops_excess")
+
+	gp2 := resources[3]
+	assert.Equal(t, "false", gp2.Attrs["iops_overprovisioned"])
+	assert.Equal(t, "true", gp2.Attrs["gp3_candidate"])
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // Elastic IP Tests
 // ══════════════════════════════════════════════════════════════════════════════
@@ -903,6 +1704,10 @@ func TestScanElasticIPs(t *testing.T) {
 					AllocationId: aws.String("eipalloc-456"),
 					PublicIp:     aws.String("54.4.5.6"),
 				},
+				{
+					AllocationId: aws.String("eipalloc-789"),
+					PublicIp:     aws.String("54.7.8.9"),
+				},
 			},
 		}, nil
 	}
@@ -911,7 +1716,7 @@ func TestScanElasticIPs(t *testing.T) {
 	resources, err := p.scanElasticIPs(context.Background())
 
 	require.NoError(t, err)
-	require.Len(t, resources, 2)
+	require.Len(t, resources, 3)
 
 	assert.Equal(t, "eipalloc-123", resources[0].ID)
 	assert.Equal(t, "eip", resources[0].Type)
@@ -919,6 +1724,9 @@ func TestScanElasticIPs(t *testing.T) {
 	assert.Equal(t, "54.1.2.3", resources[0].Attrs["public_ip"])
 
 	assert.Equal(t, "unattached", resources[1].Status)
+	assert.NotContains(t, resources[0].Attrs, "unattached_count")
+	assert.Equal(t, "2", resources[1].Attrs["unattached_count"])
+	assert.Equal(t, "2", resources[2].Attrs["unattached_count"])
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -956,6 +1764,50 @@ func TestScanNATGateways(t *testing.T) {
 	assert.Equal(t, "available", r.Status)
 	assert.Equal(t, "public-nat", r.Name)
 	assert.Equal(t, "54.1.2.3", r.Attrs["public_ip"])
+	assert.Equal(t, "1", r.Attrs["subnet_nat_count"])
+	assert.Equal(t, "false", r.Attrs["redundant"])
+}
+
+func TestScanNATGateways_RedundantInSubnet(t *testing.T) {
+	older := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock := &mockEC2Client{}
+	mock.describeNatGatewaysFunc = func(_ context.Context, _ *ec2.DescribeNatGatewaysInput, _ ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
+		return &ec2.DescribeNatGatewaysOutput{
+			NatGateways: []ec2types.NatGateway{
+				{NatGatewayId: aws.String("nat-new"), SubnetId: aws.String("subnet-a"), State: ec2types.NatGatewayStateAvailable, CreateTime: &newer},
+				{NatGatewayId: aws.String("nat-old"), SubnetId: aws.String("subnet-a"), State: ec2types.NatGatewayStateAvailable, CreateTime: &older},
+				{NatGatewayId: aws.String("nat-b"), SubnetId: aws.String("subnet-b"), State: ec2types.NatGatewayStateAvailable, CreateTime: &newer},
+				{NatGatewayId: aws.String("nat-gone"), SubnetId: aws.String("subnet-b"), State: ec2types.NatGatewayStateDeleted, CreateTime: &older},
+				{NatGatewayId: aws.String("nat-private"), SubnetId: aws.String("subnet-b"), State: ec2types.NatGatewayStateAvailable, ConnectivityType: ec2types.ConnectivityTypePrivate, CreateTime: &older},
+				{NatGatewayId: aws.String("nat-nosubnet-1"), State: ec2types.NatGatewayStatePending, CreateTime: &older},
+				{NatGatewayId: aws.String("nat-nosubnet-2"), State: ec2types.NatGatewayStatePending, CreateTime: &newer},
+			},
+		}, nil
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", ec2Client: func() EC2API { return mock }}
+	resources, err := p.scanNATGateways(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 7)
+
+	byID := make(map[string]map[string]string)
+	for _, r := range resources {
+		byID[r.ID] = r.Attrs
+	}
+
+	assert.Equal(t, "true", byID["nat-new"]["redundant"])
+	assert.Equal(t, "2", byID["nat-new"]["subnet_nat_count"])
+	assert.Equal(t, "false", byID["nat-old"]["redundant"])
+	assert.Equal(t, "false", byID["nat-b"]["redundant"])
+	assert.Equal(t, "1", byID["nat-b"]["subnet_nat_count"])
+	assert.NotContains(t, byID["nat-gone"], "redundant")
+	assert.Equal(t, "false", byID["nat-private"]["redundant"])
+	assert.Equal(t, "1", byID["nat-private"]["subnet_nat_count"])
+	assert.NotContains(t, byID["nat-nosubnet-1"], "redundant")
+	assert.NotContains(t, byID["nat-nosubnet-2"], "redundant")
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -1087,6 +1939,69 @@ func TestScanElastiCache(t *testing.T) {
 	assert.Equal(t, "cache.t3.micro", r.Attrs["node_type"])
 }
 
+// ══════════════════════════════════════════════════════════════════════════════
+// MemoryDB Tests
+// ══════════════════════════════════════════════════════════════════════════════
+
+type mockMemoryDBClient struct {
+	DescribeClustersFunc func(ctx context.Context, params *memorydb.DescribeClustersInput, optFns ...func(*memorydb.Options)) (*memorydb.DescribeClustersOutput, error)
+}
+
+func (m *mockMemoryDBClient) DescribeClusters(ctx context.Context, params *memorydb.DescribeClustersInput, optFns ...func(*memorydb.Options)) (*memorydb.DescribeClustersOutput, error) {
+	return m.DescribeClustersFunc(ctx, params, optFns...)
+}
+
+func TestScanMemoryDB(t *testing.T) {
+	oldest := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	var pages int
+	mock := &mockMemoryDBClient{
+		DescribeClustersFunc: func(_ context.Context, params *memorydb.DescribeClustersInput, _ ...func(*memorydb.Options)) (*memorydb.DescribeClustersOutput, error) {
+			pages++
+			assert.True(t, aws.ToBool(params.ShowShardDetails))
+			if params.NextToken == nil {
+				return &memorydb.DescribeClustersOutput{
+					Clusters: []mdbtypes.Cluster{{
+						Name:           aws.String("sessions"),
+						Status:         aws.String("available"),
+						Engine:         aws.String("valkey"),
+						EngineVersion:  aws.String("7.2"),
+						NodeType:       aws.String("db.r7g.large"),
+						NumberOfShards: aws.Int32(2),
+						Shards: []mdbtypes.Shard{
+							{Nodes: []mdbtypes.Node{{CreateTime: aws.Time(oldest.AddDate(0, 1, 0))}, {CreateTime: aws.Time(oldest)}}},
+							{Nodes: []mdbtypes.Node{{CreateTime: aws.Time(oldest.AddDate(0, 2, 0))}, {}}},
+						},
+					}},
+					NextToken: aws.String("page-2"),
+				}, nil
+			}
+			return &memorydb.DescribeClustersOutput{
+				Clusters: []mdbtypes.Cluster{{Name: aws.String("creating"), Status: aws.String("creating")}},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", memorydbClient: func() MemoryDBAPI { return mock }}
+	resources, err := p.scanMemoryDB(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, pages)
+	require.Len(t, resources, 2)
+
+	r := resources[0]
+	assert.Equal(t, "sessions", r.ID)
+	assert.Equal(t, "memorydb", r.Type)
+	assert.Equal(t, "available", r.Status)
+	assert.Equal(t, oldest, r.CreatedAt, "oldest node")
+	assert.Equal(t, "valkey", r.Attrs["engine"])
+	assert.Equal(t, "db.r7g.large", r.Attrs["node_type"])
+	assert.Equal(t, "2", r.Attrs["num_shards"])
+	assert.Equal(t, "4", r.Attrs["num_nodes"])
+
+	assert.True(t, resources[1].CreatedAt.IsZero())
+	assert.Equal(t, "0", resources[1].Attrs["num_nodes"])
+}
+
 // ══════════════════════════════════════════════════════════════════════════════
 // Secrets Manager Tests
 // ══════════════════════════════════════════════════════════════════════════════
@@ -1108,6 +2023,7 @@ func TestScanSecretsManager(t *testing.T) {
 						ARN:         aws.String("arn:aws:secretsmanager:us-east-1:123456789012:secret:db-password-abc123"),
 						Name:        aws.String("db-password"),
 						Description: aws.String("Database password"),
+						Tags:        []smtypes.Tag{{Key: aws.String("Owner"), Value: aws.String("alice")}},
 					},
 				},
 			}, nil
@@ -1125,6 +2041,7 @@ func TestScanSecretsManager(t *testing.T) {
 	assert.Equal(t, "db-password", r.Name)
 	assert.Equal(t, "active", r.Status)
 	assert.Equal(t, "Database password", r.Attrs["description"])
+	assert.True(t, r.HasOwnershipTag())
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -1212,6 +2129,7 @@ func TestScanAPIGateway(t *testing.T) {
 						Name:         aws.String("my-api"),
 						ProtocolType: apigwtypes.ProtocolTypeHttp,
 						ApiEndpoint:  aws.String("https://abc123.execute-api.us-east-1.amazonaws.com"),
+						Tags:         map[string]string{"team": "payments"},
 					},
 				},
 			}, nil
@@ -1229,6 +2147,7 @@ func TestScanAPIGateway(t *testing.T) {
 	assert.Equal(t, "my-api", r.Name)
 	assert.Equal(t, "active", r.Status)
 	assert.Equal(t, "HTTP", r.Attrs["protocol"])
+	assert.Equal(t, "payments", r.Labels["team"])
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -1293,6 +2212,7 @@ func TestScanRedshift(t *testing.T) {
 						NodeType:          aws.String("dc2.large"),
 						NumberOfNodes:     aws.Int32(2),
 						DBName:            aws.String("mydb"),
+						Tags:              []redshifttypes.Tag{{Key: aws.String("Project"), Value: aws.String("analytics")}},
 					},
 				},
 			}, nil
@@ -1311,6 +2231,7 @@ func TestScanRedshift(t *testing.T) {
 	assert.Equal(t, "available", r.Status)
 	assert.Equal(t, "dc2.large", r.Attrs["node_type"])
 	assert.Equal(t, "2", r.Attrs["node_count"])
+	assert.Equal(t, "analytics", r.Labels["Project"])
 }
 
 // ══════════════════════════════════════════════════════════════════════════════
@@ -1392,3 +2313,736 @@ func TestScanGlue(t *testing.T) {
 	assert.Equal(t, "active", r.Status)
 	assert.Equal(t, "Analytics database", r.Attrs["description"])
 }
+
+// ══════════════════════════════════════════════════════════════════════════════
+// MSK Tests
+// ══════════════════════════════════════════════════════════════════════════════
+
+type mockMSKClient struct {
+	ListClustersV2Func func(ctx context.Context, params *kafka.ListClustersV2Input, optFns ...func(*kafka.Options)) (*kafka.ListClustersV2Output, error)
+}
+
+func (m *mockMSKClient) ListClustersV2(ctx context.Context, params *kafka.ListClustersV2Input, optFns ...func(*kafka.Options)) (*kafka.ListClustersV2Output, error) {
+	return m.ListClustersV2Func(ctx, params, optFns...)
+}
+
+func TestScanMSK(t *testing.T) {
+	mock := &mockMSKClient{
+		ListClustersV2Func: func(_ context.Context, _ *kafka.ListClustersV2Input, _ ...func(*kafka.Options)) (*kafka.ListClustersV2Output, error) {
+			return &kafka.ListClustersV2Output{
+				ClusterInfoList: []kafkatypes.Cluster{
+					{
+						ClusterArn:  aws.String("arn:aws:kafka:us-east-1:123456789012:cluster/events/abc"),
+						ClusterName: aws.String("events"),
+						ClusterType: kafkatypes.ClusterTypeProvisioned,
+						State:       kafkatypes.ClusterStateActive,
+						Provisioned: &kafkatypes.Provisioned{
+							NumberOfBrokerNodes:       aws.Int32(3),
+							BrokerNodeGroupInfo:       &kafkatypes.BrokerNodeGroupInfo{InstanceType: aws.String("kafka.m5.large")},
+							CurrentBrokerSoftwareInfo: &kafkatypes.BrokerSoftwareInfo{KafkaVersion: aws.String("3.5.1")},
+						},
+					},
+				},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", mskClient: func() MSKAPI { return mock }}
+	resources, err := p.scanMSK(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	r := resources[0]
+	assert.Equal(t, "msk", r.Type)
+	assert.Equal(t, "ACTIVE", r.Status)
+	assert.Equal(t, "events", r.Name)
+	assert.Equal(t, "3", r.Attrs["broker_nodes"])
+	assert.Equal(t, "3.5.1", r.Attrs["kafka_version"])
+	assert.Equal(t, "kafka.m5.large", r.Attrs["instance_type"])
+	assert.Equal(t, "false", r.Attrs["serverless"])
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// EMR Tests
+// ══════════════════════════════════════════════════════════════════════════════
+
+type mockEMRClient struct {
+	ListClustersFunc func(ctx context.Context, params *emr.ListClustersInput, optFns ...func(*emr.Options)) (*emr.ListClustersOutput, error)
+	ListStepsFunc    func(ctx context.Context, params *emr.ListStepsInput, optFns ...func(*emr.Options)) (*emr.ListStepsOutput, error)
+}
+
+func (m *mockEMRClient) ListClusters(ctx context.Context, params *emr.ListClustersInput, optFns ...func(*emr.Options)) (*emr.ListClustersOutput, error) {
+	return m.ListClustersFunc(ctx, params, optFns...)
+}
+
+func (m *mockEMRClient) ListSteps(ctx context.Context, params *emr.ListStepsInput, optFns ...func(*emr.Options)) (*emr.ListStepsOutput, error) {
+	return m.ListStepsFunc(ctx, params, optFns...)
+}
+
+func TestScanEMR(t *testing.T) {
+	mock := &mockEMRClient{
+		ListClustersFunc: func(_ context.Context, in *emr.ListClustersInput, _ ...func(*emr.Options)) (*emr.ListClustersOutput, error) {
+			assert.ElementsMatch(t, []emrtypes.ClusterState{emrtypes.ClusterStateRunning, emrtypes.ClusterStateWaiting}, in.ClusterStates)
+			return &emr.ListClustersOutput{
+				Clusters: []emrtypes.ClusterSummary{
+					{
+						Id: aws.String("j-WAITING"), Name: aws.String("adhoc"), NormalizedInstanceHours: aws.Int32(640),
+						Status: &emrtypes.ClusterStatus{State: emrtypes.ClusterStateWaiting},
+					},
+					{
+						Id: aws.String("j-BUSY"), Name: aws.String("etl"), NormalizedInstanceHours: aws.Int32(32),
+						Status: &emrtypes.ClusterStatus{State: emrtypes.ClusterStateRunning},
+					},
+				},
+			}, nil
+		},
+		ListStepsFunc: func(_ context.Context, in *emr.ListStepsInput, _ ...func(*emr.Options)) (*emr.ListStepsOutput, error) {
+			if aws.ToString(in.ClusterId) == "j-BUSY" {
+				return &emr.ListStepsOutput{Steps: []emrtypes.StepSummary{{Id: aws.String("s-1")}}}, nil
+			}
+			return &emr.ListStepsOutput{}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", emrClient: func() EMRAPI { return mock }}
+	resources, err := p.scanEMR(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	waiting := resources[0]
+	assert.Equal(t, "emr", waiting.Type)
+	assert.Equal(t, "adhoc", waiting.Name)
+	assert.Equal(t, "WAITING", waiting.Attrs["state"])
+	assert.Equal(t, "640", waiting.Attrs["normalized_instance_hours"])
+	assert.Equal(t, "0", waiting.Attrs["active_steps"])
+	assert.Equal(t, "true", waiting.Attrs["idle"])
+
+	busy := resources[1]
+	assert.Equal(t, "1", busy.Attrs["active_steps"])
+	assert.Equal(t, "false", busy.Attrs["idle"])
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// WorkSpaces Tests
+// ══════════════════════════════════════════════════════════════════════════════
+
+type mockWorkSpacesClient struct {
+	DescribeWorkspacesFunc                 func(ctx context.Context, params *workspaces.DescribeWorkspacesInput, optFns ...func(*workspaces.Options)) (*workspaces.DescribeWorkspacesOutput, error)
+	DescribeWorkspacesConnectionStatusFunc func(ctx context.Context, params *workspaces.DescribeWorkspacesConnectionStatusInput, optFns ...func(*workspaces.Options)) (*workspaces.DescribeWorkspacesConnectionStatusOutput, error)
+}
+
+func (m *mockWorkSpacesClient) DescribeWorkspaces(ctx context.Context, params *workspaces.DescribeWorkspacesInput, optFns ...func(*workspaces.Options)) (*workspaces.DescribeWorkspacesOutput, error) {
+	return m.DescribeWorkspacesFunc(ctx, params, optFns...)
+}
+
+func (m *mockWorkSpacesClient) DescribeWorkspacesConnectionStatus(ctx context.Context, params *workspaces.DescribeWorkspacesConnectionStatusInput, optFns ...func(*workspaces.Options)) (*workspaces.DescribeWorkspacesConnectionStatusOutput, error) {
+	return m.DescribeWorkspacesConnectionStatusFunc(ctx, params, optFns...)
+}
+
+func TestScanWorkSpaces(t *testing.T) {
+	recent := time.Now().Add(-2 * time.Hour)
+	mock := &mockWorkSpacesClient{
+		DescribeWorkspacesFunc: func(_ context.Context, _ *workspaces.DescribeWorkspacesInput, _ ...func(*workspaces.Options)) (*workspaces.DescribeWorkspacesOutput, error) {
+			return &workspaces.DescribeWorkspacesOutput{
+				Workspaces: []wstypes.Workspace{
+					{
+						WorkspaceId:   aws.String("ws-alwayson"),
+						WorkspaceName: aws.String("alice-desktop"),
+						BundleId:      aws.String("wsb-123"),
+						UserName:      aws.String("alice"),
+						State:         wstypes.WorkspaceStateAvailable,
+						WorkspaceProperties: &wstypes.WorkspaceProperties{
+							RunningMode:     wstypes.RunningModeAlwaysOn,
+							ComputeTypeName: wstypes.ComputeStandard,
+						},
+					},
+					{
+						WorkspaceId: aws.String("ws-autostop"),
+						BundleId:    aws.String("wsb-456"),
+						UserName:    aws.String("bob"),
+						State:       wstypes.WorkspaceStateStopped,
+						WorkspaceProperties: &wstypes.WorkspaceProperties{
+							RunningMode: wstypes.RunningModeAutoStop,
+						},
+					},
+				},
+			}, nil
+		},
+		DescribeWorkspacesConnectionStatusFunc: func(_ context.Context, _ *workspaces.DescribeWorkspacesConnectionStatusInput, _ ...func(*workspaces.Options)) (*workspaces.DescribeWorkspacesConnectionStatusOutput, error) {
+			return &workspaces.DescribeWorkspacesConnectionStatusOutput{
+				WorkspacesConnectionStatus: []wstypes.WorkspaceConnectionStatus{
+					{WorkspaceId: aws.String("ws-alwayson"), ConnectionState: wstypes.ConnectionStateDisconnected},
+					{WorkspaceId: aws.String("ws-autostop"), ConnectionState: wstypes.ConnectionStateDisconnected, LastKnownUserConnectionTimestamp: &recent},
+				},
+			}, nil
+		},
+	}
+
+	p := &Plugin{
+		region:           "us-east-1",
+		accountID:        "123456789012",
+		idle:             IdleConfig{Enabled: true, Window: 7 * 24 * time.Hour},
+		workspacesClient: func() WorkSpacesAPI { return mock },
+	}
+	resources, err := p.scanWorkSpaces(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	alwaysOn := resources[0]
+	assert.Equal(t, "workspace", alwaysOn.Type)
+	assert.Equal(t, "alice-desktop", alwaysOn.Name)
+	assert.Equal(t, "AVAILABLE", alwaysOn.Status)
+	assert.Equal(t, "wsb-123", alwaysOn.Attrs["bundle_id"])
+	assert.Equal(t, "ALWAYS_ON", alwaysOn.Attrs["running_mode"])
+	assert.Equal(t, "STANDARD", alwaysOn.Attrs["compute_type"])
+	assert.Equal(t, "alice", alwaysOn.Attrs["user"])
+	assert.Equal(t, "DISCONNECTED", alwaysOn.Attrs["connection_state"])
+	assert.Empty(t, alwaysOn.Attrs["last_connected"])
+	assert.Equal(t, "true", alwaysOn.Attrs["idle"])
+
+	autoStop := resources[1]
+	assert.Equal(t, "STOPPED", autoStop.Status)
+	assert.Equal(t, "AUTO_STOP", autoStop.Attrs["running_mode"])
+	assert.Equal(t, recent.Format("2006-01-02"), autoStop.Attrs["last_connected"])
+	assert.Equal(t, "false", autoStop.Attrs["idle"])
+
+	p.idle.Enabled = false
+	resources, err = p.scanWorkSpaces(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "DISCONNECTED", resources[0].Attrs["connection_state"])
+	assert.NotContains(t, resources[0].Attrs, "idle", "idle detection is disabled")
+}
+
+func TestScanWorkSpaces_IdleWindowClock(t *testing.T) {
+	lastConnected := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	mock := &mockWorkSpacesClient{
+		DescribeWorkspacesFunc: func(_ context.Context, _ *workspaces.DescribeWorkspacesInput, _ ...func(*workspaces.Options)) (*workspaces.DescribeWorkspacesOutput, error) {
+			return &workspaces.DescribeWorkspacesOutput{
+				Workspaces: []wstypes.Workspace{{
+					WorkspaceId:         aws.String("ws-1"),
+					WorkspaceProperties: &wstypes.WorkspaceProperties{RunningMode: wstypes.RunningModeAlwaysOn},
+				}},
+			}, nil
+		},
+		DescribeWorkspacesConnectionStatusFunc: func(_ context.Context, _ *workspaces.DescribeWorkspacesConnectionStatusInput, _ ...func(*workspaces.Options)) (*workspaces.DescribeWorkspacesConnectionStatusOutput, error) {
+			return &workspaces.DescribeWorkspacesConnectionStatusOutput{
+				WorkspacesConnectionStatus: []wstypes.WorkspaceConnectionStatus{
+					{WorkspaceId: aws.String("ws-1"), LastKnownUserConnectionTimestamp: &lastConnected},
+				},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"within window", lastConnected.Add(6 * 24 * time.Hour), "false"},
+		{"past window", lastConnected.Add(8 * 24 * time.Hour), "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{
+				region:           "us-east-1",
+				accountID:        "123456789012",
+				idle:             IdleConfig{Enabled: true, Window: 7 * 24 * time.Hour},
+				now:              func() time.Time { return tt.now },
+				workspacesClient: func() WorkSpacesAPI { return mock },
+			}
+			resources, err := p.scanWorkSpaces(context.Background())
+
+			require.NoError(t, err)
+			require.Len(t, resources, 1)
+			assert.Equal(t, tt.want, resources[0].Attrs["idle"])
+			assert.Equal(t, tt.now, resources[0].ScannedAt)
+		})
+	}
+}
+
+func TestScanWorkSpaces_ConnectionStatusError(t *testing.T) {
+	mock := &mockWorkSpacesClient{
+		DescribeWorkspacesFunc: func(_ context.Context, _ *workspaces.DescribeWorkspacesInput, _ ...func(*workspaces.Options)) (*workspaces.DescribeWorkspacesOutput, error) {
+			return &workspaces.DescribeWorkspacesOutput{
+				Workspaces: []wstypes.Workspace{
+					{WorkspaceId: aws.String("ws-1"), State: wstypes.WorkspaceStateAvailable},
+				},
+			}, nil
+		},
+		DescribeWorkspacesConnectionStatusFunc: func(_ context.Context, _ *workspaces.DescribeWorkspacesConnectionStatusInput, _ ...func(*workspaces.Options)) (*workspaces.DescribeWorkspacesConnectionStatusOutput, error) {
+			return nil, errors.New("throttled")
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", workspacesClient: func() WorkSpacesAPI { return mock }}
+	resources, err := p.scanWorkSpaces(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.NotContains(t, resources[0].Attrs, "idle")
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// RAM Tests
+// ══════════════════════════════════════════════════════════════════════════════
+
+type mockRAMClient struct {
+	GetResourceSharesFunc func(ctx context.Context, params *ram.GetResourceSharesInput, optFns ...func(*ram.Options)) (*ram.GetResourceSharesOutput, error)
+	ListResourcesFunc     func(ctx context.Context, params *ram.ListResourcesInput, optFns ...func(*ram.Options)) (*ram.ListResourcesOutput, error)
+	ListPrincipalsFunc    func(ctx context.Context, params *ram.ListPrincipalsInput, optFns ...func(*ram.Options)) (*ram.ListPrincipalsOutput, error)
+}
+
+func (m *mockRAMClient) GetResourceShares(ctx context.Context, params *ram.GetResourceSharesInput, optFns ...func(*ram.Options)) (*ram.GetResourceSharesOutput, error) {
+	return m.GetResourceSharesFunc(ctx, params, optFns...)
+}
+
+func (m *mockRAMClient) ListResources(ctx context.Context, params *ram.ListResourcesInput, optFns ...func(*ram.Options)) (*ram.ListResourcesOutput, error) {
+	return m.ListResourcesFunc(ctx, params, optFns...)
+}
+
+func (m *mockRAMClient) ListPrincipals(ctx context.Context, params *ram.ListPrincipalsInput, optFns ...func(*ram.Options)) (*ram.ListPrincipalsOutput, error) {
+	return m.ListPrincipalsFunc(ctx, params, optFns...)
+}
+
+func TestScanRAMShares(t *testing.T) {
+	const (
+		internalARN = "arn:aws:ram:us-east-1:123456789012:resource-share/internal"
+		externalARN = "arn:aws:ram:us-east-1:123456789012:resource-share/external"
+		incomingARN = "arn:aws:ram:us-east-1:999999999999:resource-share/incoming"
+	)
+	mock := &mockRAMClient{
+		GetResourceSharesFunc: func(_ context.Context, in *ram.GetResourceSharesInput, _ ...func(*ram.Options)) (*ram.GetResourceSharesOutput, error) {
+			if in.ResourceOwner == ramtypes.ResourceOwnerOtherAccounts {
+				return &ram.GetResourceSharesOutput{
+					ResourceShares: []ramtypes.ResourceShare{
+						{ResourceShareArn: aws.String(incomingARN), Name: aws.String("from-network"), OwningAccountId: aws.String("999999999999"), Status: ramtypes.ResourceShareStatusActive},
+					},
+				}, nil
+			}
+			return &ram.GetResourceSharesOutput{
+				ResourceShares: []ramtypes.ResourceShare{
+					{
+						ResourceShareArn:        aws.String(internalARN),
+						Name:                    aws.String("subnets-to-org"),
+						OwningAccountId:         aws.String("123456789012"),
+						AllowExternalPrincipals: aws.Bool(false),
+						Status:                  ramtypes.ResourceShareStatusActive,
+						Tags:                    []ramtypes.Tag{{Key: aws.String("team"), Value: aws.String("network")}},
+					},
+					{
+						ResourceShareArn:        aws.String(externalARN),
+						Name:                    aws.String("ami-to-vendor"),
+						OwningAccountId:         aws.String("123456789012"),
+						AllowExternalPrincipals: aws.Bool(true),
+						Status:                  ramtypes.ResourceShareStatusActive,
+					},
+					{ResourceShareArn: aws.String("arn:aws:ram:us-east-1:123456789012:resource-share/gone"), Status: ramtypes.ResourceShareStatusDeleted},
+				},
+			}, nil
+		},
+		ListResourcesFunc: func(_ context.Context, _ *ram.ListResourcesInput, _ ...func(*ram.Options)) (*ram.ListResourcesOutput, error) {
+			return &ram.ListResourcesOutput{
+				Resources: []ramtypes.Resource{
+					{Arn: aws.String("subnet-1"), ResourceShareArn: aws.String(internalARN)},
+					{Arn: aws.String("subnet-2"), ResourceShareArn: aws.String(internalARN)},
+					{Arn: aws.String("ami-1"), ResourceShareArn: aws.String(externalARN)},
+				},
+			}, nil
+		},
+		ListPrincipalsFunc: func(_ context.Context, _ *ram.ListPrincipalsInput, _ ...func(*ram.Options)) (*ram.ListPrincipalsOutput, error) {
+			return &ram.ListPrincipalsOutput{
+				Principals: []ramtypes.Principal{
+					{Id: aws.String("o-abc123"), ResourceShareArn: aws.String(internalARN), External: aws.Bool(false)},
+					{Id: aws.String("111111111111"), ResourceShareArn: aws.String(externalARN), External: aws.Bool(true)},
+				},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", ramClient: func() RAMAPI { return mock }}
+	resources, err := p.scanRAMShares(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	internal := resources[0]
+	assert.Equal(t, "ram_share", internal.Type)
+	assert.Equal(t, "ACTIVE", internal.Status)
+	assert.Equal(t, "subnets-to-org", internal.Name)
+	assert.Equal(t, "network", internal.Labels["team"])
+	assert.Equal(t, "outgoing", internal.Attrs["direction"])
+	assert.Equal(t, "2", internal.Attrs["resource_count"])
+	assert.Equal(t, "1", internal.Attrs["principal_count"])
+	assert.Equal(t, "false", internal.Attrs["external"])
+
+	external := resources[1]
+	assert.Equal(t, "1", external.Attrs["resource_count"])
+	assert.Equal(t, "true", external.Attrs["external"])
+	assert.Equal(t, "true", external.Attrs["allow_external_principals"])
+
+	incoming := resources[2]
+	assert.Equal(t, "incoming", incoming.Attrs["direction"])
+	assert.Equal(t, "999999999999", incoming.Attrs["owner_account"])
+	assert.NotContains(t, incoming.Attrs, "external")
+}
+
+func TestScanRAMShares_EnrichmentError(t *testing.T) {
+	mock := &mockRAMClient{
+		GetResourceSharesFunc: func(_ context.Context, in *ram.GetResourceSharesInput, _ ...func(*ram.Options)) (*ram.GetResourceSharesOutput, error) {
+			if in.ResourceOwner == ramtypes.ResourceOwnerOtherAccounts {
+				return &ram.GetResourceSharesOutput{}, nil
+			}
+			return &ram.GetResourceSharesOutput{
+				ResourceShares: []ramtypes.ResourceShare{{ResourceShareArn: aws.String("arn:share"), Status: ramtypes.ResourceShareStatusActive}},
+			}, nil
+		},
+		ListResourcesFunc: func(context.Context, *ram.ListResourcesInput, ...func(*ram.Options)) (*ram.ListResourcesOutput, error) {
+			return nil, errors.New("access denied")
+		},
+		ListPrincipalsFunc: func(context.Context, *ram.ListPrincipalsInput, ...func(*ram.Options)) (*ram.ListPrincipalsOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", ramClient: func() RAMAPI { return mock }}
+	resources, err := p.scanRAMShares(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.NotContains(t, resources[0].Attrs, "resource_count")
+	assert.NotContains(t, resources[0].Attrs, "external")
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// ECR Tests
+// ══════════════════════════════════════════════════════════════════════════════
+
+type mockECRClient struct {
+	DescribeRepositoriesFunc func(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error)
+	DescribeImagesFunc       func(ctx context.Context, params *ecr.DescribeImagesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error)
+}
+
+func (m *mockECRClient) DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+	return m.DescribeRepositoriesFunc(ctx, params, optFns...)
+}
+
+func (m *mockECRClient) DescribeImages(ctx context.Context, params *ecr.DescribeImagesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error) {
+	return m.DescribeImagesFunc(ctx, params, optFns...)
+}
+
+func TestScanECR(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	mock := &mockECRClient{
+		DescribeRepositoriesFunc: func(_ context.Context, _ *ecr.DescribeRepositoriesInput, _ ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+			return &ecr.DescribeRepositoriesOutput{
+				Repositories: []ecrtypes.Repository{
+					{
+						RepositoryArn:              aws.String("arn:aws:ecr:us-east-1:123456789012:repository/api"),
+						RepositoryName:             aws.String("api"),
+						RepositoryUri:              aws.String("123456789012.dkr.ecr.us-east-1.amazonaws.com/api"),
+						ImageTagMutability:         ecrtypes.ImageTagMutabilityImmutable,
+						ImageScanningConfiguration: &ecrtypes.ImageScanningConfiguration{ScanOnPush: true},
+					},
+					{RepositoryArn: aws.String("arn:aws:ecr:us-east-1:123456789012:repository/empty"), RepositoryName: aws.String("empty")},
+					{RepositoryArn: aws.String("arn:aws:ecr:us-east-1:123456789012:repository/legacy"), RepositoryName: aws.String("legacy")},
+				},
+			}, nil
+		},
+		DescribeImagesFunc: func(_ context.Context, in *ecr.DescribeImagesInput, _ ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error) {
+			switch aws.ToString(in.RepositoryName) {
+			case "api":
+				if in.NextToken == nil {
+					return &ecr.DescribeImagesOutput{
+						ImageDetails: []ecrtypes.ImageDetail{{ImagePushedAt: aws.Time(now.AddDate(0, -6, 0))}},
+						NextToken:    aws.String("page2"),
+					}, nil
+				}
+				return &ecr.DescribeImagesOutput{
+					ImageDetails: []ecrtypes.ImageDetail{{ImagePushedAt: aws.Time(now.AddDate(0, 0, -2))}},
+				}, nil
+			case "legacy":
+				return &ecr.DescribeImagesOutput{
+					ImageDetails: []ecrtypes.ImageDetail{{ImagePushedAt: aws.Time(now.AddDate(-1, 0, 0))}},
+				}, nil
+			}
+			return &ecr.DescribeImagesOutput{}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", now: func() time.Time { return now }, ecrClient: func() ECRAPI { return mock }}
+	resources, err := p.scanECR(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	api := resources[0]
+	assert.Equal(t, "ecr", api.Type)
+	assert.Equal(t, "api", api.Name)
+	assert.Equal(t, "IMMUTABLE", api.Attrs["tag_mutability"])
+	assert.Equal(t, "true", api.Attrs["scan_on_push"])
+	assert.Equal(t, "2", api.Attrs["image_count"])
+	assert.Equal(t, "2024-05-30", api.Attrs["last_pushed"])
+	assert.Equal(t, "false", api.Attrs["idle"])
+
+	empty := resources[1]
+	assert.Equal(t, "0", empty.Attrs["image_count"])
+	assert.NotContains(t, empty.Attrs, "last_pushed")
+	assert.Equal(t, "true", empty.Attrs["idle"])
+
+	legacy := resources[2]
+	assert.Equal(t, "1", legacy.Attrs["image_count"])
+	assert.Equal(t, "true", legacy.Attrs["idle"])
+}
+
+func TestScanECR_DescribeImagesError(t *testing.T) {
+	mock := &mockECRClient{
+		DescribeRepositoriesFunc: func(_ context.Context, _ *ecr.DescribeRepositoriesInput, _ ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+			return &ecr.DescribeRepositoriesOutput{
+				Repositories: []ecrtypes.Repository{{RepositoryArn: aws.String("arn:aws:ecr:us-east-1:123456789012:repository/api"), RepositoryName: aws.String("api")}},
+			}, nil
+		},
+		DescribeImagesFunc: func(_ context.Context, _ *ecr.DescribeImagesInput, _ ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", ecrClient: func() ECRAPI { return mock }}
+	resources, err := p.scanECR(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.NotContains(t, resources[0].Attrs, "image_count")
+	assert.NotContains(t, resources[0].Attrs, "idle")
+}
+
+func TestScanECR_Error(t *testing.T) {
+	mock := &mockECRClient{
+		DescribeRepositoriesFunc: func(_ context.Context, _ *ecr.DescribeRepositoriesInput, _ ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+			return nil, errors.New("throttled")
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", ecrClient: func() ECRAPI { return mock }}
+	_, err := p.scanECR(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "describe repositories")
+}
+
+// ══════════════════════════════════════════════════════════════════════════════
+// Snapshot Tests
+// ══════════════════════════════════════════════════════════════════════════════
+
+func TestMarkUnmanagedSnapshots(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	snap := func(id, source string, age time.Duration, automated bool) resource.Resource {
+		return resource.Resource{ID: id, CreatedAt: now.Add(-age), Attrs: map[string]string{
+			"source":    source,
+			"automated": strconv.FormatBool(automated),
+		}}
+	}
+	day := 24 * time.Hour
+
+	resources := []resource.Resource{
+		// vol-a: nightly script, plus a forgotten manual snapshot
+		snap("a-1", "vol-a", 3*day, false),
+		snap("a-2", "vol-a", 2*day, false),
+		snap("a-3", "vol-a", day, false),
+		snap("a-old", "vol-a", 200*day, false),
+		// vol-b: one old manual snapshot
+		snap("b-old", "vol-b", 90*day, false),
+		// vol-c: old but created by a lifecycle policy
+		snap("c-dlm", "vol-c", 90*day, true),
+		// vol-d: manual but recent
+		snap("d-new", "vol-d", 10*day, false),
+		// vol-e: weekly series that stopped a year ago
+		snap("e-1", "vol-e", 400*day, false),
+		snap("e-2", "vol-e", 407*day, false),
+		snap("e-3", "vol-e", 414*day, false),
+	}
+
+	markUnmanagedSnapshots(resources, now, defaultSnapshotUnmanagedAge)
+
+	unmanaged := make(map[string]string)
+	for _, r := range resources {
+		unmanaged[r.ID] = r.Attrs["unmanaged"]
+	}
+	assert.Equal(t, map[string]string{
+		"a-1": "false", "a-2": "false", "a-3": "false", "a-old": "true",
+		"b-old": "true",
+		"c-dlm": "false",
+		"d-new": "false",
+		"e-1":   "true", "e-2": "true", "e-3": "true",
+	}, unmanaged)
+}
+
+func TestScanEBSSnapshots(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	mock := &mockEC2Client{
+		describeSnapshotsFunc: func(_ context.Context, in *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+			assert.Equal(t, []string{"self"}, in.OwnerIds)
+			return &ec2.DescribeSnapshotsOutput{
+				Snapshots: []ec2types.Snapshot{
+					{
+						SnapshotId: aws.String("snap-dlm"), VolumeId: aws.String("vol-1"), VolumeSize: aws.Int32(100),
+						State: ec2types.SnapshotStateCompleted, StartTime: aws.Time(now.AddDate(0, -3, 0)),
+						Tags: []ec2types.Tag{{Key: aws.String("aws:dlm:lifecycle-policy-id"), Value: aws.String("policy-123")}},
+					},
+					{
+						SnapshotId: aws.String("snap-manual"), VolumeId: aws.String("vol-1"), VolumeSize: aws.Int32(100),
+						State: ec2types.SnapshotStateCompleted, StartTime: aws.Time(now.AddDate(0, -6, 0)), Encrypted: aws.Bool(true),
+						Tags: []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String("before-upgrade")}},
+					},
+				},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", now: func() time.Time { return now }, ec2Client: func() EC2API { return mock }}
+	resources, err := p.scanEBSSnapshots(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	assert.Equal(t, "ebs_snapshot", resources[0].Type)
+	assert.Equal(t, "completed", resources[0].Status)
+	assert.Equal(t, "vol-1", resources[0].Attrs["source"])
+	assert.Equal(t, "true", resources[0].Attrs["automated"])
+	assert.Equal(t, "false", resources[0].Attrs["unmanaged"])
+
+	assert.Equal(t, "before-upgrade", resources[1].Name)
+	assert.Equal(t, "100", resources[1].Attrs["size_gb"])
+	assert.Equal(t, "true", resources[1].Attrs["encrypted"])
+	assert.Equal(t, "false", resources[1].Attrs["automated"])
+	assert.Equal(t, "true", resources[1].Attrs["unmanaged"])
+}
+
+func TestScanEBSSnapshots_EncryptionAndPagination(t *testing.T) {
+	started := time.Date(2024, 5, 20, 8, 30, 0, 0, time.UTC)
+	var tokens []*string
+	mock := &mockEC2Client{
+		describeSnapshotsFunc: func(_ context.Context, in *ec2.DescribeSnapshotsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+			tokens = append(tokens, in.NextToken)
+			if in.NextToken == nil {
+				return &ec2.DescribeSnapshotsOutput{
+					Snapshots: []ec2types.Snapshot{{
+						SnapshotId: aws.String("snap-enc"), VolumeId: aws.String("vol-1"), VolumeSize: aws.Int32(8),
+						Encrypted: aws.Bool(true), StartTime: aws.Time(started), Description: aws.String("nightly"),
+					}},
+					NextToken: aws.String("page-2"),
+				}, nil
+			}
+			return &ec2.DescribeSnapshotsOutput{
+				Snapshots: []ec2types.Snapshot{{
+					SnapshotId: aws.String("snap-plain"), VolumeId: aws.String("vol-2"), VolumeSize: aws.Int32(20),
+					Encrypted: aws.Bool(false), StartTime: aws.Time(started),
+				}},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", ec2Client: func() EC2API { return mock }}
+	resources, err := p.scanEBSSnapshots(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "page-2", aws.ToString(tokens[1]))
+	require.Len(t, resources, 2)
+
+	enc := resources[0]
+	assert.Equal(t, "vol-1", enc.Attrs["volume_id"])
+	assert.Equal(t, "8", enc.Attrs["size_gb"])
+	assert.Equal(t, "true", enc.Attrs["encrypted"])
+	assert.Equal(t, "2024-05-20", enc.Attrs["started"])
+	assert.Equal(t, "nightly", enc.Attrs["description"])
+
+	plain := resources[1]
+	assert.Equal(t, "vol-2", plain.Attrs["volume_id"])
+	assert.Equal(t, "false", plain.Attrs["encrypted"])
+	assert.NotContains(t, plain.Attrs, "description")
+}
+
+func TestScanAMIs(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	mock := &mockEC2Client{
+		describeImagesFunc: func(_ context.Context, in *ec2.DescribeImagesInput, _ ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+			assert.Equal(t, []string{"self"}, in.Owners)
+			return &ec2.DescribeImagesOutput{
+				Images: []ec2types.Image{{
+					ImageId:        aws.String("ami-123"),
+					Name:           aws.String("golden-2024-01"),
+					State:          ec2types.ImageStateAvailable,
+					Architecture:   ec2types.ArchitectureValuesX8664,
+					RootDeviceType: ec2types.DeviceTypeEbs,
+					Public:         aws.Bool(false),
+					CreationDate:   aws.String("2024-01-02T03:04:05.000Z"),
+					BlockDeviceMappings: []ec2types.BlockDeviceMapping{
+						{DeviceName: aws.String("/dev/xvda"), Ebs: &ec2types.EbsBlockDevice{SnapshotId: aws.String("snap-1")}},
+						{DeviceName: aws.String("/dev/xvdb"), Ebs: &ec2types.EbsBlockDevice{SnapshotId: aws.String("snap-2")}},
+					},
+				}},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", now: func() time.Time { return now }, ec2Client: func() EC2API { return mock }}
+	resources, err := p.scanAMIs(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	r := resources[0]
+	assert.Equal(t, "ami-123", r.ID)
+	assert.Equal(t, "ami", r.Type)
+	assert.Equal(t, "available", r.Status)
+	assert.Equal(t, "golden-2024-01", r.Name)
+	assert.Equal(t, "x86_64", r.Attrs["architecture"])
+	assert.Equal(t, "ebs", r.Attrs["root_device_type"])
+	assert.Equal(t, "false", r.Attrs["public"])
+	assert.Equal(t, "2", r.Attrs["snapshot_count"])
+	assert.Equal(t, "150", r.Attrs["age_days"])
+	assert.Equal(t, "true", r.Attrs["obsolete"])
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), r.CreatedAt)
+}
+
+func TestScanRDSSnapshots(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	mock := &mockRDSClient{
+		DescribeDBSnapshotsFunc: func(_ context.Context, _ *rds.DescribeDBSnapshotsInput, _ ...func(*rds.Options)) (*rds.DescribeDBSnapshotsOutput, error) {
+			return &rds.DescribeDBSnapshotsOutput{
+				DBSnapshots: []rdstypes.DBSnapshot{
+					{
+						DBSnapshotIdentifier: aws.String("rds:orders-2024-05-31"), DBInstanceIdentifier: aws.String("orders"),
+						SnapshotType: aws.String("automated"), Status: aws.String("available"),
+						SnapshotCreateTime: aws.Time(now.AddDate(0, 0, -1)), Engine: aws.String("postgres"), AllocatedStorage: aws.Int32(50),
+					},
+					{
+						DBSnapshotIdentifier: aws.String("orders-pre-migration"), DBInstanceIdentifier: aws.String("orders"),
+						SnapshotType: aws.String("manual"), Status: aws.String("available"),
+						SnapshotCreateTime: aws.Time(now.AddDate(-1, 0, 0)),
+					},
+				},
+			}, nil
+		},
+	}
+
+	p := &Plugin{region: "us-east-1", accountID: "123456789012", now: func() time.Time { return now }, rdsClient: func() RDSAPI { return mock }}
+	resources, err := p.scanRDSSnapshots(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	assert.Equal(t, "rds_snapshot", resources[0].Type)
+	assert.Equal(t, "orders", resources[0].Attrs["source"])
+	assert.Equal(t, "automated", resources[0].Attrs["snapshot_type"])
+	assert.Equal(t, "50", resources[0].Attrs["size_gb"])
+	assert.Equal(t, "false", resources[0].Attrs["unmanaged"])
+
+	assert.Equal(t, "manual", resources[1].Attrs["snapshot_type"])
+	assert.Equal(t, "true", resources[1].Attrs["unmanaged"])
+}
@@ -18,6 +18,42 @@ type Plugin interface {
 	Scan(ctx context.Context) ([]resource.Resource, error)
 }
 
+// StreamingPlugin is implemented by plugins that can hand over resources as
+// each part of a scan finishes, so emitters need not wait for the whole scan.
+type StreamingPlugin interface {
+	Plugin
+
+	// ScanStream scans like Scan but hands each batch of resources to fn as
+	// soon as it is scanned instead of collecting them, and returns how many
+	// there were. Calls to fn are serialized. On error, batches already
+	// passed to fn belong to the failed scan.
+	ScanStream(ctx context.Context, fn func([]resource.Resource)) (int, error)
+}
+
+// RegionalPlugin is implemented by plugins that scan a single region.
+type RegionalPlugin interface {
+	Plugin
+
+	// Region returns the region scanned, e.g. "us-east-1".
+	Region() string
+}
+
+// RegionOf returns the region scanned by p, or "" if it is not regional.
+func RegionOf(p Plugin) string {
+	if rp, ok := p.(RegionalPlugin); ok {
+		return rp.Region()
+	}
+	return ""
+}
+
+// CostProvider is implemented by plugins that can report what the provider
+// actually billed for each resource.
+type CostProvider interface {
+	// ResourceCosts returns the monthly cost in USD keyed by resource ID.
+	// Resources without billing data are absent from the map.
+	ResourceCosts(ctx context.Context) (map[string]float64, error)
+}
+
 // Registry holds registered plugins.
 var registry = make(map[string]Plugin)
 
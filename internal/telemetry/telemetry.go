@@ -8,10 +8,13 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/prometheus"
+	otellog "go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -21,12 +24,15 @@ import (
 	"github.com/yairfalse/elava/internal/config"
 )
 
-// Provider wraps OTEL tracer and meter providers.
+// Provider wraps OTEL tracer, meter and logger providers.
 type Provider struct {
 	tracerProvider *sdktrace.TracerProvider
 	meterProvider  *sdkmetric.MeterProvider
+	loggerProvider *sdklog.LoggerProvider
 	tracer         trace.Tracer
 	meter          metric.Meter
+	logger         otellog.Logger
+	namespace      string // metric name prefix
 
 	// Metrics
 	scanDuration  metric.Float64Histogram
@@ -36,16 +42,19 @@ type Provider struct {
 
 // NewProvider creates a new telemetry provider.
 func NewProvider(ctx context.Context, cfg config.OTELConfig) (*Provider, error) {
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceName(cfg.ServiceName),
-		),
-	)
+	attrs := []attribute.KeyValue{semconv.ServiceName(cfg.ServiceName)}
+	for k, v := range cfg.Metrics.ConstLabels {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	res, err := resource.New(ctx, resource.WithAttributes(attrs...))
 	if err != nil {
 		return nil, fmt.Errorf("create resource: %w", err)
 	}
 
-	p := &Provider{}
+	p := &Provider{namespace: cfg.Metrics.Namespace}
+	if p.namespace == "" {
+		p.namespace = config.DefaultMetricsNamespace
+	}
 
 	if err := p.setupTracing(ctx, cfg, res); err != nil {
 		return nil, err
@@ -58,6 +67,11 @@ func NewProvider(ctx context.Context, cfg config.OTELConfig) (*Provider, error)
 		return nil, err
 	}
 
+	if err := p.setupLogs(ctx, cfg, res); err != nil {
+		_ = p.Shutdown(ctx)
+		return nil, err
+	}
+
 	if err := p.initMetrics(); err != nil {
 		_ = p.Shutdown(ctx)
 		_ = p.Shutdown(ctx)
@@ -93,8 +107,9 @@ func (p *Provider) setupMetrics(ctx context.Context, cfg config.OTELConfig, res
 		sdkmetric.WithResource(res),
 	}
 
-	// Always add Prometheus exporter for /metrics endpoint
-	promExporter, err := prometheus.New()
+	// Always add Prometheus exporter for /metrics endpoint.
+	// Configured constant labels are copied from the resource onto every series.
+	promExporter, err := prometheus.New(prometheus.WithResourceAsConstantLabels(constLabelFilter(cfg.Metrics.ConstLabels)))
 	if err != nil {
 		return fmt.Errorf("create prometheus exporter: %w", err)
 	}
@@ -116,6 +131,33 @@ func (p *Provider) setupMetrics(ctx context.Context, cfg config.OTELConfig, res
 	return nil
 }
 
+func (p *Provider) setupLogs(ctx context.Context, cfg config.OTELConfig, res *resource.Resource) error {
+	opts := []sdklog.LoggerProviderOption{
+		sdklog.WithResource(res),
+	}
+
+	if cfg.Logs.Enabled && cfg.Endpoint != "" {
+		exp, err := createLogExporter(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("create log exporter: %w", err)
+		}
+		opts = append(opts, sdklog.WithProcessor(sdklog.NewBatchProcessor(exp)))
+	}
+
+	p.loggerProvider = sdklog.NewLoggerProvider(opts...)
+	p.logger = p.loggerProvider.Logger("elava")
+
+	return nil
+}
+
+// constLabelFilter selects the resource attributes configured as constant labels.
+func constLabelFilter(labels map[string]string) attribute.Filter {
+	return func(kv attribute.KeyValue) bool {
+		_, ok := labels[string(kv.Key)]
+		return ok
+	}
+}
+
 func createTraceExporter(ctx context.Context, cfg config.OTELConfig) (sdktrace.SpanExporter, error) {
 	opts := []otlptracegrpc.Option{
 		otlptracegrpc.WithEndpoint(cfg.Endpoint),
@@ -136,11 +178,21 @@ func createMetricExporter(ctx context.Context, cfg config.OTELConfig) (sdkmetric
 	return otlpmetricgrpc.New(ctx, opts...)
 }
 
+func createLogExporter(ctx context.Context, cfg config.OTELConfig) (sdklog.Exporter, error) {
+	opts := []otlploggrpc.Option{
+		otlploggrpc.WithEndpoint(cfg.Endpoint),
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+	return otlploggrpc.New(ctx, opts...)
+}
+
 func (p *Provider) initMetrics() error {
 	var err error
 
 	p.scanDuration, err = p.meter.Float64Histogram(
-		"elava_scan_duration_seconds",
+		p.metricName("scan_duration_seconds"),
 		metric.WithDescription("Duration of resource scans"),
 		metric.WithUnit("s"),
 	)
@@ -149,7 +201,7 @@ func (p *Provider) initMetrics() error {
 	}
 
 	p.resourceCount, err = p.meter.Int64Counter(
-		"elava_resources_scanned_total",
+		p.metricName("resources_scanned_total"),
 		metric.WithDescription("Total resources scanned"),
 	)
 	if err != nil {
@@ -157,7 +209,7 @@ func (p *Provider) initMetrics() error {
 	}
 
 	p.scanErrors, err = p.meter.Int64Counter(
-		"elava_scan_errors_total",
+		p.metricName("scan_errors_total"),
 		metric.WithDescription("Total scan errors"),
 	)
 	if err != nil {
@@ -167,6 +219,11 @@ func (p *Provider) initMetrics() error {
 	return nil
 }
 
+// metricName prefixes name with the configured namespace.
+func (p *Provider) metricName(name string) string {
+	return p.namespace + "_" + name
+}
+
 // Tracer returns the tracer.
 func (p *Provider) Tracer() trace.Tracer {
 	return p.tracer
@@ -177,6 +234,16 @@ func (p *Provider) Meter() metric.Meter {
 	return p.meter
 }
 
+// Logger returns the logger. Records are dropped unless log export is enabled.
+func (p *Provider) Logger() otellog.Logger {
+	return p.logger
+}
+
+// RegisterSpanProcessor adds a span processor, e.g. an in-memory recorder in tests.
+func (p *Provider) RegisterSpanProcessor(sp sdktrace.SpanProcessor) {
+	p.tracerProvider.RegisterSpanProcessor(sp)
+}
+
 // StartSpan starts a new span.
 func (p *Provider) StartSpan(ctx context.Context, name string) (context.Context, trace.Span) {
 	return p.tracer.Start(ctx, name)
@@ -200,12 +267,14 @@ func (p *Provider) RecordResourceCount(ctx context.Context, provider, region, sc
 	))
 }
 
-// RecordError records a scan error.
-func (p *Provider) RecordError(ctx context.Context, provider, region, scanner string) {
+// RecordError records a scan error. reason distinguishes failures, e.g.
+// "timeout" from "error".
+func (p *Provider) RecordError(ctx context.Context, provider, region, scanner, reason string) {
 	p.scanErrors.Add(ctx, 1, metric.WithAttributes(
 		attribute.String("provider", provider),
 		attribute.String("region", region),
 		attribute.String("scanner", scanner),
+		attribute.String("reason", reason),
 	))
 }
 
@@ -221,5 +290,10 @@ func (p *Provider) Shutdown(ctx context.Context) error {
 			return fmt.Errorf("shutdown meter: %w", err)
 		}
 	}
+	if p.loggerProvider != nil {
+		if err := p.loggerProvider.Shutdown(ctx); err != nil {
+			return fmt.Errorf("shutdown logger: %w", err)
+		}
+	}
 	return nil
 }
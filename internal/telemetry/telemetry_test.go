@@ -2,9 +2,13 @@ package telemetry
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -24,6 +28,7 @@ func TestNewProvider_Disabled(t *testing.T) {
 
 	assert.NotNil(t, p.Tracer())
 	assert.NotNil(t, p.Meter())
+	assert.NotNil(t, p.Logger())
 
 	err = p.Shutdown(context.Background())
 	require.NoError(t, err)
@@ -112,7 +117,38 @@ func TestProvider_RecordError(t *testing.T) {
 	require.NoError(t, err)
 
 	// Should not panic
-	p.RecordError(context.Background(), "aws", "us-east-1", "ec2")
+	p.RecordError(context.Background(), "aws", "us-east-1", "ec2", "error")
 
 	_ = p.Shutdown(context.Background())
 }
+
+func TestNewProvider_NamespaceAndConstLabels(t *testing.T) {
+	cfg := config.OTELConfig{
+		ServiceName: "test-elava",
+		Metrics: config.MetricsConfig{
+			Namespace:   "team_a",
+			ConstLabels: map[string]string{"elava_instance": "scanner-1", "account": "123456789012"},
+		},
+	}
+
+	p, err := NewProvider(context.Background(), cfg)
+	require.NoError(t, err)
+	defer func() { _ = p.Shutdown(context.Background()) }()
+
+	p.RecordResourceCount(context.Background(), "aws", "us-east-1", "ec2", 3)
+
+	rec := httptest.NewRecorder()
+	promhttp.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	var series string
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "team_a_resources_scanned_total{") {
+			series = line
+		}
+	}
+	require.NotEmpty(t, series, "prefixed series not found")
+	assert.Contains(t, series, `elava_instance="scanner-1"`)
+	assert.Contains(t, series, `account="123456789012"`)
+	assert.NotContains(t, series, "service_name")
+}
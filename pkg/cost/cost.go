@@ -0,0 +1,170 @@
+// Package cost estimates the monthly cost of resources from their attributes
+// and the savings from removing those flagged as waste.
+//
+// Estimates use us-east-1 on-demand list prices and ignore data transfer,
+// requests and discounts. They are a rough signal for ranking waste, not a
+// bill; billed cost comes from Cost Explorer when enabled.
+package cost
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// hoursPerMonth is the average number of hours AWS bills in a month.
+const hoursPerMonth = 730
+
+// Hourly prices of fixed-rate resources.
+const (
+	natGatewayHourly   = 0.045
+	publicIPv4Hourly   = 0.005 // charged for every EIP since February 2024
+	loadBalancerHourly = 0.0225
+)
+
+// Storage prices per GB-month.
+const (
+	snapshotGBMonth      = 0.05
+	rdsStorageGBMonth    = 0.115
+	defaultVolumeGBMonth = 0.10
+)
+
+// volumeGBMonth is the EBS price per GB-month by volume type.
+var volumeGBMonth = map[string]float64{
+	"gp2":      0.10,
+	"gp3":      0.08,
+	"io1":      0.125,
+	"io2":      0.125,
+	"st1":      0.045,
+	"sc1":      0.015,
+	"standard": 0.05,
+}
+
+// ec2LargeHourly is the hourly Linux price of the "large" size of each EC2
+// instance family. Other sizes scale from it, see sizeFactor.
+var ec2LargeHourly = map[string]float64{
+	"t2":  0.0928,
+	"t3":  0.0832,
+	"t3a": 0.0752,
+	"t4g": 0.0672,
+	"m5":  0.096,
+	"m5a": 0.086,
+	"m6i": 0.096,
+	"m6a": 0.0864,
+	"m6g": 0.077,
+	"m7i": 0.1008,
+	"m7g": 0.0816,
+	"c5":  0.085,
+	"c6i": 0.085,
+	"c6g": 0.068,
+	"c7g": 0.0725,
+	"r5":  0.126,
+	"r6i": 0.126,
+	"r6g": 0.1008,
+	"r7g": 0.1071,
+}
+
+// rdsLargeHourly is the hourly single-AZ price of the "large" size of each
+// RDS instance family (MySQL/PostgreSQL).
+var rdsLargeHourly = map[string]float64{
+	"t3":  0.136,
+	"t4g": 0.129,
+	"m5":  0.171,
+	"m6i": 0.171,
+	"m6g": 0.152,
+	"m7g": 0.168,
+	"r5":  0.25,
+	"r6i": 0.25,
+	"r6g": 0.215,
+	"r7g": 0.239,
+}
+
+// smallSizes are the sizes below "large" as a fraction of it.
+var smallSizes = map[string]float64{
+	"nano":   1.0 / 16,
+	"micro":  1.0 / 8,
+	"small":  1.0 / 4,
+	"medium": 1.0 / 2,
+	"large":  1,
+	"xlarge": 2,
+}
+
+// Estimate returns a rough monthly cost in USD for r, or 0 when the type or
+// its size is unknown. Stopped instances only count their storage.
+func Estimate(r resource.Resource) float64 {
+	switch r.Type {
+	case "ec2":
+		if r.Status != "running" {
+			return 0
+		}
+		return hourlyByClass(ec2LargeHourly, r.Attrs["instance_type"]) * hoursPerMonth
+	case "ebs":
+		price, ok := volumeGBMonth[r.Attrs["type"]]
+		if !ok {
+			price = defaultVolumeGBMonth
+		}
+		return attrFloat(r, "size_gb") * price
+	case "ebs_snapshot":
+		return attrFloat(r, "size_gb") * snapshotGBMonth
+	case "rds":
+		storage := attrFloat(r, "storage_gb") * rdsStorageGBMonth
+		if r.Status == "stopped" {
+			return storage
+		}
+		compute := hourlyByClass(rdsLargeHourly, strings.TrimPrefix(r.Attrs["instance_class"], "db.")) * hoursPerMonth
+		if compute == 0 {
+			return 0
+		}
+		if r.Attrs["multi_az"] == "true" {
+			compute, storage = compute*2, storage*2
+		}
+		return compute + storage
+	case "nat_gateway":
+		return natGatewayHourly * hoursPerMonth
+	case "eip":
+		return publicIPv4Hourly * hoursPerMonth
+	case "elb":
+		return loadBalancerHourly * hoursPerMonth
+	}
+	return 0
+}
+
+// hourlyByClass prices an instance class such as "m5.2xlarge" from the
+// family's large price, or returns 0 for an unknown family or size.
+func hourlyByClass(largeHourly map[string]float64, class string) float64 {
+	family, size, ok := strings.Cut(class, ".")
+	if !ok {
+		return 0
+	}
+	base, ok := largeHourly[family]
+	if !ok {
+		return 0
+	}
+	return base * sizeFactor(size)
+}
+
+// sizeFactor returns the size relative to "large": an "Nxlarge" is 2N
+// larges. Unknown sizes, including "metal", return 0.
+func sizeFactor(size string) float64 {
+	if f, ok := smallSizes[size]; ok {
+		return f
+	}
+	n, ok := strings.CutSuffix(size, "xlarge")
+	if !ok {
+		return 0
+	}
+	mult, err := strconv.Atoi(n)
+	if err != nil || mult <= 0 {
+		return 0
+	}
+	return float64(2 * mult)
+}
+
+func attrFloat(r resource.Resource, key string) float64 {
+	v, err := strconv.ParseFloat(r.Attrs[key], 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,52 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func res(typ, status string, attrs map[string]string) resource.Resource {
+	return resource.Resource{Type: typ, Status: status, Attrs: attrs}
+}
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		name string
+		r    resource.Resource
+		want float64
+	}{
+		{"ec2 large", res("ec2", "running", map[string]string{"instance_type": "m5.large"}), 70.08},
+		{"ec2 2xlarge", res("ec2", "running", map[string]string{"instance_type": "m5.2xlarge"}), 280.32},
+		{"ec2 micro", res("ec2", "running", map[string]string{"instance_type": "t3.micro"}), 7.592},
+		{"ec2 stopped", res("ec2", "stopped", map[string]string{"instance_type": "m5.large"}), 0},
+		{"ec2 unknown family", res("ec2", "running", map[string]string{"instance_type": "x9z.large"}), 0},
+		{"ec2 metal", res("ec2", "running", map[string]string{"instance_type": "m5.metal"}), 0},
+		{"ebs gp3", res("ebs", "available", map[string]string{"type": "gp3", "size_gb": "500"}), 40},
+		{"ebs gp2", res("ebs", "in-use", map[string]string{"type": "gp2", "size_gb": "100"}), 10},
+		{"ebs unknown type", res("ebs", "in-use", map[string]string{"type": "new", "size_gb": "100"}), 10},
+		{"rds single az", res("rds", "available", map[string]string{"instance_class": "db.r5.large", "storage_gb": "100"}), 182.5 + 11.5},
+		{"rds multi az", res("rds", "available", map[string]string{"instance_class": "db.r5.large", "storage_gb": "100", "multi_az": "true"}), 2 * (182.5 + 11.5)},
+		{"rds stopped", res("rds", "stopped", map[string]string{"instance_class": "db.r5.large", "storage_gb": "100"}), 11.5},
+		{"rds unknown class", res("rds", "available", map[string]string{"instance_class": "db.x1e.large", "storage_gb": "100"}), 0},
+		{"nat gateway", res("nat_gateway", "available", nil), 32.85},
+		{"eip", res("eip", "unattached", nil), 3.65},
+		{"snapshot", res("ebs_snapshot", "completed", map[string]string{"size_gb": "200"}), 10},
+		{"unknown type", res("lambda", "active", map[string]string{"size_gb": "100"}), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, Estimate(tt.r), 0.001)
+		})
+	}
+}
+
+func TestSizeFactor(t *testing.T) {
+	assert.Equal(t, 0.5, sizeFactor("medium"))
+	assert.Equal(t, 2.0, sizeFactor("xlarge"))
+	assert.Equal(t, 48.0, sizeFactor("24xlarge"))
+	assert.Equal(t, 0.0, sizeFactor("metal"))
+	assert.Equal(t, 0.0, sizeFactor("0xlarge"))
+}
@@ -0,0 +1,56 @@
+package cost
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// Group is the resources sharing a key, e.g. an owner, and their cost.
+type Group struct {
+	Key         string         `json:"key"` // "" for resources without one
+	Resources   int            `json:"resources"`
+	Types       map[string]int `json:"types"`        // resource count by type
+	MonthlyCost float64        `json:"monthly_cost"` // billed cost, else estimate; unknown counts as 0
+
+	CostByType map[string]float64 `json:"cost_by_type"` // monthly cost by type, for types with a known cost
+}
+
+// GroupBy groups resources by key(r), counting each resource ID once.
+// Groups are sorted by monthly cost descending, then by key.
+func GroupBy(resources []resource.Resource, key func(resource.Resource) string) []Group {
+	index := make(map[string]int)
+	seen := make(map[string]bool)
+	var groups []Group
+
+	for _, r := range resources {
+		if seen[r.ID] {
+			continue
+		}
+		seen[r.ID] = true
+
+		k := key(r)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, Group{Key: k, Types: make(map[string]int), CostByType: make(map[string]float64)})
+		}
+		g := &groups[i]
+		g.Resources++
+		g.Types[r.Type]++
+		if monthly, ok := MonthlyCost(r); ok {
+			g.MonthlyCost += monthly
+			g.CostByType[r.Type] += monthly
+		}
+	}
+
+	slices.SortFunc(groups, func(a, b Group) int {
+		if c := cmp.Compare(b.MonthlyCost, a.MonthlyCost); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return groups
+}
@@ -0,0 +1,50 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func TestGroupBy(t *testing.T) {
+	resources := []resource.Resource{
+		{ID: "i-1", Type: "ec2", Labels: map[string]string{"owner": "alice"}, Attrs: map[string]string{"monthly_cost_estimate": "70.08"}},
+		{ID: "vol-1", Type: "ebs", Labels: map[string]string{"owner": "alice"}, Attrs: map[string]string{"monthly_cost": "8.00"}},
+		{ID: "i-2", Type: "ec2", Labels: map[string]string{"owner": "bob"}, Attrs: map[string]string{"monthly_cost_estimate": "140.16"}},
+		{ID: "fn-1", Type: "lambda"},
+		// Same ID seen again in another batch
+		{ID: "i-1", Type: "ec2", Labels: map[string]string{"owner": "alice"}, Attrs: map[string]string{"monthly_cost_estimate": "70.08"}},
+	}
+
+	groups := GroupBy(resources, resource.Resource.Owner)
+
+	require.Len(t, groups, 3)
+	assert.Equal(t, "bob", groups[0].Key)
+	assert.Equal(t, Group{
+		Key: "alice", Resources: 2, Types: map[string]int{"ec2": 1, "ebs": 1}, MonthlyCost: 78.08,
+		CostByType: map[string]float64{"ec2": 70.08, "ebs": 8.00},
+	}, groups[1])
+	assert.Equal(t, Group{Key: "", Resources: 1, Types: map[string]int{"lambda": 1}, CostByType: map[string]float64{}}, groups[2])
+}
+
+func TestGroupBy_CostByEnvironment(t *testing.T) {
+	resources := []resource.Resource{
+		{ID: "i-1", Type: "ec2", Labels: map[string]string{"env": "prod"}, Attrs: map[string]string{"monthly_cost_estimate": "100.00"}},
+		{ID: "i-2", Type: "ec2", Labels: map[string]string{"env": "prod"}, Attrs: map[string]string{"monthly_cost_estimate": "50.00"}},
+		{ID: "db-1", Type: "rds", Labels: map[string]string{"env": "prod"}, Attrs: map[string]string{"monthly_cost_estimate": "200.00"}},
+		{ID: "i-3", Type: "ec2", Labels: map[string]string{"env": "dev"}, Attrs: map[string]string{"monthly_cost_estimate": "25.50"}},
+	}
+
+	groups := GroupBy(resources, func(r resource.Resource) string { return r.Label("env") })
+
+	require.Len(t, groups, 2)
+	assert.Equal(t, "prod", groups[0].Key)
+	assert.InDelta(t, 350.00, groups[0].MonthlyCost, 0.001)
+	assert.Equal(t, map[string]float64{"ec2": 150.00, "rds": 200.00}, groups[0].CostByType)
+	assert.Equal(t, "dev", groups[1].Key)
+	assert.InDelta(t, 25.50, groups[1].MonthlyCost, 0.001)
+	assert.Equal(t, map[string]float64{"ec2": 25.50}, groups[1].CostByType)
+}
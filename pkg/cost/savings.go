@@ -0,0 +1,166 @@
+package cost
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+// wasteSignals are attrs set to "true" by scanners when a resource looks
+// unused, in the order a resource matching several is categorised.
+var wasteSignals = []string{"orphaned", "idle", "redundant", "overdue", "unmanaged", "obsolete", "iops_overprovisioned"}
+
+// provisionedIOPSMonth is the io1/io2 price per provisioned IOPS-month.
+const provisionedIOPSMonth = 0.065
+
+// EstimatedMonthlySavings returns the monthly USD cost of resources flagged
+// as waste, and the same total broken down by waste category. Each resource
+// ID counts once: a resource matching several categories is attributed to
+// the first of its waste rule ("waste" attr), orphaned, idle, redundant,
+// overdue, unmanaged, obsolete, iops_overprovisioned, or being unattached
+// (an "unattached" status or an EBS volume with attached=false), so the
+// breakdown sums to the total.
+//
+// Billed monthly_cost is preferred over monthly_cost_estimate; flagged
+// resources with neither are skipped. Over-provisioned IOPS save only the
+// price of the excess IOPS, not the volume.
+func EstimatedMonthlySavings(resources []resource.Resource) (float64, map[string]float64) {
+	var total float64
+	byCategory := make(map[string]float64)
+	seen := make(map[string]bool)
+
+	for _, r := range resources {
+		category := wasteCategory(r)
+		if category == "" || seen[r.ID] {
+			continue
+		}
+		monthly, ok := savings(r, category)
+		if !ok {
+			continue
+		}
+		seen[r.ID] = true
+		total += monthly
+		byCategory[category] += monthly
+	}
+	return total, byCategory
+}
+
+// wasteReasons explain the built-in waste categories.
+var wasteReasons = map[string]string{
+	"orphaned":   "not attached to or used by anything",
+	"idle":       "no activity in the idle window",
+	"redundant":  "duplicates another resource",
+	"overdue":    "outlived its elava:ttl tag",
+	"unattached": "not attached",
+	"unmanaged":  "manual snapshot outside any backup schedule",
+	"obsolete":   "self-owned image past its retention age",
+
+	"iops_overprovisioned": "provisions more IOPS than its size needs",
+}
+
+// Recommendation is a resource flagged as waste and what removing it saves.
+type Recommendation struct {
+	ID             string  `json:"id"`
+	Type           string  `json:"type"`
+	Name           string  `json:"name"`
+	Region         string  `json:"region"`
+	Category       string  `json:"category"`
+	Reason         string  `json:"reason"`
+	MonthlySavings float64 `json:"monthly_savings"`
+	CostKnown      bool    `json:"cost_known"` // false when neither monthly_cost nor an estimate is set
+	Staleness      int     `json:"staleness"`  // resource.ComputeStalenessScore at now
+}
+
+// Recommendations returns one recommendation per flagged resource ID,
+// categorised as in EstimatedMonthlySavings, sorted by monthly savings
+// descending, then by staleness descending. Resources of unknown cost are
+// kept with zero savings.
+func Recommendations(resources []resource.Resource, now time.Time) []Recommendation {
+	var recs []Recommendation
+	seen := make(map[string]bool)
+
+	for _, r := range resources {
+		category := wasteCategory(r)
+		if category == "" || seen[r.ID] {
+			continue
+		}
+		seen[r.ID] = true
+		monthly, ok := savings(r, category)
+		recs = append(recs, Recommendation{
+			ID:             r.ID,
+			Type:           r.Type,
+			Name:           r.Name,
+			Region:         r.Region,
+			Category:       category,
+			Reason:         wasteReason(r, category),
+			MonthlySavings: monthly,
+			CostKnown:      ok,
+			Staleness:      resource.ComputeStalenessScore(r, now),
+		})
+	}
+
+	slices.SortStableFunc(recs, func(a, b Recommendation) int {
+		return cmp.Or(
+			cmp.Compare(b.MonthlySavings, a.MonthlySavings),
+			cmp.Compare(b.Staleness, a.Staleness),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+	return recs
+}
+
+// wasteReason explains why r falls in category.
+func wasteReason(r resource.Resource, category string) string {
+	if rule := r.Attrs["waste_rule"]; rule != "" && r.Attrs["waste"] == category {
+		return "matched waste rule " + rule
+	}
+	if reason, ok := wasteReasons[category]; ok {
+		return reason
+	}
+	return category
+}
+
+// wasteCategory returns the waste category of r, or "" when it is not
+// flagged.
+func wasteCategory(r resource.Resource) string {
+	if w := r.Attrs["waste"]; w != "" {
+		return w
+	}
+	for _, signal := range wasteSignals {
+		if r.Attrs[signal] == "true" {
+			return signal
+		}
+	}
+	if r.Status == "unattached" || (r.Type == "ebs" && r.Attrs["attached"] == "false") {
+		return "unattached"
+	}
+	return ""
+}
+
+// savings returns what acting on r in category saves a month. Removing the
+// excess IOPS of an over-provisioned volume saves their price; anything else
+// saves the resource's cost.
+func savings(r resource.Resource, category string) (float64, bool) {
+	if category == "iops_overprovisioned" {
+		excess, err := strconv.ParseFloat(r.Attrs["iops_excess"], 64)
+		if err != nil {
+			return 0, false
+		}
+		return excess * provisionedIOPSMonth, true
+	}
+	return MonthlyCost(r)
+}
+
+// MonthlyCost returns the monthly USD cost of r: billed monthly_cost,
+// falling back to monthly_cost_estimate. ok is false when neither is set.
+func MonthlyCost(r resource.Resource) (monthly float64, ok bool) {
+	for _, key := range []string{"monthly_cost", "monthly_cost_estimate"} {
+		if v, err := strconv.ParseFloat(r.Attrs[key], 64); err == nil {
+			return v, true
+		}
+	}
+	return 0, false
+}
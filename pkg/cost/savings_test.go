@@ -0,0 +1,135 @@
+package cost
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yairfalse/elava/pkg/resource"
+)
+
+func TestEstimatedMonthlySavings(t *testing.T) {
+	resources := []resource.Resource{
+		// Orphaned and idle: counted once, as orphaned
+		{ID: "vol-1", Attrs: map[string]string{"orphaned": "true", "idle": "true", "monthly_cost_estimate": "40.00"}},
+		{ID: "i-1", Attrs: map[string]string{"idle": "true", "monthly_cost_estimate": "70.08"}},
+		// Billed cost wins over the estimate
+		{ID: "db-1", Attrs: map[string]string{"waste": "oversized", "idle": "true", "monthly_cost": "150.00", "monthly_cost_estimate": "194.00"}},
+		{ID: "eipalloc-1", Status: "unattached", Attrs: map[string]string{"monthly_cost_estimate": "3.65"}},
+		// Same ID seen again in another batch
+		{ID: "i-1", Attrs: map[string]string{"idle": "true", "monthly_cost_estimate": "70.08"}},
+		// Not waste, or no cost known
+		{ID: "i-2", Attrs: map[string]string{"idle": "false", "monthly_cost_estimate": "70.08"}},
+		{ID: "fn-1", Attrs: map[string]string{"idle": "true"}},
+	}
+
+	total, byCategory := EstimatedMonthlySavings(resources)
+
+	assert.InDelta(t, 263.73, total, 0.001)
+	assert.Len(t, byCategory, 4)
+	assert.InDelta(t, 40.00, byCategory["orphaned"], 0.001)
+	assert.InDelta(t, 70.08, byCategory["idle"], 0.001)
+	assert.InDelta(t, 150.00, byCategory["oversized"], 0.001)
+	assert.InDelta(t, 3.65, byCategory["unattached"], 0.001)
+}
+
+func TestEstimatedMonthlySavings_Empty(t *testing.T) {
+	total, byCategory := EstimatedMonthlySavings(nil)
+	assert.Zero(t, total)
+	assert.Empty(t, byCategory)
+}
+
+func TestRecommendations(t *testing.T) {
+	resources := []resource.Resource{
+		{ID: "vol-1", Type: "ebs", Region: "us-east-1", Attrs: map[string]string{"orphaned": "true", "monthly_cost_estimate": "40.00"}},
+		{ID: "db-1", Type: "rds", Attrs: map[string]string{"waste": "oversized", "waste_rule": "dev-rds-large", "monthly_cost": "150.00"}},
+		{ID: "fn-1", Type: "lambda", Attrs: map[string]string{"idle": "true"}},
+		{ID: "vol-1", Type: "ebs", Attrs: map[string]string{"orphaned": "true", "monthly_cost_estimate": "40.00"}},
+		{ID: "i-1", Type: "ec2", Attrs: map[string]string{"idle": "false", "monthly_cost_estimate": "70.08"}},
+	}
+
+	recs := Recommendations(resources, time.Now())
+
+	require.Len(t, recs, 3)
+	assert.Equal(t, "db-1", recs[0].ID)
+	assert.Equal(t, "oversized", recs[0].Category)
+	assert.Equal(t, "matched waste rule dev-rds-large", recs[0].Reason)
+	assert.InDelta(t, 150.00, recs[0].MonthlySavings, 0.001)
+
+	assert.Equal(t, Recommendation{
+		ID: "vol-1", Type: "ebs", Region: "us-east-1", Category: "orphaned",
+		Reason: "not attached to or used by anything", MonthlySavings: 40, CostKnown: true, Staleness: 60,
+	}, recs[1])
+
+	assert.Equal(t, "fn-1", recs[2].ID)
+	assert.False(t, recs[2].CostKnown)
+	assert.Zero(t, recs[2].MonthlySavings)
+}
+
+func TestRecommendations_TiesByStaleness(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	resources := []resource.Resource{
+		{ID: "a-new", Type: "ebs", CreatedAt: now, Labels: map[string]string{"owner": "alice"}, Attrs: map[string]string{"orphaned": "true"}},
+		{ID: "b-old", Type: "ebs", CreatedAt: now.AddDate(-1, 0, 0), Attrs: map[string]string{"orphaned": "true"}},
+	}
+
+	recs := Recommendations(resources, now)
+
+	require.Len(t, recs, 2)
+	assert.Equal(t, "b-old", recs[0].ID, "equal savings: the stalest first")
+	assert.Equal(t, 100, recs[0].Staleness)
+	assert.Equal(t, 30, recs[1].Staleness)
+}
+
+func TestEstimatedMonthlySavings_Signals(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        resource.Resource
+		category string
+		savings  float64
+	}{
+		{
+			name:     "unattached volume",
+			r:        resource.Resource{ID: "vol-1", Type: "ebs", Status: "available", Attrs: map[string]string{"attached": "false", "monthly_cost_estimate": "8.00"}},
+			category: "unattached",
+			savings:  8.00,
+		},
+		{
+			name:     "unmanaged snapshot",
+			r:        resource.Resource{ID: "snap-1", Type: "ebs_snapshot", Attrs: map[string]string{"unmanaged": "true", "monthly_cost_estimate": "5.00"}},
+			category: "unmanaged",
+			savings:  5.00,
+		},
+		{
+			name:     "obsolete AMI",
+			r:        resource.Resource{ID: "ami-1", Type: "ami", Attrs: map[string]string{"obsolete": "true", "monthly_cost_estimate": "2.50"}},
+			category: "obsolete",
+			savings:  2.50,
+		},
+		{
+			name:     "over-provisioned IOPS save the excess only",
+			r:        resource.Resource{ID: "vol-2", Type: "ebs", Attrs: map[string]string{"attached": "true", "iops_overprovisioned": "true", "iops_excess": "2000", "monthly_cost_estimate": "12.50"}},
+			category: "iops_overprovisioned",
+			savings:  130.00,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, byCategory := EstimatedMonthlySavings([]resource.Resource{tt.r})
+
+			assert.InDelta(t, tt.savings, total, 0.001)
+			assert.Equal(t, map[string]float64{tt.category: tt.savings}, byCategory)
+
+			recs := Recommendations([]resource.Resource{tt.r}, time.Now())
+			require.Len(t, recs, 1)
+			assert.Equal(t, wasteReasons[tt.category], recs[0].Reason)
+		})
+	}
+
+	attached := resource.Resource{ID: "vol-3", Type: "ebs", Status: "in-use", Attrs: map[string]string{"attached": "true", "monthly_cost_estimate": "8.00"}}
+	total, _ := EstimatedMonthlySavings([]resource.Resource{attached})
+	assert.Zero(t, total)
+}
@@ -0,0 +1,60 @@
+package resource
+
+import "strings"
+
+// matchAttrs lists, per resource type, the attrs that must agree for two
+// resources in different accounts to count as the same resource. Types not
+// listed match on type and name alone.
+var matchAttrs = map[string][]string{
+	"ec2":         {"instance_type"},
+	"rds":         {"engine", "instance_class"},
+	"ebs":         {"type", "size_gb"},
+	"lambda":      {"runtime"},
+	"elasticache": {"engine", "node_type"},
+	"memorydb":    {"engine", "node_type"},
+	"dynamodb":    {"billing_mode"},
+}
+
+// MatchKey returns an account-independent key for r built from its type,
+// normalized name and the type's match attrs. IDs, ARNs, accounts and regions
+// are ignored since they differ between accounts.
+func MatchKey(r Resource) string {
+	parts := []string{r.Type, strings.ToLower(strings.TrimSpace(r.Name))}
+	for _, attr := range matchAttrs[r.Type] {
+		parts = append(parts, attr+"="+r.Attrs[attr])
+	}
+	return strings.Join(parts, "|")
+}
+
+// CompareInventories returns the resources of a with no match in b and the
+// resources of b with no match in a, matched by MatchKey. Duplicate keys are
+// paired one to one, so two matching resources in a and one in b leave one
+// unmatched in a. Results keep their input order.
+func CompareInventories(a, b []Resource) (onlyA, onlyB []Resource) {
+	remaining := make(map[string]int)
+	for _, r := range b {
+		remaining[MatchKey(r)]++
+	}
+
+	matched := make(map[string]int)
+	for _, r := range a {
+		key := MatchKey(r)
+		if remaining[key] > 0 {
+			remaining[key]--
+			matched[key]++
+			continue
+		}
+		onlyA = append(onlyA, r)
+	}
+
+	for _, r := range b {
+		key := MatchKey(r)
+		if matched[key] > 0 {
+			matched[key]--
+			continue
+		}
+		onlyB = append(onlyB, r)
+	}
+
+	return onlyA, onlyB
+}
@@ -0,0 +1,59 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareInventories(t *testing.T) {
+	a := []Resource{
+		{ID: "i-aaa1", Type: "ec2", Account: "111", Name: "web", Attrs: map[string]string{"instance_type": "t3.large"}},
+		{ID: "i-aaa2", Type: "ec2", Account: "111", Name: "worker", Attrs: map[string]string{"instance_type": "m5.xlarge"}},
+		{ID: "prod-db", Type: "rds", Account: "111", Name: "prod-db", Attrs: map[string]string{"engine": "postgres", "instance_class": "db.r5.large"}},
+		{ID: "arn:aws:sqs:us-east-1:111:jobs", Type: "sqs", Account: "111", Name: "jobs"},
+		{ID: "arn:aws:sqs:us-east-1:111:legacy", Type: "sqs", Account: "111", Name: "legacy"},
+	}
+	b := []Resource{
+		{ID: "i-bbb1", Type: "ec2", Account: "222", Region: "eu-west-1", Name: "Web", Attrs: map[string]string{"instance_type": "t3.large"}},
+		{ID: "i-bbb2", Type: "ec2", Account: "222", Name: "worker", Attrs: map[string]string{"instance_type": "m5.large"}},
+		{ID: "prod-db", Type: "rds", Account: "222", Name: "prod-db", Attrs: map[string]string{"engine": "postgres", "instance_class": "db.r5.large"}},
+		{ID: "arn:aws:sqs:us-east-1:222:jobs", Type: "sqs", Account: "222", Name: "jobs"},
+		{ID: "arn:aws:sns:us-east-1:222:alerts", Type: "sns", Account: "222", Name: "alerts"},
+	}
+
+	onlyA, onlyB := CompareInventories(a, b)
+
+	assert.Equal(t, []string{"i-aaa2", "arn:aws:sqs:us-east-1:111:legacy"}, ids(onlyA))
+	assert.Equal(t, []string{"i-bbb2", "arn:aws:sns:us-east-1:222:alerts"}, ids(onlyB))
+}
+
+func TestCompareInventories_Duplicates(t *testing.T) {
+	a := []Resource{
+		{ID: "q1", Type: "sqs", Name: "jobs"},
+		{ID: "q2", Type: "sqs", Name: "jobs"},
+	}
+	b := []Resource{{ID: "q3", Type: "sqs", Name: "jobs"}}
+
+	onlyA, onlyB := CompareInventories(a, b)
+
+	assert.Equal(t, []string{"q2"}, ids(onlyA))
+	assert.Empty(t, onlyB)
+}
+
+func TestCompareInventories_Identical(t *testing.T) {
+	inv := []Resource{{ID: "i-1", Type: "ec2", Name: "web"}, {ID: "b", Type: "s3", Name: "logs"}}
+
+	onlyA, onlyB := CompareInventories(inv, inv)
+
+	assert.Empty(t, onlyA)
+	assert.Empty(t, onlyB)
+}
+
+func ids(resources []Resource) []string {
+	var out []string
+	for _, r := range resources {
+		out = append(out, r.ID)
+	}
+	return out
+}
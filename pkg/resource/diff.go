@@ -1,6 +1,8 @@
 // Package resource provides resource types and diffing.
 package resource
 
+import "time"
+
 // DiffType represents the type of change detected.
 type DiffType string
 
@@ -11,8 +13,38 @@ const (
 	DiffDeleted DiffType = "deleted"
 	// DiffModified indicates a resource's properties changed.
 	DiffModified DiffType = "modified"
+	// DiffSecurityRuleModified indicates a security group's rules changed.
+	DiffSecurityRuleModified DiffType = "security_rule_modified"
+	// DiffReappeared indicates a resource that was deleted is back, e.g.
+	// recreated with the same ID.
+	DiffReappeared DiffType = "reappeared"
 )
 
+// Severity ranks how urgent a detected change is.
+type Severity int
+
+const (
+	// SeverityInfo is a cosmetic or routine change.
+	SeverityInfo Severity = iota
+	// SeverityWarning is a change to ownership or environment tags.
+	SeverityWarning
+	// SeverityCritical is a change that widens exposure, e.g. a security
+	// group opened to the internet or a resource made public.
+	SeverityCritical
+)
+
+// String returns "info", "warning" or "critical".
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "info"
+	}
+}
+
 // Change represents a single field change.
 // The field name is the map key in ResourceDiff.Changes.
 type Change struct {
@@ -26,6 +58,8 @@ type ResourceDiff struct {
 	Resource Resource
 	Previous *Resource         // nil for added resources
 	Changes  map[string]Change // field name → change details
+	Severity Severity
+	Absent   time.Duration // how long a reappeared resource was gone
 }
 
 // ResourceKey returns a unique key for identifying a resource across scans.
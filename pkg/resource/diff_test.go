@@ -40,4 +40,5 @@ func TestDiffType_Constants(t *testing.T) {
 	assert.Equal(t, DiffType("added"), DiffAdded)
 	assert.Equal(t, DiffType("deleted"), DiffDeleted)
 	assert.Equal(t, DiffType("modified"), DiffModified)
+	assert.Equal(t, DiffType("security_rule_modified"), DiffSecurityRuleModified)
 }
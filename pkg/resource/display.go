@@ -0,0 +1,71 @@
+package resource
+
+import "strings"
+
+// DisplayID returns a shortened ID suitable for display. ARNs are reduced to
+// their resource portion, dropping the partition, service, region, and account.
+// Non-ARN IDs are returned unchanged. ID itself stays canonical.
+func (r Resource) DisplayID() string {
+	parts := strings.SplitN(r.ID, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[5] == "" {
+		return r.ID
+	}
+	return parts[5]
+}
+
+// typeDisplayNames are friendly names for resource types in reports.
+var typeDisplayNames = map[string]string{
+	"ec2":              "EC2 Instance",
+	"rds":              "RDS Instance",
+	"rds_snapshot":     "RDS Snapshot",
+	"elb":              "Load Balancer",
+	"target_group":     "Target Group",
+	"eks":              "EKS Cluster",
+	"asg":              "Auto Scaling Group",
+	"lambda":           "Lambda Function",
+	"vpc":              "VPC",
+	"subnet":           "Subnet",
+	"vpc_endpoint":     "VPC Endpoint",
+	"internet_gateway": "Internet Gateway",
+	"route_table":      "Route Table",
+	"transit_gateway":  "Transit Gateway",
+	"security_group":   "Security Group",
+	"dynamodb":         "DynamoDB Table",
+	"sqs":              "SQS Queue",
+	"ebs":              "EBS Volume",
+	"ebs_snapshot":     "EBS Snapshot",
+	"ami":              "AMI",
+	"eip":              "Elastic IP",
+	"nat_gateway":      "NAT Gateway",
+	"ecs":              "ECS Cluster",
+	"cloudwatch_logs":  "Log Group",
+	"sns":              "SNS Topic",
+	"elasticache":      "ElastiCache Cluster",
+	"memorydb":         "MemoryDB Cluster",
+	"secretsmanager":   "Secret",
+	"acm":              "ACM Certificate",
+	"apigateway":       "API Gateway",
+	"kinesis":          "Kinesis Stream",
+	"redshift":         "Redshift Cluster",
+	"stepfunctions":    "State Machine",
+	"glue_database":    "Glue Database",
+	"opensearch":       "OpenSearch Domain",
+	"msk":              "MSK Cluster",
+	"emr":              "EMR Cluster",
+	"workspace":        "WorkSpace",
+	"ram_share":        "RAM Share",
+	"ecr":              "ECR Repository",
+	"s3":               "S3 Bucket",
+	"iam_role":         "IAM Role",
+	"route53":          "Hosted Zone",
+	"cloudfront":       "CloudFront Distribution",
+}
+
+// TypeDisplayName returns a friendly name for the resource type, e.g.
+// "EC2 Instance". Unknown types are returned unchanged.
+func (r Resource) TypeDisplayName() string {
+	if name, ok := typeDisplayNames[r.Type]; ok {
+		return name
+	}
+	return r.Type
+}
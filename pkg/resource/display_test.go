@@ -0,0 +1,36 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisplayID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{"plain id", "i-abc123", "i-abc123"},
+		{"sns arn", "arn:aws:sns:us-east-1:123456789012:alerts", "alerts"},
+		{"elb arn", "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/50dc6c495c0c9188", "loadbalancer/app/web/50dc6c495c0c9188"},
+		{"global arn", "arn:aws:iam::123456789012:role/admin", "role/admin"},
+		{"resource with colons", "arn:aws:logs:us-east-1:123456789012:log-group:/aws/lambda/fn", "log-group:/aws/lambda/fn"},
+		{"truncated arn", "arn:aws:s3", "arn:aws:s3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Resource{ID: tt.id}
+			assert.Equal(t, tt.want, r.DisplayID())
+			assert.Equal(t, tt.id, r.ID)
+		})
+	}
+}
+
+func TestTypeDisplayName(t *testing.T) {
+	assert.Equal(t, "EC2 Instance", Resource{Type: "ec2"}.TypeDisplayName())
+	assert.Equal(t, "Glue Database", Resource{Type: "glue_database"}.TypeDisplayName())
+	assert.Equal(t, "custom", Resource{Type: "custom"}.TypeDisplayName())
+}
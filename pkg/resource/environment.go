@@ -0,0 +1,71 @@
+package resource
+
+import "strings"
+
+// environmentLabels are tag keys (case-insensitive) that declare an environment.
+var environmentLabels = []string{"environment", "env", "stage"}
+
+// environmentAliases maps tag values and name tokens to a canonical environment.
+var environmentAliases = map[string]string{
+	"prod":        "prod",
+	"prd":         "prod",
+	"production":  "prod",
+	"live":        "prod",
+	"staging":     "staging",
+	"stage":       "staging",
+	"stg":         "staging",
+	"preprod":     "staging",
+	"dev":         "dev",
+	"develop":     "dev",
+	"development": "dev",
+	"sandbox":     "dev",
+	"test":        "test",
+	"testing":     "test",
+	"qa":          "test",
+	"uat":         "test",
+}
+
+// TagEnvironment returns the canonical environment declared by the
+// environment, env or stage tag, or "" if none is set or recognized.
+func (r Resource) TagEnvironment() string {
+	for k, v := range r.Labels {
+		for _, key := range environmentLabels {
+			if strings.EqualFold(k, key) {
+				return environmentAliases[strings.ToLower(strings.TrimSpace(v))]
+			}
+		}
+	}
+	return ""
+}
+
+// NameEnvironment infers the canonical environment from tokens in the
+// resource name, e.g. "test-foo" or "api_prod_db". Returns "" when no token
+// is recognized or the name mentions more than one environment.
+func (r Resource) NameEnvironment() string {
+	tokens := strings.FieldsFunc(strings.ToLower(r.Name), func(c rune) bool {
+		return !('a' <= c && c <= 'z' || '0' <= c && c <= '9')
+	})
+
+	env := ""
+	for _, tok := range tokens {
+		e, ok := environmentAliases[tok]
+		if !ok {
+			continue
+		}
+		if env != "" && env != e {
+			return ""
+		}
+		env = e
+	}
+	return env
+}
+
+// EnvironmentConflict reports whether the tagged environment disagrees with
+// the environment inferred from the name. ok is false unless both are known.
+func (r Resource) EnvironmentConflict() (tagEnv, nameEnv string, conflict, ok bool) {
+	tagEnv, nameEnv = r.TagEnvironment(), r.NameEnvironment()
+	if tagEnv == "" || nameEnv == "" {
+		return tagEnv, nameEnv, false, false
+	}
+	return tagEnv, nameEnv, tagEnv != nameEnv, true
+}
@@ -0,0 +1,39 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironmentConflict(t *testing.T) {
+	tests := []struct {
+		name         string
+		r            Resource
+		wantConflict bool
+		wantOK       bool
+	}{
+		{"prod tag on test name", Resource{Name: "test-foo", Labels: map[string]string{"Environment": "prod"}}, true, true},
+		{"dev tag on production name", Resource{Name: "api_production_db", Labels: map[string]string{"env": "Development"}}, true, true},
+		{"consistent", Resource{Name: "prod-api", Labels: map[string]string{"Environment": "production"}}, false, true},
+		{"alias on both sides", Resource{Name: "orders-stg", Labels: map[string]string{"Stage": "staging"}}, false, true},
+		{"no tag", Resource{Name: "test-foo"}, false, false},
+		{"no env in name", Resource{Name: "orders-api", Labels: map[string]string{"Environment": "prod"}}, false, false},
+		{"unknown tag value", Resource{Name: "test-foo", Labels: map[string]string{"Environment": "blue"}}, false, false},
+		{"ambiguous name", Resource{Name: "prod-to-dev-sync", Labels: map[string]string{"Environment": "prod"}}, false, false},
+		{"substring is not a token", Resource{Name: "production-contest", Labels: map[string]string{"Environment": "prod"}}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, conflict, ok := tt.r.EnvironmentConflict()
+			assert.Equal(t, tt.wantConflict, conflict)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestNameEnvironment(t *testing.T) {
+	assert.Equal(t, "test", Resource{Name: "test-foo"}.NameEnvironment())
+	assert.Equal(t, "prod", Resource{Name: "Payments.PRD.Queue"}.NameEnvironment())
+	assert.Equal(t, "", Resource{Name: "latest"}.NameEnvironment())
+}
@@ -0,0 +1,78 @@
+package resource
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"slices"
+)
+
+// volatileAttrs are attrs that move between scans without the resource
+// changing: usage metrics, queue depths, ages counted in days and billed
+// cost. They are left out of change detection.
+var volatileAttrs = []string{
+	"messages_available", "messages_in_flight", // SQS queue depth
+	"max_connections", "cache_hits", "request_count", "new_flow_count", // CloudWatch idle metrics
+	"items", "size_bytes", "stored_bytes", // DynamoDB and log group usage
+	"tasks_running", "tasks_pending", "active_steps", "normalized_instance_hours",
+	"role_last_used", "last_connected", "connection_state",
+	"age_days", "running_days", "untagged_days", "days_since_modified",
+	"monthly_cost", "schedule_savings",
+}
+
+// IsVolatileAttr reports whether the attr key changes between scans on its
+// own and is ignored by change detection.
+func IsVolatileAttr(key string) bool {
+	return slices.Contains(volatileAttrs, key)
+}
+
+// StableAttrs returns the attrs without volatile ones, i.e. those compared
+// for change detection.
+func (r Resource) StableAttrs() map[string]string {
+	if r.Attrs == nil {
+		return nil
+	}
+	attrs := make(map[string]string, len(r.Attrs))
+	for k, v := range r.Attrs {
+		if !IsVolatileAttr(k) {
+			attrs[k] = v
+		}
+	}
+	return attrs
+}
+
+// ContentHash returns a deterministic hash of the fields that matter for change
+// detection: name, status, labels and stable attrs. Identity fields (ID,
+// provider, region, account) are covered by ResourceKey, and ScannedAt and
+// volatile attrs are excluded because they change on every scan.
+// Map keys are sorted, so the hash is stable across runs and map ordering.
+func (r Resource) ContentHash() string {
+	h := sha256.New()
+	writeField(h, r.Name)
+	writeField(h, r.Status)
+	writeMap(h, r.Labels)
+	writeMap(h, r.StableAttrs())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeField writes a length-prefixed string so adjacent fields can't collide.
+func writeField(h hash.Hash, s string) {
+	_, _ = fmt.Fprintf(h, "%d:%s", len(s), s)
+}
+
+// writeMap writes map entries in sorted key order.
+func writeMap(h hash.Hash, m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	_, _ = fmt.Fprintf(h, "%d{", len(keys))
+	for _, k := range keys {
+		writeField(h, k)
+		writeField(h, m[k])
+	}
+	_, _ = h.Write([]byte("}"))
+}
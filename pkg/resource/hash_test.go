@@ -0,0 +1,110 @@
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newHashResource() Resource {
+	return Resource{
+		ID:        "i-abc123",
+		Type:      "ec2",
+		Provider:  "aws",
+		Region:    "us-east-1",
+		Account:   "123456789012",
+		Name:      "web-1",
+		Status:    "running",
+		Labels:    map[string]string{"env": "prod", "team": "web", "owner": "alice"},
+		Attrs:     map[string]string{"instance_type": "t3.micro", "az": "us-east-1a"},
+		ScannedAt: time.Now(),
+	}
+}
+
+func TestContentHash_Identical(t *testing.T) {
+	r1 := newHashResource()
+	r2 := newHashResource()
+	r2.ScannedAt = r1.ScannedAt.Add(5 * time.Minute) // scan time is not content
+
+	assert.Equal(t, r1.ContentHash(), r2.ContentHash())
+}
+
+func TestContentHash_StableAcrossMapOrdering(t *testing.T) {
+	r1 := newHashResource()
+
+	// Rebuild the maps with a different insertion order
+	r2 := newHashResource()
+	r2.Labels = map[string]string{}
+	for _, k := range []string{"team", "owner", "env"} {
+		r2.Labels[k] = r1.Labels[k]
+	}
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, r1.ContentHash(), r2.ContentHash())
+	}
+}
+
+func TestContentHash_ChangedField(t *testing.T) {
+	base := newHashResource()
+
+	tests := []struct {
+		name   string
+		mutate func(r *Resource)
+	}{
+		{"status", func(r *Resource) { r.Status = "stopped" }},
+		{"name", func(r *Resource) { r.Name = "web-2" }},
+		{"label value", func(r *Resource) { r.Labels["env"] = "dev" }},
+		{"label added", func(r *Resource) { r.Labels["cost-center"] = "42" }},
+		{"attr value", func(r *Resource) { r.Attrs["instance_type"] = "t3.large" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newHashResource()
+			tt.mutate(&r)
+			assert.NotEqual(t, base.ContentHash(), r.ContentHash())
+		})
+	}
+}
+
+func TestContentHash_NoFieldCollision(t *testing.T) {
+	// Moving characters between adjacent fields must change the hash
+	r1 := Resource{Name: "ab", Status: "c"}
+	r2 := Resource{Name: "a", Status: "bc"}
+	assert.NotEqual(t, r1.ContentHash(), r2.ContentHash())
+
+	// Labels and attrs are hashed separately
+	r3 := Resource{Labels: map[string]string{"k": "v"}}
+	r4 := Resource{Attrs: map[string]string{"k": "v"}}
+	assert.NotEqual(t, r3.ContentHash(), r4.ContentHash())
+}
+
+func TestContentHash_NilAndEmptyMapsEqual(t *testing.T) {
+	// Matches maps.Equal semantics used by the diff tracker
+	r1 := Resource{Status: "running"}
+	r2 := Resource{Status: "running", Labels: map[string]string{}, Attrs: map[string]string{}}
+	assert.Equal(t, r1.ContentHash(), r2.ContentHash())
+}
+
+func TestContentHash_IgnoresVolatileAttrs(t *testing.T) {
+	r1 := newHashResource()
+	r2 := newHashResource()
+	r1.Attrs["messages_available"] = "3"
+	r2.Attrs["messages_available"] = "1200"
+	r2.Attrs["age_days"] = "41"
+
+	assert.Equal(t, r1.ContentHash(), r2.ContentHash())
+
+	// ELB traffic, recorded with idle detection
+	for _, key := range []string{"request_count", "new_flow_count"} {
+		r1.Attrs[key] = "0"
+		r2.Attrs[key] = "52000"
+		assert.Equal(t, r1.ContentHash(), r2.ContentHash(), key)
+	}
+
+	// WorkSpace sessions come and go with user logins
+	r1.Attrs["connection_state"] = "CONNECTED"
+	r2.Attrs["connection_state"] = "DISCONNECTED"
+	assert.Equal(t, r1.ContentHash(), r2.ContentHash())
+}
@@ -0,0 +1,64 @@
+package resource
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// ownershipLabels are tag keys (case-insensitive) that say who a resource
+// belongs to. Project counts too: it is enough to route a cleanup question.
+var ownershipLabels = []string{"owner", "elava:owner", "team", "project"}
+
+// HasOwnershipTag reports whether an owner, elava:owner, team or project tag
+// is set to a non-empty value.
+func (r Resource) HasOwnershipTag() bool {
+	for k, v := range r.Labels {
+		if v != "" && slices.Contains(ownershipLabels, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// AttrTagsUnknown is set to "true" by scanners that could not read a
+// resource's tags, e.g. because listing them needs a separate API call.
+const AttrTagsUnknown = "tags_unknown"
+
+// TagsUnknown reports whether the resource's tags were not read, so a
+// missing ownership tag says nothing about it.
+func (r Resource) TagsUnknown() bool {
+	return r.Attrs[AttrTagsUnknown] == "true"
+}
+
+// Label returns the value of the tag key, matched case-insensitively, or ""
+// when it is not set.
+func (r Resource) Label(key string) string {
+	for k, v := range r.Labels {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+// Owner returns the owner or elava:owner tag, falling back to the team tag,
+// or "" when none is set.
+func (r Resource) Owner() string {
+	for _, key := range []string{"owner", "elava:owner", "team"} {
+		if v := r.Label(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Untagged reports whether the resource has no ownership tag although it was
+// created more than grace ago, i.e. it had time to be tagged. Resources with
+// an unknown creation time or unknown tags are never untagged.
+func (r Resource) Untagged(now time.Time, grace time.Duration) bool {
+	if r.CreatedAt.IsZero() || r.TagsUnknown() || r.HasOwnershipTag() {
+		return false
+	}
+	return now.Sub(r.CreatedAt) > grace
+}
@@ -0,0 +1,41 @@
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasOwnershipTag(t *testing.T) {
+	assert.True(t, Resource{Labels: map[string]string{"Team": "platform"}}.HasOwnershipTag())
+	assert.True(t, Resource{Labels: map[string]string{"elava:owner": "alice"}}.HasOwnershipTag())
+	assert.True(t, Resource{Labels: map[string]string{"Project": "checkout"}}.HasOwnershipTag())
+	assert.False(t, Resource{Labels: map[string]string{"Owner": ""}}.HasOwnershipTag(), "empty value")
+	assert.False(t, Resource{Labels: map[string]string{"Name": "web"}}.HasOwnershipTag())
+	assert.False(t, Resource{}.HasOwnershipTag())
+}
+
+func TestUntagged(t *testing.T) {
+	now := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	grace := 3 * 24 * time.Hour
+
+	assert.True(t, Resource{CreatedAt: now.AddDate(0, 0, -5)}.Untagged(now, grace))
+	assert.False(t, Resource{CreatedAt: now.Add(-time.Hour)}.Untagged(now, grace), "still in grace period")
+	assert.False(t, Resource{CreatedAt: now.AddDate(0, 0, -5), Labels: map[string]string{"team": "data"}}.Untagged(now, grace))
+	assert.False(t, Resource{}.Untagged(now, grace), "unknown age")
+	assert.False(t, Resource{CreatedAt: now.AddDate(0, 0, -5), Attrs: map[string]string{AttrTagsUnknown: "true"}}.Untagged(now, grace), "tags not read")
+}
+
+func TestLabel(t *testing.T) {
+	r := Resource{Labels: map[string]string{"Team": "platform"}}
+	assert.Equal(t, "platform", r.Label("team"))
+	assert.Empty(t, r.Label("owner"))
+}
+
+func TestOwner(t *testing.T) {
+	assert.Equal(t, "alice", Resource{Labels: map[string]string{"Owner": "alice", "team": "data"}}.Owner())
+	assert.Equal(t, "bob", Resource{Labels: map[string]string{"elava:owner": "bob"}}.Owner())
+	assert.Equal(t, "data", Resource{Labels: map[string]string{"Owner": "", "Team": "data"}}.Owner(), "falls back to team")
+	assert.Empty(t, Resource{Labels: map[string]string{"Project": "checkout"}}.Owner())
+}
@@ -15,6 +15,7 @@ type Resource struct {
 	Status    string            `json:"status"`     // Current status (e.g., "running")
 	Labels    map[string]string `json:"labels"`     // Normalized labels/tags
 	Attrs     map[string]string `json:"attrs"`      // Provider-specific attributes
+	CreatedAt time.Time         `json:"created_at"` // When the resource was created (zero if unknown)
 	ScannedAt time.Time         `json:"scanned_at"` // When this was scanned
 }
 
@@ -25,4 +26,5 @@ type ScanResult struct {
 	Resources []Resource
 	Duration  time.Duration
 	Error     error
+	Streamed  bool // Resources were delivered via EmitResource and are nil here
 }
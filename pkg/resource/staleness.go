@@ -0,0 +1,57 @@
+package resource
+
+import "time"
+
+// Staleness score weights. They sum to 100.
+const (
+	// stalenessAgeWeight scales linearly with age, reaching its maximum at stalenessAgeCap.
+	stalenessAgeWeight = 40
+	// stalenessOwnerWeight applies when no ownership tag is present.
+	stalenessOwnerWeight = 30
+	// stalenessIdleWeight applies when any idle signal is set.
+	stalenessIdleWeight = 30
+
+	stalenessAgeCap = 365 * 24 * time.Hour
+)
+
+// idleAttrs are attrs set to "true" by scanners when a resource looks unused.
+var idleAttrs = []string{"idle", "orphaned", "redundant", "overdue", "unused", "stale", "obsolete", "unmanaged"}
+
+// ComputeStalenessScore returns a 0-100 score of how likely the resource is
+// forgotten. Stateless scans have no last-change time, so age is measured
+// from CreatedAt:
+//
+//   - age: up to 40 points, linear over the first year (unknown age scores 0)
+//   - ownership: 30 points when no ownership tag is set (see HasOwnershipTag)
+//     and the tags are known
+//   - activity: 30 points when any idle signal is set (idle, orphaned,
+//     redundant, overdue, unused, stale, obsolete, unmanaged, or status
+//     "unattached")
+func ComputeStalenessScore(r Resource, now time.Time) int {
+	score := 0
+
+	if !r.CreatedAt.IsZero() {
+		age := min(max(now.Sub(r.CreatedAt), 0), stalenessAgeCap)
+		score += int(stalenessAgeWeight * age / stalenessAgeCap)
+	}
+	if !r.TagsUnknown() && !r.HasOwnershipTag() {
+		score += stalenessOwnerWeight
+	}
+	if isIdle(r) {
+		score += stalenessIdleWeight
+	}
+
+	return score
+}
+
+func isIdle(r Resource) bool {
+	if r.Status == "unattached" {
+		return true
+	}
+	for _, k := range idleAttrs {
+		if r.Attrs[k] == "true" {
+			return true
+		}
+	}
+	return false
+}
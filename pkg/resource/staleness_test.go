@@ -0,0 +1,53 @@
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeStalenessScore_Monotonic(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	owned := map[string]string{"Owner": "alice"}
+
+	// Each sample worsens one signal relative to the previous one
+	samples := []struct {
+		name string
+		r    Resource
+	}{
+		{"new, owned, active", Resource{CreatedAt: now, Labels: owned}},
+		{"month old", Resource{CreatedAt: now.AddDate(0, -1, 0), Labels: owned}},
+		{"year old", Resource{CreatedAt: now.AddDate(-1, 0, 0), Labels: owned}},
+		{"year old, unowned", Resource{CreatedAt: now.AddDate(-1, 0, 0)}},
+		{"year old, unowned, idle", Resource{CreatedAt: now.AddDate(-1, 0, 0), Attrs: map[string]string{"idle": "true"}}},
+	}
+
+	prev := -1
+	for _, s := range samples {
+		score := ComputeStalenessScore(s.r, now)
+		assert.Greater(t, score, prev, s.name)
+		assert.GreaterOrEqual(t, score, 0, s.name)
+		assert.LessOrEqual(t, score, 100, s.name)
+		prev = score
+	}
+	assert.Equal(t, 100, prev)
+}
+
+func TestComputeStalenessScore_Signals(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 0, ComputeStalenessScore(Resource{CreatedAt: now, Labels: map[string]string{"team": "platform"}}, now))
+	assert.Equal(t, 30, ComputeStalenessScore(Resource{}, now), "unknown age, unowned")
+	assert.Equal(t, 30, ComputeStalenessScore(Resource{Labels: map[string]string{"owner": ""}}, now), "empty owner tag")
+	assert.Equal(t, 0, ComputeStalenessScore(Resource{Labels: map[string]string{"elava:owner": "alice"}}, now), "same tags as HasOwnershipTag")
+	assert.Equal(t, 0, ComputeStalenessScore(Resource{Labels: map[string]string{"Project": "billing"}}, now))
+	assert.Equal(t, 40, ComputeStalenessScore(Resource{CreatedAt: now.AddDate(-3, 0, 0), Labels: map[string]string{"owner": "bob"}}, now), "age is capped")
+	assert.Equal(t, 60, ComputeStalenessScore(Resource{Status: "unattached"}, now))
+	assert.Equal(t, 60, ComputeStalenessScore(Resource{Attrs: map[string]string{"orphaned": "true"}}, now))
+	assert.Equal(t, 30, ComputeStalenessScore(Resource{Attrs: map[string]string{"idle": "false"}}, now))
+	for _, signal := range []string{"unused", "stale", "obsolete", "unmanaged"} {
+		assert.Equal(t, 60, ComputeStalenessScore(Resource{Attrs: map[string]string{signal: "true"}}, now), signal)
+	}
+	assert.Equal(t, 0, ComputeStalenessScore(Resource{Attrs: map[string]string{AttrTagsUnknown: "true"}}, now), "tags not read")
+}
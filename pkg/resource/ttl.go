@@ -0,0 +1,36 @@
+package resource
+
+import (
+	"fmt"
+	"time"
+)
+
+// TTLLabel is the tag declaring a resource's expected lifetime (e.g. "72h").
+const TTLLabel = "elava:ttl"
+
+// TTL returns the expected lifetime declared by the TTLLabel tag. ok is false
+// when the tag is absent; err is set when it is not a positive duration.
+func (r Resource) TTL() (ttl time.Duration, ok bool, err error) {
+	v, ok := r.Labels[TTLLabel]
+	if !ok {
+		return 0, false, nil
+	}
+	ttl, err = time.ParseDuration(v)
+	if err != nil {
+		return 0, true, fmt.Errorf("parse %s: %w", TTLLabel, err)
+	}
+	if ttl <= 0 {
+		return 0, true, fmt.Errorf("parse %s: must be positive, got %q", TTLLabel, v)
+	}
+	return ttl, true, nil
+}
+
+// Overdue reports whether the resource has outlived its declared TTL at now.
+// Resources without a valid TTL or a known creation time are never overdue.
+func (r Resource) Overdue(now time.Time) bool {
+	ttl, ok, err := r.TTL()
+	if !ok || err != nil || r.CreatedAt.IsZero() {
+		return false
+	}
+	return now.After(r.CreatedAt.Add(ttl))
+}
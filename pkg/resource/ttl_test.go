@@ -0,0 +1,63 @@
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		want    time.Duration
+		wantOK  bool
+		wantErr bool
+	}{
+		{"absent", map[string]string{"env": "dev"}, 0, false, false},
+		{"hours", map[string]string{TTLLabel: "72h"}, 72 * time.Hour, true, false},
+		{"compound", map[string]string{TTLLabel: "1h30m"}, 90 * time.Minute, true, false},
+		{"malformed", map[string]string{TTLLabel: "3 days"}, 0, true, true},
+		{"zero", map[string]string{TTLLabel: "0s"}, 0, true, true},
+		{"negative", map[string]string{TTLLabel: "-1h"}, 0, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ttl, ok, err := Resource{Labels: tt.labels}.TTL()
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ttl)
+		})
+	}
+}
+
+func TestOverdue(t *testing.T) {
+	created := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := created.Add(96 * time.Hour)
+
+	tests := []struct {
+		name    string
+		ttl     string
+		created time.Time
+		want    bool
+	}{
+		{"overdue", "72h", created, true},
+		{"not yet overdue", "168h", created, false},
+		{"malformed", "soon", created, false},
+		{"unknown creation time", "72h", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Resource{Labels: map[string]string{TTLLabel: tt.ttl}, CreatedAt: tt.created}
+			assert.Equal(t, tt.want, r.Overdue(now))
+		})
+	}
+}
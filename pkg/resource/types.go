@@ -0,0 +1,77 @@
+package resource
+
+import "strings"
+
+// DefaultTypeAliases maps alternative names users give resource types to the
+// canonical type used by scanners and filters.
+var DefaultTypeAliases = map[string]string{
+	"instance":           "ec2",
+	"ec2_instance":       "ec2",
+	"db":                 "rds",
+	"rds_instance":       "rds",
+	"alb":                "elb",
+	"nlb":                "elb",
+	"load_balancer":      "elb",
+	"volume":             "ebs",
+	"ebs_volume":         "ebs",
+	"snapshot":           "ebs_snapshot",
+	"image":              "ami",
+	"bucket":             "s3",
+	"function":           "lambda",
+	"sg":                 "security_group",
+	"autoscaling":        "asg",
+	"auto_scaling_group": "asg",
+	"elastic_ip":         "eip",
+	"nat":                "nat_gateway",
+	"endpoint":           "vpc_endpoint",
+	"igw":                "internet_gateway",
+	"tgw":                "transit_gateway",
+	"queue":              "sqs",
+	"topic":              "sns",
+	"table":              "dynamodb",
+	"redis":              "elasticache",
+	"secret":             "secretsmanager",
+	"certificate":        "acm",
+	"api_gateway":        "apigateway",
+	"stream":             "kinesis",
+	"state_machine":      "stepfunctions",
+	"glue_database":      "glue",
+	"elasticsearch":      "opensearch",
+	"kafka":              "msk",
+	"log_group":          "cloudwatch_logs",
+	"role":               "iam_role",
+	"hosted_zone":        "route53",
+	"distribution":       "cloudfront",
+	"repository":         "ecr",
+}
+
+// TypeAliases resolves resource type aliases to canonical types.
+// Lookups are case-insensitive.
+type TypeAliases map[string]string
+
+// NewTypeAliases returns DefaultTypeAliases merged with extra. Entries in
+// extra take precedence, so config can add aliases or redirect defaults.
+func NewTypeAliases(extra map[string]string) TypeAliases {
+	a := make(TypeAliases, len(DefaultTypeAliases)+len(extra))
+	for alias, typ := range DefaultTypeAliases {
+		a[alias] = typ
+	}
+	for alias, typ := range extra {
+		a[normalizeType(alias)] = normalizeType(typ)
+	}
+	return a
+}
+
+// Canonical returns the canonical type for name. Names that are not aliases
+// are returned lowercased, so canonical types resolve to themselves.
+func (a TypeAliases) Canonical(name string) string {
+	name = normalizeType(name)
+	if typ, ok := a[name]; ok {
+		return typ
+	}
+	return name
+}
+
+func normalizeType(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
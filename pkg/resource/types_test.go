@@ -0,0 +1,28 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeAliases_Canonical(t *testing.T) {
+	a := NewTypeAliases(map[string]string{"VM": "EC2", "alb": "target_group"})
+
+	assert.Equal(t, "ec2", a.Canonical("instance"))
+	assert.Equal(t, "ec2", a.Canonical("vm"), "config alias")
+	assert.Equal(t, "target_group", a.Canonical("ALB"), "config overrides default")
+	assert.Equal(t, "elb", a.Canonical("nlb"))
+	assert.Equal(t, "rds", a.Canonical(" RDS "), "canonical type resolves to itself")
+	assert.Equal(t, "unknown", a.Canonical("unknown"))
+	assert.Equal(t, "elb", DefaultTypeAliases["alb"], "defaults are not modified")
+}
+
+func TestDefaultTypeAliases_DisplayNames(t *testing.T) {
+	for alias, typ := range DefaultTypeAliases {
+		if typ == "glue" {
+			continue // scanner name; its resources are typed glue_database
+		}
+		assert.NotEqual(t, typ, Resource{Type: typ}.TypeDisplayName(), "alias %q targets %q which has no display name", alias, typ)
+	}
+}